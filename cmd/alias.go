@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+	"github.com/leocov-dev/packwiz-nxt/internal/shared"
+)
+
+// aliasCmd represents the alias command
+var aliasCmd = &cobra.Command{
+	Use:   "alias [name] [path...]",
+	Short: "Install a file to other paths instead of (or as well as) its usual one",
+	Long: `Install a file to the given paths, relative to the pack folder, instead of its usual one:
+one copy of the file is installed to each path. Pass "" as a path to keep a copy at its usual
+one. Without any paths, the file is installed to its usual path only.
+
+Copies that were already installed to one of the paths keep whether they are preserved.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Loading modpack...")
+
+		packFile, packDir, err := shared.GetPackPaths()
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		pack, err := shared.LoadAll(packFile)
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		mod, ok := pack.Mods[args[0]]
+		if !ok {
+			shared.Exitln("Can't find this file; please ensure you have run packwiz refresh and use the name of the .pw.toml file (defaults to the project slug)")
+		}
+
+		preserved := make(map[string]bool)
+		for _, c := range mod.GetCopies() {
+			preserved[c.Alias] = c.Preserve
+		}
+		var copies []core.ModCopy
+		for _, alias := range args[1:] {
+			copies = append(copies, core.ModCopy{Alias: alias, Preserve: preserved[alias]})
+		}
+		if err := mod.SetCopies(copies); err != nil {
+			shared.Exitln(err)
+		}
+
+		err = fileio.WriteAll(*pack, packDir)
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		if len(args) == 1 {
+			fmt.Printf("%s is installed to its usual path\n", args[0])
+			return
+		}
+		var paths []string
+		for _, c := range mod.GetCopies() {
+			if c.Alias == "" {
+				paths = append(paths, "its usual path")
+			} else {
+				paths = append(paths, c.Alias)
+			}
+		}
+		fmt.Printf("%s is installed to %s\n", args[0], strings.Join(paths, ", "))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/internal/shared"
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the pack for common problems, such as shader packs without a shader loader",
+	Long: `Check the pack for problems that don't stop it from being refreshed or exported, but
+probably stop it from working as intended in game: datapacks and shader packs without a mod to
+load them, and resource packs made for a different version of Minecraft.
+
+Resource packs are downloaded (or read from the cache) to check their format; use --skip-files
+to only check the pack metadata. Problems are printed as warnings; use --fail-on-issues to exit
+with an error if any are found (e.g. in CI).`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		shared.LoadPlugins()
+		packFile, _, err := shared.GetPackPaths()
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		pack, err := shared.LoadAll(packFile)
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		issues := core.CheckPack(pack)
+		if !viper.GetBool("check.skip-files") {
+			fileIssues, err := shared.CheckModFiles(cmd.Context(), pack, pack.GetModsList())
+			if err != nil {
+				shared.Exitf("Error checking files: %v\n", err)
+			}
+			issues = append(issues, fileIssues...)
+		}
+		if len(issues) == 0 {
+			fmt.Println("No problems found")
+			return
+		}
+
+		shared.PrintIssues(issues)
+		if viper.GetBool("check.fail-on-issues") {
+			shared.Exitf("%d problem(s) found\n", len(issues))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+
+	checkCmd.Flags().Bool("fail-on-issues", false, "Exit with an error if any problems are found")
+	_ = viper.BindPFlag("check.fail-on-issues", checkCmd.Flags().Lookup("fail-on-issues"))
+	checkCmd.Flags().Bool("skip-files", false, "Don't download files to check their contents, such as resource pack formats")
+	_ = viper.BindPFlag("check.skip-files", checkCmd.Flags().Lookup("skip-files"))
+}
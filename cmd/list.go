@@ -9,7 +9,6 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/leocov-dev/packwiz-nxt/core"
-	"github.com/leocov-dev/packwiz-nxt/fileio"
 	"github.com/leocov-dev/packwiz-nxt/internal/shared"
 )
 
@@ -25,7 +24,7 @@ var listCmd = &cobra.Command{
 			shared.Exitln(err)
 		}
 
-		pack, err := fileio.LoadAll(packFile)
+		pack, err := shared.LoadAll(packFile)
 		if err != nil {
 			shared.Exitln(err)
 		}
@@ -56,7 +55,7 @@ var listCmd = &cobra.Command{
 		// Print mods
 		if viper.GetBool("list.version") {
 			for _, mod := range mods {
-				fmt.Printf("%s (%s)\n", mod.Name, mod.FileName)
+				fmt.Printf("%s (%s)\n", mod.Name, mod.GetVersion())
 			}
 		} else {
 			for _, mod := range mods {
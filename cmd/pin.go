@@ -5,10 +5,13 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/leocov-dev/packwiz-nxt/core"
 	"github.com/leocov-dev/packwiz-nxt/fileio"
 	"github.com/leocov-dev/packwiz-nxt/internal/shared"
 )
 
+// pinMod pins (or unpins) the mod named args[0]. When pinning, an optional version
+// constraint in args[1] lets the mod receive updates it allows instead.
 func pinMod(args []string, pinned bool) {
 	fmt.Println("Loading modpack...")
 
@@ -17,20 +20,35 @@ func pinMod(args []string, pinned bool) {
 		shared.Exitln(err)
 	}
 
-	pack, err := fileio.LoadAll(packFile)
+	pack, err := shared.LoadAll(packFile)
 	if err != nil {
 		shared.Exitln(err)
 	}
 
-	pack.Mods[args[0]].Pin = pinned
+	mod, ok := pack.Mods[args[0]]
+	if !ok {
+		shared.Exitln("Can't find this file; please ensure you have run packwiz refresh and use the name of the .pw.toml file (defaults to the project slug)")
+	}
+
+	constraint := ""
+	if pinned && len(args) > 1 {
+		constraint = args[1]
+		if err := core.ValidateVersionConstraint(constraint); err != nil {
+			shared.Exitln(err)
+		}
+	}
+	mod.Pin = pinned && constraint == ""
+	mod.VersionConstraint = constraint
 
-	err = fileio.WritePackAndIndex(*pack, packDir)
+	err = fileio.WriteAll(*pack, packDir)
 	if err != nil {
 		shared.Exitln(err)
 	}
 
 	message := "pinned"
-	if !pinned {
+	if constraint != "" {
+		message = fmt.Sprintf("constrained to %q", constraint)
+	} else if !pinned {
 		message = "unpinned"
 	}
 	fmt.Printf("%s %s successfully!\n", args[0], message)
@@ -38,10 +56,17 @@ func pinMod(args []string, pinned bool) {
 
 // pinCmd represents the pin command
 var pinCmd = &cobra.Command{
-	Use:     "pin",
-	Short:   "Pin a file so it does not get updated automatically",
+	Use:   "pin [name] [constraint]",
+	Short: "Pin a file so it does not get updated automatically",
+	Long: `Pin a file so it does not get updated automatically.
+
+With a version constraint, the file is only updated to versions it allows, e.g. "~4.2" for
+4.2.x versions, "^4.2" for 4.x versions from 4.2, or ">=4.2,<5". Constraints are compared
+against version numbers, CurseForge file names and release tags, ignoring Minecraft versions
+in them. Stable URLs and GitHub Actions artifacts have no versions, so constraints on them
+are ignored.`,
 	Aliases: []string{"hold"},
-	Args:    cobra.ExactArgs(1),
+	Args:    cobra.RangeArgs(1, 2),
 	Run: func(cmd *cobra.Command, args []string) {
 		pinMod(args, true)
 	},
@@ -49,8 +74,8 @@ var pinCmd = &cobra.Command{
 
 // unpinCmd represents the unpin command
 var unpinCmd = &cobra.Command{
-	Use:     "unpin",
-	Short:   "Unpin a file so it receives updates",
+	Use:     "unpin [name]",
+	Short:   "Unpin a file so it receives updates, removing its version constraint",
 	Aliases: []string{"unhold"},
 	Args:    cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
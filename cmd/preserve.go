@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+	"github.com/leocov-dev/packwiz-nxt/internal/shared"
+)
+
+// preserveMod sets whether the mod named args[0] is preserved. With an alias in args[1], only
+// the copy installed to that alias is changed.
+func preserveMod(args []string, preserve bool) {
+	fmt.Println("Loading modpack...")
+
+	packFile, packDir, err := shared.GetPackPaths()
+	if err != nil {
+		shared.Exitln(err)
+	}
+
+	pack, err := shared.LoadAll(packFile)
+	if err != nil {
+		shared.Exitln(err)
+	}
+
+	mod, ok := pack.Mods[args[0]]
+	if !ok {
+		shared.Exitln("Can't find this file; please ensure you have run packwiz refresh and use the name of the .pw.toml file (defaults to the project slug)")
+	}
+
+	copies := mod.GetCopies()
+	found := false
+	for i := range copies {
+		if len(args) > 1 && copies[i].Alias != args[1] {
+			continue
+		}
+		copies[i].Preserve = preserve
+		found = true
+	}
+	if !found {
+		shared.Exitf("%s isn't installed to %s; see packwiz alias\n", args[0], args[1])
+	}
+	if err := mod.SetCopies(copies); err != nil {
+		shared.Exitln(err)
+	}
+
+	err = fileio.WriteAll(*pack, packDir)
+	if err != nil {
+		shared.Exitln(err)
+	}
+
+	message := "preserved"
+	if !preserve {
+		message = "no longer preserved"
+	}
+	fmt.Printf("%s %s successfully!\n", args[0], message)
+}
+
+// preserveCmd represents the preserve command
+var preserveCmd = &cobra.Command{
+	Use:   "preserve [name] [alias]",
+	Short: "Preserve a file so installing the pack doesn't overwrite it once installed",
+	Long: `Preserve a file so installing the pack doesn't overwrite it once installed, e.g. so players
+can replace it. With an alias, only the copy installed to that path (see packwiz alias) is
+preserved.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		preserveMod(args, true)
+	},
+}
+
+// unpreserveCmd represents the unpreserve command
+var unpreserveCmd = &cobra.Command{
+	Use:   "unpreserve [name] [alias]",
+	Short: "Stop preserving a file, so installing the pack updates it",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		preserveMod(args, false)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(preserveCmd)
+	rootCmd.AddCommand(unpreserveCmd)
+}
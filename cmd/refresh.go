@@ -1,10 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
+	"github.com/leocov-dev/packwiz-nxt/core"
 	"github.com/leocov-dev/packwiz-nxt/fileio"
 	"github.com/leocov-dev/packwiz-nxt/internal/shared"
 )
@@ -13,28 +19,125 @@ import (
 var refreshCmd = &cobra.Command{
 	Use:   "refresh",
 	Short: "Refresh the index file",
-	Args:  cobra.NoArgs,
+	Long: `Refresh the index file, hashing every file in the pack that isn't excluded by .packwizignore.
+
+Files that are unchanged since the last refresh are not hashed again: their sizes, modification
+times and hashes are kept in the ` + fileio.LocalCacheFolder + ` folder, which git ignores.
+
+Use --check to only report whether the index is up to date, e.g. in CI or a git pre-commit hook
+(see 'packwiz utils install-hooks'): files that refreshing would add, remove or rehash are
+listed, and it exits with an error if index.toml or the index hash in pack.toml is out of date.
+Nothing is written in this mode.
+
+Use --watch to keep refreshing the index as files in the pack folder change, until interrupted.
+Only the changed files are hashed again, and index.toml and pack.toml are rewritten atomically.`,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Loading modpack...")
-		packFile, packDir, err := shared.GetPackPaths()
+		packFile, _, err := shared.GetPackPaths()
 		if err != nil {
 			shared.Exitln(err)
 		}
 
-		pack, err := fileio.LoadAll(packFile)
+		pack, err := fileio.LoadPackFile(packFile)
 		if err != nil {
 			shared.Exitln(err)
 		}
+		if viper.GetBool("refresh.check") {
+			if viper.GetBool("refresh.watch") {
+				shared.Exitln("--check and --watch can't be used together")
+			}
+			checkIndex(&pack, packFile)
+			return
+		}
 
-		err = fileio.WritePackAndIndex(*pack, packDir)
+		index, err := fileio.LoadPackIndexFile(&pack)
 		if err != nil {
 			shared.Exitln(err)
 		}
 
+		skipped, err := fileio.RefreshIndexFiles(&index, packFile, nil)
+		if err != nil {
+			shared.Exitln(err)
+		}
+		for _, err := range skipped {
+			fmt.Printf("Warning: %v\n", err)
+		}
+
+		if err := fileio.WriteIndex(&index); err != nil {
+			shared.Exitln(err)
+		}
+		pack.RefreshIndexHash(index)
+		if err := fileio.NewPackWriter().Write(&pack); err != nil {
+			shared.Exitln(err)
+		}
+
 		fmt.Println("Index refreshed!")
+
+		if viper.GetBool("refresh.watch") {
+			watchIndex(cmd.Context(), &pack, &index)
+		}
 	},
 }
 
+// watchDebounce is how long refresh --watch waits for changes to stop before updating the index
+const watchDebounce = 500 * time.Millisecond
+
+// watchIndex keeps the pack's index up to date until interrupted
+func watchIndex(ctx context.Context, pack *core.PackToml, index *core.IndexFS) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	fmt.Println("Watching for changes, press Ctrl+C to stop...")
+	err := fileio.WatchIndexFiles(ctx, pack, index, watchDebounce, func(changed []string, skipped []error) {
+		for _, err := range skipped {
+			fmt.Printf("Warning: %v\n", err)
+		}
+		for _, p := range changed {
+			fmt.Printf("Updated %s\n", p)
+		}
+	})
+	if err != nil {
+		shared.Exitln(err)
+	}
+}
+
+// checkIndex prints the changes refreshing the pack's index would make, exiting with an error
+// if there are any
+func checkIndex(pack *core.PackToml, packFile string) {
+	check, skipped, err := fileio.CheckIndexFiles(pack, packFile)
+	if err != nil {
+		shared.Exitln(err)
+	}
+	for _, err := range skipped {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	if check.UpToDate() {
+		fmt.Println("Index is up to date")
+		return
+	}
+
+	fmt.Println("Index is out of date:")
+	for _, p := range check.Files.Added {
+		fmt.Printf("  added:    %s\n", p)
+	}
+	for _, p := range check.Files.Removed {
+		fmt.Printf("  removed:  %s\n", p)
+	}
+	for _, p := range check.Files.Rehashed {
+		fmt.Printf("  rehashed: %s\n", p)
+	}
+	if check.IndexHashStale {
+		fmt.Println("  the index hash in pack.toml doesn't match index.toml")
+	}
+	shared.Exitln("Run 'packwiz refresh' to update the index")
+}
+
 func init() {
 	rootCmd.AddCommand(refreshCmd)
+
+	refreshCmd.Flags().Bool("check", false, "Only check that the index is up to date, exiting with an error if not, without writing anything")
+	_ = viper.BindPFlag("refresh.check", refreshCmd.Flags().Lookup("check"))
+	refreshCmd.Flags().Bool("watch", false, "Keep refreshing the index as files change, until interrupted")
+	_ = viper.BindPFlag("refresh.watch", refreshCmd.Flags().Lookup("watch"))
 }
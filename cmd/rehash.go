@@ -16,14 +16,14 @@ var rehashCmd = &cobra.Command{
 	Short: "Migrate all hashes to a specific format",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-
+		shared.LoadPlugins()
 		packPath, packDir, err := shared.GetPackPaths()
 		if err != nil {
 			shared.Exitln(err)
 		}
 
 		// Load pack
-		pack, err := fileio.LoadAll(packPath)
+		pack, err := shared.LoadAll(packPath)
 		if err != nil {
 			shared.Exitln(err)
 		}
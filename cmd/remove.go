@@ -25,7 +25,7 @@ var removeCmd = &cobra.Command{
 			shared.Exitln(err)
 		}
 
-		pack, err := fileio.LoadAll(packFile)
+		pack, err := shared.LoadAll(packFile)
 		if err != nil {
 			shared.Exitln(err)
 		}
@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
+	"github.com/leocov-dev/packwiz-nxt/config"
 	"github.com/leocov-dev/packwiz-nxt/fileio"
 	"github.com/leocov-dev/packwiz-nxt/internal/shared"
 )
@@ -25,7 +26,9 @@ var rootCmd = &cobra.Command{
 
 // Execute starts the root command for packwiz
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	shared.ClosePlugins()
+	if err != nil {
 		os.Exit(1)
 	}
 }
@@ -65,17 +68,23 @@ func init() {
 	rootCmd.PersistentFlags().String("cache", defaultCacheDir, "The directory where packwiz will cache downloaded mods")
 	_ = viper.BindPFlag("cache.directory", rootCmd.PersistentFlags().Lookup("cache"))
 
-	file, err := fileio.GetPackwizLocalStore()
+	localStore, err := fileio.GetPackwizLocalStore()
 	if err != nil {
 		shared.Exitln(err)
 	}
-	file = filepath.Join(file, ".packwiz.toml")
+	rootCmd.PersistentFlags().String("plugin-path", filepath.Join(localStore, "plugins"), "The directories (separated by \""+string(filepath.ListSeparator)+"\") from which provider plugin executables are loaded")
+	_ = viper.BindPFlag("plugins.path", rootCmd.PersistentFlags().Lookup("plugin-path"))
+
+	file := filepath.Join(localStore, ".packwiz.toml")
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "The config file to use (default \""+file+"\")")
 	_ = viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
 
 	var nonInteractive bool
 	rootCmd.PersistentFlags().BoolVarP(&nonInteractive, "yes", "y", false, "Accept all prompts with the default or \"yes\" option (non-interactive mode) - may pick unwanted options in search results")
 	_ = viper.BindPFlag("non-interactive", rootCmd.PersistentFlags().Lookup("yes"))
+
+	rootCmd.PersistentFlags().Bool("strict", false, "Check that index.toml and every metadata file match the hashes recorded for them when loading the pack, offering to refresh the index if not (failing instead with --yes)")
+	_ = viper.BindPFlag("strict", rootCmd.PersistentFlags().Lookup("strict"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -102,4 +111,9 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
 	}
+
+	// API tokens for Gitea/Forgejo servers, keyed by host, e.g. gitea.tokens."codeberg.org"
+	for host, token := range viper.GetStringMapString("gitea.tokens") {
+		config.SetGiteaApiKey(host, token)
+	}
 }
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+	"github.com/leocov-dev/packwiz-nxt/internal/shared"
+)
+
+// serverJarCmd represents the server-jar command
+var serverJarCmd = &cobra.Command{
+	Use:   "server-jar",
+	Short: "Download the server JAR of the pack's server platform (Paper or Purpur)",
+	Long: `Download the server JAR for the build of Paper or Purpur set in pack.toml, for
+exporting a server pack. The JAR is verified against the hash published by the platform
+and kept in the download cache.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		packPath, _, err := shared.GetPackPaths()
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		pack, err := shared.LoadAll(packPath)
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		platform, ok := pack.GetServerPlatform()
+		if !ok {
+			shared.Exitf("The pack doesn't have a server platform, add one of %v to the versions in pack.toml\n", core.ServerPlatforms)
+		}
+		mcVersion, err := pack.GetMCVersion()
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		jar, err := core.GetServerJar(platform, mcVersion, pack.Versions[platform])
+		if err != nil {
+			shared.Exitf("Error finding server JAR: %v\n", err)
+		}
+
+		session, err := fileio.CreateDownloadSession(nil, []*core.Mod{jar.AsMod()}, []string{})
+		if err != nil {
+			shared.Exitf("Error retrieving server JAR: %v\n", err)
+		}
+
+		output := filepath.Join(viper.GetString("server-jar.output"), jar.FileName)
+		for dl := range session.StartDownloads(cmd.Context()) {
+			if dl.Error != nil {
+				shared.Exitf("Error retrieving server JAR: %v\n", dl.Error)
+			}
+			err = copyToFile(dl.File, output)
+			_ = dl.File.Close()
+			if err != nil {
+				shared.Exitf("Error writing server JAR: %v\n", err)
+			}
+		}
+
+		err = session.SaveIndex()
+		if err != nil {
+			shared.Exitf("Error saving cache index: %v\n", err)
+		}
+
+		fmt.Printf("%s build %s for Minecraft %s saved to %s\n", core.ComponentToFriendlyName(platform), jar.Build, mcVersion, output)
+	},
+}
+
+func copyToFile(src io.Reader, path string) error {
+	f, err := fileio.CreateFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, src); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+func init() {
+	rootCmd.AddCommand(serverJarCmd)
+
+	serverJarCmd.Flags().StringP("output", "o", ".", "The directory to save the server JAR in")
+	_ = viper.BindPFlag("server-jar.output", serverJarCmd.Flags().Lookup("output"))
+}
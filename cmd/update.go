@@ -18,6 +18,7 @@ var updateCmd = &cobra.Command{
 	Aliases: []string{"upgrade"},
 	Args:    cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		shared.LoadPlugins()
 		// TODO: --check flag?
 		// TODO: specify multiple files to update at once?
 
@@ -28,7 +29,7 @@ var updateCmd = &cobra.Command{
 			shared.Exitln(err)
 		}
 
-		pack, err := fileio.LoadAll(packFile)
+		pack, err := shared.LoadAll(packFile)
 		if err != nil {
 			shared.Exitln(err)
 		}
@@ -7,9 +7,10 @@ import (
 )
 
 var (
-	Version  string
-	cfApiKey string
-	ghApiKey string
+	Version     string
+	cfApiKey    string
+	ghApiKey    string
+	giteaApiKey = make(map[string]string)
 )
 
 func SetVersion(version string) {
@@ -41,3 +42,12 @@ func DecodeCfApiKey() (string, error) {
 func GetGhApiKey() string {
 	return ghApiKey
 }
+
+// SetGiteaApiKey sets the API token used for the Gitea/Forgejo server at host (e.g. "codeberg.org")
+func SetGiteaApiKey(host string, key string) {
+	giteaApiKey[host] = key
+}
+
+func GetGiteaApiKey(host string) string {
+	return giteaApiKey[host]
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// PackIssue is a problem found in a pack by a PackCheck. Issues are warnings: the pack can
+// still be refreshed and exported, but probably won't work as intended in game.
+type PackIssue struct {
+	// Check is the name of the PackCheck that found the issue
+	Check   string
+	Message string
+	// Mods holds the slugs of the mods the issue concerns, if any
+	Mods []string
+}
+
+func (i PackIssue) String() string {
+	return fmt.Sprintf("[%s] %s", i.Check, i.Message)
+}
+
+// PackCheck looks for one kind of problem in a pack
+type PackCheck struct {
+	Name string
+	Run  func(pack *Pack) []PackIssue
+}
+
+// PackChecks are the checks run by CheckPack
+var PackChecks = []PackCheck{
+	{Name: "datapack-loader", Run: checkDatapackLoader},
+	{Name: "shader-loader", Run: checkShaderLoader},
+}
+
+// ModFileCheck looks for one kind of problem in the downloaded files of mods
+type ModFileCheck struct {
+	Name string
+	// Applies reports whether the check inspects the file of mod
+	Applies func(mod *Mod) bool
+	Run     func(pack *Pack, mod *Mod, file io.ReaderAt, size int64) []PackIssue
+}
+
+// ModFileChecks are the checks run by CheckModFile
+var ModFileChecks = []ModFileCheck{
+	{Name: "resourcepack-format", Applies: IsResourcePack, Run: checkResourcePackFormat},
+}
+
+// CheckPack runs every check in PackChecks against pack, returning the issues found ordered
+// by check name.
+func CheckPack(pack *Pack) []PackIssue {
+	var issues []PackIssue
+	for _, check := range PackChecks {
+		for _, issue := range check.Run(pack) {
+			if issue.Check == "" {
+				issue.Check = check.Name
+			}
+			issues = append(issues, issue)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Check < issues[j].Check
+	})
+	return issues
+}
+
+// ModsToCheckFiles returns the mods whose files are inspected by ModFileChecks, ordered by
+// slug, so callers can download them for CheckModFile
+func ModsToCheckFiles(mods []*Mod) []*Mod {
+	var result []*Mod
+	for _, mod := range mods {
+		for _, check := range ModFileChecks {
+			if check.Applies(mod) {
+				result = append(result, mod)
+				break
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Slug < result[j].Slug
+	})
+	return result
+}
+
+// CheckModFile runs the checks in ModFileChecks that apply to mod against its downloaded file
+func CheckModFile(pack *Pack, mod *Mod, file io.ReaderAt, size int64) []PackIssue {
+	var issues []PackIssue
+	for _, check := range ModFileChecks {
+		if !check.Applies(mod) {
+			continue
+		}
+		for _, issue := range check.Run(pack, mod, file, size) {
+			if issue.Check == "" {
+				issue.Check = check.Name
+			}
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// IssuesFor returns the issues that concern any of the mods with the given slugs
+func IssuesFor(issues []PackIssue, slugs ...string) []PackIssue {
+	var result []PackIssue
+	for _, issue := range issues {
+		for _, slug := range slugs {
+			if slices.Contains(issue.Mods, slug) {
+				result = append(result, issue)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// DatapackLoaderSlugs are the slugs of mods that load datapacks from a folder outside of a
+// world, making them apply to every world of the pack
+var DatapackLoaderSlugs = []string{
+	"paxi",
+	"open-loader",
+	"openloader",
+	"global-packs",
+	"globalpacks",
+	"global-data-and-resourcepacks",
+}
+
+// HasMod reports whether the pack contains a mod with one of the given slugs, comparing
+// both the slug of its metadata file and its slugified name.
+func (p *Pack) HasMod(slugs ...string) bool {
+	for _, mod := range p.Mods {
+		if slices.Contains(slugs, mod.Slug) || slices.Contains(slugs, SlugifyName(mod.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func modSlugs(mods []*Mod) []string {
+	slugs := make([]string, 0, len(mods))
+	for _, mod := range mods {
+		slugs = append(slugs, mod.Slug)
+	}
+	sort.Strings(slugs)
+	return slugs
+}
+
+// checkDatapackLoader warns about datapacks installed outside of a world's datapacks folder
+// in a pack without a mod to load them from there
+func checkDatapackLoader(pack *Pack) []PackIssue {
+	datapacks := pack.GetDatapacks()
+	folder := pack.GetDatapackFolder()
+	if len(datapacks) == 0 || IsWorldDatapackFolder(folder) || pack.HasMod(DatapackLoaderSlugs...) {
+		return nil
+	}
+
+	slugs := modSlugs(datapacks)
+	return []PackIssue{{
+		Message: fmt.Sprintf("%d datapack(s) are installed in %s, but the pack has no datapack loader mod (such as Paxi or Open Loader) to load them: %s",
+			len(datapacks), folder, strings.Join(slugs, ", ")),
+		Mods: slugs,
+	}}
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func datapackTestPack(folder string) *Pack {
+	pack := NewPack("Pack", "dev", "1.0.0", "", "1.21.1", LoaderInfo{"fabric": "0.16.5"})
+	pack.SetDatapackFolder(folder)
+	pack.SetMod(NewMod("terralith", "Terralith", "Terralith.zip", "both", folder, "", false, false, nil, ModDownload{}, nil))
+	pack.SetMod(NewMod("sodium", "Sodium", "sodium.jar", "client", "mods", "", false, false, nil, ModDownload{}, nil))
+	return pack
+}
+
+func TestDatapackFolder(t *testing.T) {
+	pack := NewPack("Pack", "dev", "1.0.0", "", "1.21.1", nil)
+	assert.Equal(t, "", pack.GetDatapackFolder())
+
+	pack.SetDatapackFolder("/config/paxi/datapacks/")
+	assert.Equal(t, "config/paxi/datapacks", pack.GetDatapackFolder())
+	assert.True(t, pack.IsDatapack(&Mod{ModType: "config/paxi/datapacks"}))
+	assert.False(t, pack.IsDatapack(&Mod{ModType: "mods"}))
+
+	pack.SetDatapackFolder("")
+	assert.Equal(t, "", pack.GetDatapackFolder())
+	assert.NotContains(t, pack.Options, DatapackFolderOption)
+	assert.False(t, pack.IsDatapack(&Mod{ModType: ""}))
+}
+
+func TestIsWorldDatapackFolder(t *testing.T) {
+	assert.True(t, IsWorldDatapackFolder("world/datapacks"))
+	assert.True(t, IsWorldDatapackFolder("saves/New World/datapacks"))
+	assert.False(t, IsWorldDatapackFolder("datapacks"))
+	assert.False(t, IsWorldDatapackFolder("config/datapacks"))
+	assert.False(t, IsWorldDatapackFolder("config/paxi/datapacks"))
+	assert.False(t, IsWorldDatapackFolder("world/data"))
+}
+
+func TestCheckDatapackLoader(t *testing.T) {
+	t.Run("datapacks without a loader", func(t *testing.T) {
+		issues := CheckPack(datapackTestPack("config/paxi/datapacks"))
+		require.Len(t, issues, 1)
+		assert.Equal(t, "datapack-loader", issues[0].Check)
+		assert.Equal(t, []string{"terralith"}, issues[0].Mods)
+		assert.Contains(t, issues[0].String(), "config/paxi/datapacks")
+	})
+
+	t.Run("datapacks with a loader", func(t *testing.T) {
+		pack := datapackTestPack("config/paxi/datapacks")
+		pack.SetMod(NewMod("paxi", "Paxi", "paxi.jar", "both", "mods", "", false, false, nil, ModDownload{}, nil))
+		assert.Empty(t, CheckPack(pack))
+	})
+
+	t.Run("loader matched by name", func(t *testing.T) {
+		pack := datapackTestPack("config/openloader/data")
+		pack.SetMod(NewMod("open-loader-forge", "Open Loader", "openloader.jar", "both", "mods", "", false, false, nil, ModDownload{}, nil))
+		assert.Empty(t, CheckPack(pack))
+	})
+
+	t.Run("datapacks in a world folder", func(t *testing.T) {
+		assert.Empty(t, CheckPack(datapackTestPack("world/datapacks")))
+	})
+
+	t.Run("no datapacks", func(t *testing.T) {
+		pack := datapackTestPack("config/paxi/datapacks")
+		delete(pack.Mods, "terralith")
+		assert.Empty(t, CheckPack(pack))
+	})
+}
+
+func TestIssuesFor(t *testing.T) {
+	issues := []PackIssue{
+		{Check: "a", Mods: []string{"x", "y"}},
+		{Check: "b", Mods: []string{"z"}},
+		{Check: "c"},
+	}
+	assert.Equal(t, []PackIssue{issues[0]}, IssuesFor(issues, "y"))
+	assert.Equal(t, issues[:2], IssuesFor(issues, "x", "z"))
+	assert.Empty(t, IssuesFor(issues, "w"))
+}
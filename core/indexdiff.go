@@ -0,0 +1,62 @@
+package core
+
+import (
+	"sort"
+	"strings"
+)
+
+// IndexDiff lists the files, as index paths, that differ between an index and an updated
+// version of it
+type IndexDiff struct {
+	Added    []string
+	Removed  []string
+	Rehashed []string
+}
+
+// IsEmpty returns true if the indexes have the same files with the same hashes
+func (d IndexDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Rehashed) == 0
+}
+
+// DiffFiles compares the files in the index with those in updated, e.g. the same index after
+// it has been refreshed. Each list in the result is sorted.
+func (in *IndexFS) DiffFiles(updated IndexFS) IndexDiff {
+	var diff IndexDiff
+	for p, file := range updated.Files {
+		previous, ok := in.Files[p]
+		if !ok {
+			diff.Added = append(diff.Added, p)
+		} else if in.entryHash(previous) != updated.entryHash(file) {
+			diff.Rehashed = append(diff.Rehashed, p)
+		}
+	}
+	for p := range in.Files {
+		if _, ok := updated.Files[p]; !ok {
+			diff.Removed = append(diff.Removed, p)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Rehashed)
+	return diff
+}
+
+// entryHash returns the hash of an index entry prefixed with its hash format, resolving an
+// empty format to the index's default. Aliased copies of a path all share the same hash.
+func (in *IndexFS) entryHash(file IndexPathHolder) string {
+	var entry IndexFile
+	switch f := file.(type) {
+	case *IndexFile:
+		entry = *f
+	case *indexFileMultipleAlias:
+		for _, v := range *f {
+			entry = v
+			break
+		}
+	}
+	format := entry.HashFormat
+	if format == "" {
+		format = in.DefaultModHashFormat
+	}
+	return strings.ToLower(format) + ":" + entry.Hash
+}
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexDiffFiles(t *testing.T) {
+	current := IndexFS{DefaultModHashFormat: "sha256", Files: IndexFiles{
+		"config/a.cfg":   &IndexFile{File: "config/a.cfg", Hash: "aa"},
+		"config/b.cfg":   &IndexFile{File: "config/b.cfg", Hash: "bb"},
+		"config/old.cfg": &IndexFile{File: "config/old.cfg", Hash: "oo"},
+		"mods/x.pw.toml": &indexFileMultipleAlias{
+			"x1": {File: "mods/x.pw.toml", Hash: "xx", Alias: "x1"},
+			"x2": {File: "mods/x.pw.toml", Hash: "xx", Alias: "x2"},
+		},
+	}}
+	updated := IndexFS{DefaultModHashFormat: "sha256", Files: IndexFiles{
+		"config/a.cfg":   &IndexFile{File: "config/a.cfg", Hash: "aa", HashFormat: "SHA256"},
+		"config/b.cfg":   &IndexFile{File: "config/b.cfg", Hash: "b2"},
+		"config/new.cfg": &IndexFile{File: "config/new.cfg", Hash: "nn"},
+		"mods/x.pw.toml": &indexFileMultipleAlias{
+			"x1": {File: "mods/x.pw.toml", Hash: "xx", Alias: "x1"},
+			"x2": {File: "mods/x.pw.toml", Hash: "xx", Alias: "x2"},
+		},
+	}}
+
+	diff := current.DiffFiles(updated)
+	assert.Equal(t, []string{"config/new.cfg"}, diff.Added)
+	assert.Equal(t, []string{"config/old.cfg"}, diff.Removed)
+	assert.Equal(t, []string{"config/b.cfg"}, diff.Rehashed, "an explicit default hash format is the same hash")
+	assert.False(t, diff.IsEmpty())
+
+	assert.True(t, current.DiffFiles(current).IsEmpty())
+}
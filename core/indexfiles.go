@@ -13,6 +13,7 @@ type IndexFiles map[string]IndexPathHolder
 type IndexPathHolder interface {
 	updateHash(hash string, format string)
 	markFound()
+	clearFound()
 	markMetaFile()
 	MarkedFound() (bool, error)
 	IsMetaFile() (bool, error)
@@ -27,7 +28,9 @@ type IndexFile struct {
 	Alias      string `toml:"alias,omitempty"`
 	MetaFile   bool   `toml:"metafile,omitempty"` // True when it is a .toml metadata file
 	Preserve   bool   `toml:"preserve,omitempty"` // Don't overwrite the file when updating
-	fileFound  bool
+	// Side is the side a non-metafile is installed on (metafiles record it themselves)
+	Side      ModSide `toml:"side,omitempty"`
+	fileFound bool
 }
 
 func (i *IndexFile) updateHash(hash string, format string) {
@@ -39,6 +42,10 @@ func (i *IndexFile) markFound() {
 	i.fileFound = true
 }
 
+func (i *IndexFile) clearFound() {
+	i.fileFound = false
+}
+
 func (i *IndexFile) markMetaFile() {
 	i.MetaFile = true
 }
@@ -68,6 +75,13 @@ func (i *indexFileMultipleAlias) markFound() {
 	}
 }
 
+func (i *indexFileMultipleAlias) clearFound() {
+	for k, v := range *i {
+		v.clearFound()
+		(*i)[k] = v // Can't mutate map value in place
+	}
+}
+
 func (i *indexFileMultipleAlias) markMetaFile() {
 	for k, v := range *i {
 		v.markMetaFile()
@@ -180,6 +194,13 @@ func (f *IndexFiles) toTomlRep() (IndexFilesTomlRepresentation, error) {
 		}
 	}
 
+	rep.sort()
+	return rep, nil
+}
+
+// sort orders the entries by path, then alias, so that the marshalled index (and its hash) don't
+// depend on map iteration order
+func (rep IndexFilesTomlRepresentation) sort() {
 	slices.SortFunc(rep, func(a IndexFile, b IndexFile) int {
 		if a.File == b.File {
 			if a.Alias == b.Alias {
@@ -197,6 +218,4 @@ func (f *IndexFiles) toTomlRep() (IndexFilesTomlRepresentation, error) {
 			}
 		}
 	})
-
-	return rep, nil
 }
@@ -69,6 +69,53 @@ func (in *IndexFS) UpdateFileHashGiven(path, format, hash string, markAsMetaFile
 	return nil
 }
 
+// UpdateHash records the hash of the index file itself, as written to disk
+func (in *IndexFS) UpdateHash(format, hash string) {
+	in.hashFormat = format
+	in.hash = hash
+}
+
+// ClearFound clears the found marks of every entry, so that refreshing an index that has already
+// been refreshed removes the files that are no longer found
+func (in *IndexFS) ClearFound() {
+	for _, file := range in.Files {
+		file.clearFound()
+	}
+}
+
+// MarkFound marks the index entry for path, and the entries of any files under it if it is a
+// folder, as found without rehashing them, so that a refresh which couldn't read them keeps
+// their previous hashes instead of dropping them
+func (in *IndexFS) MarkFound(path string) error {
+	relPath, err := in.RelIndexPath(path)
+	if err != nil {
+		return err
+	}
+	for p, file := range in.Files {
+		if p == relPath || relPath == "." || strings.HasPrefix(p, relPath+"/") {
+			file.markFound()
+		}
+	}
+	return nil
+}
+
+// GetEntries returns the index entries for the index path p, one for each alias it is installed
+// to, ordered by alias (so an entry without one comes first), or nil if p isn't in the index
+func (in *IndexFS) GetEntries(p string) []IndexFile {
+	switch file := in.Files[p].(type) {
+	case *IndexFile:
+		return []IndexFile{*file}
+	case *indexFileMultipleAlias:
+		entries := make(IndexFilesTomlRepresentation, 0, len(*file))
+		for _, entry := range *file {
+			entries = append(entries, entry)
+		}
+		entries.sort()
+		return entries
+	}
+	return nil
+}
+
 // ResolveIndexPath turns a path from the index into a file path on disk
 func (in *IndexFS) ResolveIndexPath(p string) string {
 	return filepath.Join(in.packRoot, filepath.FromSlash(p))
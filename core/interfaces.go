@@ -2,10 +2,11 @@ package core
 
 import (
 	"io"
+	"sort"
 	"sync"
 )
 
-// Registry holds the set of Updaters and MetaDownloaders that packwiz can use,
+// Registry holds the set of Updaters, MetaDownloaders and Sources that packwiz can use,
 // keyed by their configuration/source name. It is safe for concurrent use by
 // multiple goroutines.
 //
@@ -14,6 +15,7 @@ type Registry struct {
 	mu              sync.RWMutex
 	updaters        map[string]Updater
 	metaDownloaders map[string]MetaDownloader
+	sources         map[string]Source
 	logger          Logger
 }
 
@@ -22,6 +24,7 @@ func NewRegistry() *Registry {
 	return &Registry{
 		updaters:        make(map[string]Updater),
 		metaDownloaders: make(map[string]MetaDownloader),
+		sources:         make(map[string]Source),
 		logger:          PrintLogger{},
 	}
 }
@@ -71,10 +74,42 @@ func (r *Registry) GetMetaDownloader(source string) (MetaDownloader, bool) {
 	return downloader, ok
 }
 
+// AddSource registers a Source, keyed by its GetName() value.
+func (r *Registry) AddSource(source Source) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sources[source.GetName()] = source
+}
+
+// GetSource looks up a Source previously registered with AddSource.
+func (r *Registry) GetSource(name string) (Source, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	source, ok := r.sources[name]
+	return source, ok
+}
+
+// Sources returns every registered Source, sorted by name.
+func (r *Registry) Sources() []Source {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.sources))
+	for name := range r.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]Source, len(names))
+	for i, name := range names {
+		result[i] = r.sources[name]
+	}
+	return result
+}
+
 // DefaultRegistry is the process-wide Registry used by the packwiz CLI and by
 // package-level helpers (AddUpdater, GetUpdater, AddMetaDownloader,
-// GetMetaDownloader). sources/*-updater.go register themselves here via
-// init(). Library consumers that want isolated/concurrent instances should
+// GetMetaDownloader, AddSource, GetSource). sources/*-updater.go register
+// themselves here via init(). Library consumers that want isolated/concurrent instances should
 // construct their own Registry with NewRegistry instead of relying on this.
 var DefaultRegistry = NewRegistry()
 
@@ -111,6 +146,16 @@ func GetMetaDownloader(source string) (MetaDownloader, bool) {
 	return DefaultRegistry.GetMetaDownloader(source)
 }
 
+// AddSource registers a Source on DefaultRegistry, keyed by its GetName() value.
+func AddSource(source Source) {
+	DefaultRegistry.AddSource(source)
+}
+
+// GetSource looks up a Source on DefaultRegistry.
+func GetSource(name string) (Source, bool) {
+	return DefaultRegistry.GetSource(name)
+}
+
 // Updater is used to process updates on mods
 type Updater interface {
 	GetName() string
@@ -132,6 +177,15 @@ type UpdateCheck struct {
 	// UpdateString is a string that details the update in some way to the user. Usually this will be in the form of
 	// a version change (1.0.0 -> 1.0.1), or a file name change (thanos-skin-1.0.0.jar -> thanos-skin-1.0.1.jar).
 	UpdateString string
+	// HeldBack describes a newer version that wasn't offered because the mod's version
+	// constraint doesn't allow it (e.g. "5.0.0"), or is empty
+	HeldBack string
+	// ConstraintIgnored is true if the mod has a version constraint that the updater can't
+	// apply, as the source has no version numbers; updates are checked as if it had none
+	ConstraintIgnored bool
+	// TooRecent describes a newer version that wasn't offered because it was published more
+	// recently than the minimum release age (see MinReleaseAgeOption), or is empty
+	TooRecent string
 	// CachedState can be used to preserve per-mod state between CheckUpdate and DoUpdate (e.g. file metadata)
 	CachedState any
 	// Error stores an error for this specific mod
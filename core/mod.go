@@ -3,17 +3,26 @@ package core
 import (
 	"fmt"
 	"github.com/mitchellh/mapstructure"
+	"path"
 	"path/filepath"
+	"strings"
 )
 
 type Mod struct {
 	Name     string
 	FileName string
-	Side     ModSide
-	Pin      bool
-	Download ModDownload
-	Update   ModUpdate
-	Option   *ModOption
+	// Version is the provider's version label of the file, such as a version number or release
+	// tag, or "" if unknown
+	Version string
+	Side    ModSide
+	Pin     bool
+	// VersionConstraint restricts the versions the mod is updated to, see VersionSatisfies
+	VersionConstraint string
+	// MinReleaseAge overrides the pack's minimum release age, see MinReleaseAgeOption
+	MinReleaseAge string
+	Download      ModDownload
+	Update        ModUpdate
+	Option        *ModOption
 
 	// for index
 	Slug       string
@@ -21,6 +30,17 @@ type Mod struct {
 	HashFormat string
 	Alias      string
 	Preserve   bool
+	// Copies are further entries of the metadata file in the index, each installed to a
+	// different alias, see GetCopies
+	Copies []ModCopy
+}
+
+// ModCopy is an entry of a mod's metadata file in the index: Alias is the path the mod's file is
+// installed to instead of its usual one (or "" for the usual one), and Preserve is true if it
+// shouldn't be overwritten once installed
+type ModCopy struct {
+	Alias    string
+	Preserve bool
 }
 
 func NewMod(
@@ -53,17 +73,78 @@ func NewMod(
 
 func FromModMeta(modMeta ModToml) *Mod {
 	return &Mod{
-		Name:       modMeta.Name,
-		FileName:   modMeta.FileName,
-		Side:       modMeta.Side,
-		Pin:        modMeta.Pin,
-		Download:   modMeta.Download,
-		Update:     modMeta.Update,
-		Option:     modMeta.Option,
-		Slug:       modMeta.slug,
-		ModType:    modMeta.metaFolder,
-		HashFormat: modMeta.GetHashFormat(),
+		Name:              modMeta.Name,
+		FileName:          modMeta.FileName,
+		Version:           modMeta.Version,
+		Side:              modMeta.Side,
+		Pin:               modMeta.Pin,
+		VersionConstraint: modMeta.VersionConstraint,
+		MinReleaseAge:     modMeta.MinReleaseAge,
+		Download:          modMeta.Download,
+		Update:            modMeta.Update,
+		Option:            modMeta.Option,
+		Slug:              modMeta.slug,
+		ModType:           modMeta.metaFolder,
+		HashFormat:        modMeta.GetHashFormat(),
+		Alias:             modMeta.alias,
+		Preserve:          modMeta.preserve,
+		Copies:            modMeta.copies,
+	}
+}
+
+// GetCopies returns every entry of the mod's metadata file in the index: the mod's own Alias and
+// Preserve, followed by Copies
+func (m *Mod) GetCopies() []ModCopy {
+	return append([]ModCopy{{Alias: m.Alias, Preserve: m.Preserve}}, m.Copies...)
+}
+
+// SetCopies sets the entries of the mod's metadata file in the index (see GetCopies); no copies
+// means a single entry without an alias. Aliases must be distinct paths within the pack folder.
+func (m *Mod) SetCopies(copies []ModCopy) error {
+	copies = append([]ModCopy(nil), copies...)
+	seen := make(map[string]bool)
+	for i, c := range copies {
+		if c.Alias != "" {
+			clean := path.Clean(filepath.ToSlash(c.Alias))
+			if path.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+				return fmt.Errorf("alias %s is not a path within the pack folder", c.Alias)
+			}
+			copies[i].Alias = clean
+		}
+		if seen[copies[i].Alias] {
+			return fmt.Errorf("alias %q is used more than once", copies[i].Alias)
+		}
+		seen[copies[i].Alias] = true
+	}
+
+	if len(copies) == 0 {
+		copies = []ModCopy{{}}
+	}
+	m.Alias, m.Preserve = copies[0].Alias, copies[0].Preserve
+	m.Copies = nil
+	if len(copies) > 1 {
+		m.Copies = copies[1:]
 	}
+	return nil
+}
+
+// GetVersion returns the mod's version label, or its file name for mods whose version isn't
+// recorded
+func (m *Mod) GetVersion() string {
+	if m.Version != "" {
+		return m.Version
+	}
+	return m.FileName
+}
+
+// DescribeUpdate returns an UpdateCheck.UpdateString for updating m to the file newFileName
+// with the version label newVersion, comparing versions if both are known and file names
+// otherwise
+func (m *Mod) DescribeUpdate(newVersion string, newFileName string) string {
+	if m.Version != "" && newVersion != "" {
+		return m.Version + " -> " + newVersion
+	}
+	return m.FileName + " -> " + newFileName
 }
 
 func (m *Mod) GetRelMetaPath() string {
@@ -85,32 +166,48 @@ func (m *Mod) AsModToml() (string, string, error) {
 	return result.String(), result.Hash, nil
 }
 
-func (m *Mod) toIndexEntry() (IndexFile, error) {
+// toIndexEntries returns the index entries of the mod's metadata file, one for each of its
+// copies (see GetCopies)
+func (m *Mod) toIndexEntries() ([]IndexFile, error) {
 
 	_, hash, err := m.AsModToml()
 	if err != nil {
-		return IndexFile{}, err
+		return nil, err
+	}
+
+	// Omit the format if equal to the index hash format, as refreshing the index does
+	hashFormat := m.HashFormat
+	if hashFormat == DefaultHashFormat {
+		hashFormat = ""
 	}
 
-	return IndexFile{
-		File:       m.GetRelMetaPath(),
-		Hash:       hash,
-		HashFormat: m.HashFormat,
-		Alias:      m.Alias,
-		MetaFile:   true,
-		Preserve:   m.Preserve,
-	}, nil
+	copies := m.GetCopies()
+	entries := make([]IndexFile, 0, len(copies))
+	for _, c := range copies {
+		entries = append(entries, IndexFile{
+			File:       m.GetRelMetaPath(),
+			Hash:       hash,
+			HashFormat: hashFormat,
+			Alias:      c.Alias,
+			MetaFile:   true,
+			Preserve:   c.Preserve,
+		})
+	}
+	return entries, nil
 }
 
 func (m *Mod) ToModMeta() ModToml {
 	modToml := ModToml{
-		Name:     m.Name,
-		FileName: m.FileName,
-		Side:     m.Side,
-		Pin:      m.Pin,
-		Download: m.Download,
-		Update:   m.Update,
-		Option:   m.Option,
+		Name:              m.Name,
+		FileName:          m.FileName,
+		Version:           m.Version,
+		Side:              m.Side,
+		Pin:               m.Pin,
+		VersionConstraint: m.VersionConstraint,
+		MinReleaseAge:     m.MinReleaseAge,
+		Download:          m.Download,
+		Update:            m.Update,
+		Option:            m.Option,
 	}
 	modToml.SetMetaPath(m.GetRelMetaPath())
 
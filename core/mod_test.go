@@ -3,6 +3,7 @@ package core
 import (
 	"github.com/bradleyjkemp/cupaloy"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -41,3 +42,90 @@ func TestModStruct(t *testing.T) {
 
 	cupaloy.SnapshotT(t, text, hash)
 }
+
+func TestModVersionConstraintRoundTrip(t *testing.T) {
+	mod := NewMod("balm", "Balm", "balm.jar", "both", "mods", "", false, false, ModUpdate{}, ModDownload{HashFormat: "sha1"}, nil)
+	mod.VersionConstraint = "~21.5"
+
+	text, _, err := mod.AsModToml()
+	assert.NoError(t, err)
+	assert.Contains(t, text, `version-constraint = '~21.5'`)
+
+	meta := mod.ToModMeta()
+	assert.Equal(t, "~21.5", FromModMeta(meta).VersionConstraint)
+}
+
+func TestModVersion(t *testing.T) {
+	mod := &Mod{FileName: "balm-1.0.jar"}
+	assert.Equal(t, "balm-1.0.jar", mod.GetVersion(), "mods without a version fall back to the file name")
+	assert.Equal(t, "balm-1.0.jar -> balm-2.0.jar", mod.DescribeUpdate("2.0", "balm-2.0.jar"))
+
+	mod.Version = "1.0"
+	assert.Equal(t, "1.0", mod.GetVersion())
+	assert.Equal(t, "1.0 -> 2.0", mod.DescribeUpdate("2.0", "balm-2.0.jar"))
+	assert.Equal(t, "balm-1.0.jar -> balm-2.0.jar", mod.DescribeUpdate("", "balm-2.0.jar"))
+
+	text, _, err := mod.AsModToml()
+	assert.NoError(t, err)
+	assert.Contains(t, text, `version = '1.0'`)
+	assert.Equal(t, "1.0", FromModMeta(mod.ToModMeta()).Version)
+}
+
+func TestModDownloadHashes(t *testing.T) {
+	download := ModDownload{HashFormat: "sha512", Hash: "h512", Size: 1024}
+	download.SetHashes(map[string]string{"SHA512": "h512", "sha1": "h1", "md5": ""})
+	assert.Equal(t, map[string]string{"sha1": "h1"}, download.Hashes, "the primary hash and empty hashes aren't duplicated")
+
+	hash, ok := download.GetHash("sha512")
+	assert.True(t, ok)
+	assert.Equal(t, "h512", hash)
+	hash, ok = download.GetHash("SHA1")
+	assert.True(t, ok)
+	assert.Equal(t, "h1", hash)
+	_, ok = download.GetHash("md5")
+	assert.False(t, ok)
+
+	download.SetHashes(map[string]string{"sha512": "h512"})
+	assert.Nil(t, download.Hashes)
+
+	mod := &Mod{FileName: "balm.jar", Download: ModDownload{HashFormat: "sha512", Hash: "h512", Size: 1024}}
+	mod.Download.SetHashes(map[string]string{"sha1": "h1"})
+	text, _, err := mod.AsModToml()
+	assert.NoError(t, err)
+	assert.Contains(t, text, `size = 1024`)
+	assert.Equal(t, mod.Download, FromModMeta(mod.ToModMeta()).Download)
+}
+
+func TestModCopies(t *testing.T) {
+	mod := &Mod{Slug: "balm", FileName: "balm.jar", ModType: "mods"}
+	assert.Equal(t, []ModCopy{{}}, mod.GetCopies())
+
+	require.NoError(t, mod.SetCopies([]ModCopy{{Alias: "mods/a/balm.jar", Preserve: true}, {Alias: "./mods/b/balm.jar"}}))
+	assert.Equal(t, "mods/a/balm.jar", mod.Alias)
+	assert.True(t, mod.Preserve)
+	assert.Equal(t, []ModCopy{{Alias: "mods/b/balm.jar"}}, mod.Copies)
+
+	assert.Error(t, mod.SetCopies([]ModCopy{{Alias: "../balm.jar"}}))
+	assert.Error(t, mod.SetCopies([]ModCopy{{Alias: "mods/a.jar"}, {Alias: "mods/./a.jar"}}))
+
+	// Each copy is an entry of the metadata file in the index
+	pack := &Pack{}
+	pack.SetMod(mod)
+	index, err := pack.AsIndexMeta()
+	require.NoError(t, err)
+	entries := index.GetEntries("mods/balm.pw.toml")
+	require.Len(t, entries, 2)
+	assert.Equal(t, "mods/a/balm.jar", entries[0].Alias)
+	assert.True(t, entries[0].Preserve)
+	assert.Equal(t, "mods/b/balm.jar", entries[1].Alias)
+	assert.False(t, entries[1].Preserve)
+
+	modToml := mod.ToModMeta()
+	modToml.SetIndexEntries(entries)
+	loaded := FromModMeta(modToml)
+	assert.Equal(t, mod.GetCopies(), loaded.GetCopies())
+
+	require.NoError(t, mod.SetCopies(nil))
+	assert.Equal(t, []ModCopy{{}}, mod.GetCopies())
+	assert.Nil(t, mod.Copies)
+}
@@ -12,12 +12,20 @@ type ModUpdate map[string]ModSourceData
 
 // ModToml stores metadata about a mod. This is written to a TOML file for each mod.
 type ModToml struct {
-	metaFile string      // The file for the metadata file, used as an ID
-	Name     string      `toml:"name"`
-	FileName string      `toml:"filename"`
-	Side     ModSide     `toml:"side,omitempty"`
-	Pin      bool        `toml:"pin,omitempty"`
-	Download ModDownload `toml:"download"`
+	metaFile string // The file for the metadata file, used as an ID
+	Name     string `toml:"name"`
+	FileName string `toml:"filename"`
+	// Version is the provider's version label of the file, such as a version number or release tag
+	Version string  `toml:"version,omitempty"`
+	Side    ModSide `toml:"side,omitempty"`
+	Pin     bool    `toml:"pin,omitempty"`
+	// VersionConstraint restricts the versions the mod is updated to, see VersionSatisfies. It is
+	// its own key, holding lower and upper bounds together, as pin stays a boolean for
+	// compatibility with packwiz-installer and older packs
+	VersionConstraint string `toml:"version-constraint,omitempty"`
+	// MinReleaseAge overrides the pack's minimum release age, see MinReleaseAgeOption
+	MinReleaseAge string      `toml:"min-release-age,omitempty"`
+	Download      ModDownload `toml:"download"`
 	// Update is a map of maps, of stuff, so you can store arbitrary values on
 	// string keys to define updating
 	Update     ModUpdate `toml:"update"`
@@ -28,11 +36,17 @@ type ModToml struct {
 	hash       string
 	slug       string
 	metaFolder string
+
+	// Recorded in the index rather than the metadata file, see SetIndexEntries
+	alias    string
+	preserve bool
+	copies   []ModCopy
 }
 
 const (
-	ModeURL string = "url"
-	ModeCF  string = "metadata:curseforge"
+	ModeURL           string = "url"
+	ModeCF            string = "metadata:curseforge"
+	ModeGitHubActions string = "metadata:github-actions"
 )
 
 // ModDownload specifies how to download the mod file
@@ -42,6 +56,36 @@ type ModDownload struct {
 	Hash       string `toml:"hash"`
 	// Mode defaults to modeURL (i.e. use URL when omitted or empty)
 	Mode string `toml:"mode,omitempty"`
+	// Hashes holds other hashes of the file supplied by its provider, keyed by hash format, so
+	// exports that need them don't have to download it
+	Hashes map[string]string `toml:"hashes,omitempty"`
+	// Size is the size of the file in bytes, or 0 if unknown
+	Size uint64 `toml:"size,omitempty"`
+}
+
+// GetHash returns the hash of the file in the given format, if known
+func (d ModDownload) GetHash(format string) (string, bool) {
+	if strings.EqualFold(d.HashFormat, format) && d.Hash != "" {
+		return d.Hash, true
+	}
+	hash, ok := d.Hashes[strings.ToLower(format)]
+	return hash, ok && hash != ""
+}
+
+// SetHashes records the provider-supplied hashes of the file (keyed by hash format) other than
+// the one in Hash
+func (d *ModDownload) SetHashes(hashes map[string]string) {
+	d.Hashes = nil
+	for format, hash := range hashes {
+		format = strings.ToLower(format)
+		if hash == "" || format == strings.ToLower(d.HashFormat) {
+			continue
+		}
+		if d.Hashes == nil {
+			d.Hashes = make(map[string]string)
+		}
+		d.Hashes[format] = hash
+	}
 }
 
 // ModOption specifies optional metadata for this mod file
@@ -91,6 +135,19 @@ func (m *ModToml) GetMetaRelativePath() string {
 	return fmt.Sprintf("%s/%s%s", m.metaFolder, m.slug, MetaExtension)
 }
 
+// SetIndexEntries records the aliases and preserve flags of the metadata file's entries in the
+// index (as returned by IndexFS.GetEntries), which FromModMeta copies into the Mod
+func (m *ModToml) SetIndexEntries(entries []IndexFile) {
+	m.alias, m.preserve, m.copies = "", false, nil
+	for i, entry := range entries {
+		if i == 0 {
+			m.alias, m.preserve = entry.Alias, entry.Preserve
+			continue
+		}
+		m.copies = append(m.copies, ModCopy{Alias: entry.Alias, Preserve: entry.Preserve})
+	}
+}
+
 // SetMetaPath sets the file path of a metadata file
 func (m *ModToml) SetMetaPath(metaFile string) string {
 	m.metaFile = metaFile
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+)
+
+// ContentProvider opens the contents of a file that isn't necessarily on disk, such as an
+// override file added to an in-memory Pack
+type ContentProvider interface {
+	Open() (io.ReadCloser, error)
+}
+
+// BytesContent is a ContentProvider for contents held in memory
+type BytesContent []byte
+
+func (b BytesContent) Open() (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+// OverrideFile is a file in the pack that isn't mod metadata, such as a config file, and is
+// installed as-is
+type OverrideFile struct {
+	// Path is the file's path relative to the pack root, in forward-slash format
+	Path       string
+	Hash       string
+	HashFormat string
+	// Side is the side the file is installed on; "" is equivalent to UniversalSide
+	Side ModSide
+	// Preserve is true if the file shouldn't be overwritten once installed, e.g. so players can
+	// change it
+	Preserve bool
+	// Alias is the path the file is installed to instead of Path, or "" to install it to Path
+	Alias string
+	// Content provides the file's contents, or is nil if they aren't available. If Hash is empty
+	// it is computed from Content.
+	Content ContentProvider
+}
+
+// NewOverrideFile creates an override file at filePath (relative to the pack root) with the
+// given contents, hashed with DefaultHashFormat when the pack is written
+func NewOverrideFile(filePath string, side ModSide, content ContentProvider) *OverrideFile {
+	return &OverrideFile{
+		Path:    path.Clean(filePath),
+		Side:    side,
+		Content: content,
+	}
+}
+
+// FromIndexFile creates an override file from a non-metafile entry of index.toml, whose hash
+// format defaults to defaultHashFormat (the index's hash-format)
+func FromIndexFile(file IndexFile, defaultHashFormat string, content ContentProvider) *OverrideFile {
+	hashFormat := file.HashFormat
+	if hashFormat == "" {
+		hashFormat = defaultHashFormat
+	}
+	return &OverrideFile{
+		Path:       file.File,
+		Hash:       file.Hash,
+		HashFormat: hashFormat,
+		Side:       file.Side,
+		Preserve:   file.Preserve,
+		Alias:      file.Alias,
+		Content:    content,
+	}
+}
+
+// GetInstallPath returns the path the file is installed to, relative to the pack root
+func (f *OverrideFile) GetInstallPath() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Path
+}
+
+// GetHash returns the file's hash format and hash, hashing Content with DefaultHashFormat (and
+// recording the result) if the hash isn't known yet
+func (f *OverrideFile) GetHash() (string, string, error) {
+	if f.Hash != "" {
+		return f.HashFormat, f.Hash, nil
+	}
+	if f.Content == nil {
+		return "", "", fmt.Errorf("override file %s has no hash or content", f.Path)
+	}
+
+	hasher, err := GetHashImpl(DefaultHashFormat)
+	if err != nil {
+		return "", "", err
+	}
+	r, err := f.Content.Open()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open override file %s: %w", f.Path, err)
+	}
+	defer r.Close()
+	if _, err := io.Copy(hasher, r); err != nil {
+		return "", "", fmt.Errorf("failed to read override file %s: %w", f.Path, err)
+	}
+
+	f.HashFormat, f.Hash = DefaultHashFormat, hasher.String()
+	return f.HashFormat, f.Hash, nil
+}
+
+func (f *OverrideFile) toIndexEntry() (IndexFile, error) {
+	hashFormat, hash, err := f.GetHash()
+	if err != nil {
+		return IndexFile{}, err
+	}
+	// Omit the format if equal to the index hash format, as refreshing the index does
+	if hashFormat == DefaultHashFormat {
+		hashFormat = ""
+	}
+
+	return IndexFile{
+		File:       path.Clean(f.Path),
+		Hash:       hash,
+		HashFormat: hashFormat,
+		Alias:      f.Alias,
+		Preserve:   f.Preserve,
+		Side:       f.Side,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverrideFile_GetHash(t *testing.T) {
+	file := NewOverrideFile("config/a.cfg", ClientSide, BytesContent("a=1"))
+
+	format, hash, err := file.GetHash()
+	require.NoError(t, err)
+	sum := sha256.Sum256([]byte("a=1"))
+	assert.Equal(t, DefaultHashFormat, format)
+	assert.Equal(t, hex.EncodeToString(sum[:]), hash)
+	// Recorded, so the contents aren't read again
+	assert.Equal(t, hash, file.Hash)
+
+	_, _, err = (&OverrideFile{Path: "config/b.cfg"}).GetHash()
+	assert.Error(t, err)
+}
+
+func TestFromIndexFile(t *testing.T) {
+	file := FromIndexFile(IndexFile{File: "config/a.cfg", Hash: "abc", Preserve: true, Side: ServerSide}, "sha1", nil)
+	assert.Equal(t, "sha1", file.HashFormat)
+	assert.Equal(t, "config/a.cfg", file.GetInstallPath())
+	assert.True(t, file.Preserve)
+	assert.Equal(t, ServerSide, file.Side)
+
+	file = FromIndexFile(IndexFile{File: "config/a.cfg", Hash: "abc", HashFormat: "sha512", Alias: "config/b.cfg"}, "sha1", nil)
+	assert.Equal(t, "sha512", file.HashFormat)
+	assert.Equal(t, "config/b.cfg", file.GetInstallPath())
+}
+
+func TestPack_IndexIncludesOverrides(t *testing.T) {
+	pack := NewPack("PackA", "dev", "1.0.0", "", "1.21.1", nil)
+	pack.SetOverride(&OverrideFile{Path: "options.txt", Hash: "abc", HashFormat: "sha1", Preserve: true})
+	pack.SetOverride(NewOverrideFile("config/a.cfg", ClientSide, BytesContent("a=1")))
+
+	index, err := pack.AsIndexMeta()
+	require.NoError(t, err)
+	repr, err := index.ToWritable()
+	require.NoError(t, err)
+	require.Len(t, repr.Files, 2)
+
+	assert.Equal(t, "config/a.cfg", repr.Files[0].File)
+	assert.Equal(t, ClientSide, repr.Files[0].Side)
+	assert.Empty(t, repr.Files[0].HashFormat, "the index hash format is omitted")
+	assert.False(t, repr.Files[0].MetaFile)
+
+	assert.Equal(t, IndexFile{File: "options.txt", Hash: "abc", HashFormat: "sha1", Preserve: true}, repr.Files[1])
+
+	// The index hash doesn't depend on map order
+	_, hash, err := pack.AsIndexToml()
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		_, again, err := pack.AsIndexToml()
+		require.NoError(t, err)
+		assert.Equal(t, hash, again)
+	}
+}
@@ -2,6 +2,8 @@ package core
 
 import (
 	"fmt"
+	"path"
+	"path/filepath"
 )
 
 type Pack struct {
@@ -14,6 +16,9 @@ type Pack struct {
 	Export      map[string]map[string]interface{}
 	Options     map[string]interface{}
 	Mods        map[string]*Mod
+	// Overrides are the files in the pack other than mod metadata, keyed by install path (see
+	// OverrideFile.GetInstallPath)
+	Overrides map[string]*OverrideFile
 }
 
 type LoaderInfo map[string]string
@@ -44,13 +49,18 @@ func NewPack(
 	}
 }
 
-func FromPackAndModsMeta(packMeta PackToml, modMetas []*ModToml) *Pack {
+func FromPackAndModsMeta(packMeta PackToml, modMetas []*ModToml, overrides []*OverrideFile) *Pack {
 
 	mods := make(map[string]*Mod)
 	for _, modMeta := range modMetas {
 		mods[modMeta.GetSlug()] = FromModMeta(*modMeta)
 	}
 
+	overridesByPath := make(map[string]*OverrideFile)
+	for _, override := range overrides {
+		overridesByPath[override.GetInstallPath()] = override
+	}
+
 	pack := &Pack{
 		Name:        packMeta.Name,
 		Author:      packMeta.Author,
@@ -61,6 +71,7 @@ func FromPackAndModsMeta(packMeta PackToml, modMetas []*ModToml) *Pack {
 		Export:      packMeta.Export,
 		Options:     packMeta.Options,
 		Mods:        mods,
+		Overrides:   overridesByPath,
 	}
 
 	return pack
@@ -92,6 +103,23 @@ func (p *Pack) GetModsList() []*Mod {
 	return mods
 }
 
+// SetOverride adds an override file to the pack, replacing any installed to the same path
+func (p *Pack) SetOverride(file *OverrideFile) {
+	if p.Overrides == nil {
+		p.Overrides = make(map[string]*OverrideFile)
+	}
+
+	p.Overrides[file.GetInstallPath()] = file
+}
+
+func (p *Pack) GetOverridesList() []*OverrideFile {
+	overrides := make([]*OverrideFile, 0, len(p.Overrides))
+	for _, override := range p.Overrides {
+		overrides = append(overrides, override)
+	}
+	return overrides
+}
+
 func (p *Pack) ToPackMeta() (PackToml, error) {
 	indexToml, err := p.getIndex()
 	if err != nil {
@@ -171,12 +199,21 @@ func (p *Pack) getIndexRepr() (IndexTomlRepresentation, error) {
 	}
 
 	for _, mod := range p.Mods {
-		entry, err := mod.toIndexEntry()
+		entries, err := mod.toIndexEntries()
 		if err != nil {
 			return IndexTomlRepresentation{}, fmt.Errorf("failed to convert mod %s to index entry: %w", mod.Slug, err)
 		}
+		repr.Files = append(repr.Files, entries...)
+	}
+
+	for _, override := range p.Overrides {
+		entry, err := override.toIndexEntry()
+		if err != nil {
+			return IndexTomlRepresentation{}, fmt.Errorf("failed to convert override file %s to index entry: %w", override.Path, err)
+		}
 		repr.Files = append(repr.Files, entry)
 	}
+	repr.Files.sort()
 
 	return repr, nil
 }
@@ -219,3 +256,38 @@ func (p *Pack) SetAcceptableGameVersions(versions []string) {
 func (p *Pack) GetCompatibleLoaders() (loaders []string) {
 	return compatibleLoadersFrom(p.Versions)
 }
+
+// GetServerPlatform returns the plugin server platform (see ServerPlatforms) the pack uses, if any
+func (p *Pack) GetServerPlatform() (string, bool) {
+	return serverPlatformFrom(p.Versions)
+}
+
+// GetDatapackFolder returns the folder datapacks are installed into (see DatapackFolderOption), or "" if not set
+func (p *Pack) GetDatapackFolder() string {
+	return datapackFolderFrom(p.Options)
+}
+
+// SetDatapackFolder sets the folder datapacks are installed into; an empty folder removes the setting
+func (p *Pack) SetDatapackFolder(folder string) {
+	if p.Options == nil {
+		p.Options = make(map[string]interface{})
+	}
+	setDatapackFolder(p.Options, folder)
+}
+
+// IsDatapack reports whether mod is installed into the pack's datapack folder
+func (p *Pack) IsDatapack(mod *Mod) bool {
+	folder := p.GetDatapackFolder()
+	return folder != "" && path.Clean(filepath.ToSlash(mod.ModType)) == folder
+}
+
+// GetDatapacks returns the mods installed into the pack's datapack folder
+func (p *Pack) GetDatapacks() []*Mod {
+	var datapacks []*Mod
+	for _, mod := range p.Mods {
+		if p.IsDatapack(mod) {
+			datapacks = append(datapacks, mod)
+		}
+	}
+	return datapacks
+}
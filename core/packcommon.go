@@ -3,6 +3,9 @@ package core
 import (
 	"errors"
 	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
 )
 
 // This file holds logic shared between Pack (the in-memory domain type) and
@@ -69,7 +72,8 @@ func supportedMCVersionsFrom(versions map[string]string, options map[string]inte
 }
 
 // compatibleLoadersFrom returns the pack's loaders, including backwards-compatible
-// aliases (quilt implies fabric, neoforge implies forge).
+// aliases (quilt implies fabric, neoforge implies forge) and the plugin APIs implemented
+// by its server platform (purpur implies paper, which implies spigot and bukkit).
 func compatibleLoadersFrom(versions map[string]string) (loaders []string) {
 	if _, hasQuilt := versions["quilt"]; hasQuilt {
 		loaders = append(loaders, "quilt")
@@ -83,5 +87,53 @@ func compatibleLoadersFrom(versions map[string]string) (loaders []string) {
 	} else if _, hasForge := versions["forge"]; hasForge {
 		loaders = append(loaders, "forge")
 	}
+	if platform, ok := serverPlatformFrom(versions); ok {
+		if platform == "purpur" {
+			loaders = append(loaders, "purpur")
+		}
+		loaders = append(loaders, "paper", "spigot", "bukkit")
+	}
 	return
 }
+
+// DatapackFolderOption is the pack.toml option holding the folder datapacks are installed into,
+// such as "config/paxi/datapacks" for Paxi or "world/datapacks" for a server
+const DatapackFolderOption = "datapack-folder"
+
+// datapackFolderFrom returns the pack's datapack folder option, cleaned to a slash-separated
+// relative path, or "" if it isn't set.
+func datapackFolderFrom(options map[string]interface{}) string {
+	folder, ok := options[DatapackFolderOption].(string)
+	if !ok || strings.TrimSpace(folder) == "" {
+		return ""
+	}
+	return path.Clean(strings.Trim(filepath.ToSlash(folder), "/"))
+}
+
+// setDatapackFolder stores folder as the pack's datapack folder, removing the option if it is empty.
+func setDatapackFolder(options map[string]interface{}, folder string) {
+	folder = strings.Trim(filepath.ToSlash(strings.TrimSpace(folder)), "/")
+	if folder == "" {
+		delete(options, DatapackFolderOption)
+		return
+	}
+	options[DatapackFolderOption] = path.Clean(folder)
+}
+
+// IsWorldDatapackFolder reports whether folder is the datapacks folder of a world
+// ("<world>/datapacks" on a server, or "saves/<world>/datapacks" on a client), which
+// Minecraft loads datapacks from without a datapack loader mod.
+func IsWorldDatapackFolder(folder string) bool {
+	folder = path.Clean(filepath.ToSlash(folder))
+	if path.Base(folder) != "datapacks" {
+		return false
+	}
+	parts := strings.Split(path.Dir(folder), "/")
+	switch len(parts) {
+	case 1:
+		return parts[0] != "." && parts[0] != "config"
+	case 2:
+		return parts[0] == "saves"
+	}
+	return false
+}
@@ -167,6 +167,8 @@ func (pack *PackToml) GetCompatibleLoaders() (loaders []string) {
 	return compatibleLoadersFrom(pack.Versions)
 }
 
+// GetLoaders returns the mod loaders the pack uses. Plugin server platforms aren't included, as
+// hybrid packs can use one alongside a mod loader; see GetServerPlatform.
 func (pack *PackToml) GetLoaders() (loaders []string) {
 	if _, hasQuilt := pack.Versions["quilt"]; hasQuilt {
 		loaders = append(loaders, "quilt")
@@ -183,6 +185,24 @@ func (pack *PackToml) GetLoaders() (loaders []string) {
 	return
 }
 
+// GetServerPlatform returns the plugin server platform (see ServerPlatforms) the pack uses, if any
+func (pack *PackToml) GetServerPlatform() (string, bool) {
+	return serverPlatformFrom(pack.Versions)
+}
+
+// GetDatapackFolder returns the folder datapacks are installed into (see DatapackFolderOption), or "" if not set
+func (pack *PackToml) GetDatapackFolder() string {
+	return datapackFolderFrom(pack.Options)
+}
+
+// SetDatapackFolder sets the folder datapacks are installed into; an empty folder removes the setting
+func (pack *PackToml) SetDatapackFolder(folder string) {
+	if pack.Options == nil {
+		pack.Options = make(map[string]interface{})
+	}
+	setDatapackFolder(pack.Options, folder)
+}
+
 func (pack *PackToml) UpdateHash(_, _ string) {
 	// noop for packs
 }
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinReleaseAgeOption is the pack.toml option holding the minimum age of versions mods are
+// updated to, e.g. "72h" or "3d". Mods can override it with their own min-release-age.
+const MinReleaseAgeOption = "min-release-age"
+
+// ParseReleaseAge parses a minimum release age: a Go duration such as "72h" or "36h30m", or a
+// number of days such as "3d". An empty age is zero, which disables the minimum.
+func ParseReleaseAge(age string) (time.Duration, error) {
+	age = strings.TrimSpace(age)
+	if age == "" {
+		return 0, nil
+	}
+	var d time.Duration
+	if days, ok := strings.CutSuffix(age, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid release age %q", age)
+		}
+		d = time.Duration(n * float64(24*time.Hour))
+	} else {
+		var err error
+		d, err = time.ParseDuration(age)
+		if err != nil {
+			return 0, fmt.Errorf("invalid release age %q", age)
+		}
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid release age %q: must not be negative", age)
+	}
+	return d, nil
+}
+
+// minReleaseAgeFrom returns the pack's minimum release age option, or "" if it isn't set
+func minReleaseAgeFrom(options map[string]interface{}) string {
+	age, _ := options[MinReleaseAgeOption].(string)
+	return strings.TrimSpace(age)
+}
+
+// setMinReleaseAge validates and stores age as the pack's minimum release age, removing the
+// option if it is empty
+func setMinReleaseAge(options map[string]interface{}, age string) error {
+	age = strings.TrimSpace(age)
+	if _, err := ParseReleaseAge(age); err != nil {
+		return err
+	}
+	if age == "" {
+		delete(options, MinReleaseAgeOption)
+		return nil
+	}
+	options[MinReleaseAgeOption] = age
+	return nil
+}
+
+// GetMinReleaseAge returns the pack's minimum release age option (see MinReleaseAgeOption), or "" if not set
+func (p *Pack) GetMinReleaseAge() string {
+	return minReleaseAgeFrom(p.Options)
+}
+
+// SetMinReleaseAge sets the pack's minimum release age; an empty age removes the setting
+func (p *Pack) SetMinReleaseAge(age string) error {
+	if p.Options == nil {
+		p.Options = make(map[string]interface{})
+	}
+	return setMinReleaseAge(p.Options, age)
+}
+
+// GetMinReleaseAge returns the pack's minimum release age option (see MinReleaseAgeOption), or "" if not set
+func (pack *PackToml) GetMinReleaseAge() string {
+	return minReleaseAgeFrom(pack.Options)
+}
+
+// SetMinReleaseAge sets the pack's minimum release age; an empty age removes the setting
+func (pack *PackToml) SetMinReleaseAge(age string) error {
+	if pack.Options == nil {
+		pack.Options = make(map[string]interface{})
+	}
+	return setMinReleaseAge(pack.Options, age)
+}
+
+// MinReleaseAgeFor returns the minimum age of versions mod is updated to: its own
+// min-release-age if set (so "0" exempts it), otherwise the pack's
+func (p *Pack) MinReleaseAgeFor(mod *Mod) (time.Duration, error) {
+	age := p.GetMinReleaseAge()
+	if mod.MinReleaseAge != "" {
+		age = mod.MinReleaseAge
+	}
+	d, err := ParseReleaseAge(age)
+	if err != nil {
+		return 0, fmt.Errorf("invalid minimum release age for %s: %w", mod.Name, err)
+	}
+	return d, nil
+}
+
+// ReleaseOldEnough reports whether a version published at the given time is at least minAge
+// old. Versions with an unknown (zero) publish date are always old enough.
+func ReleaseOldEnough(published time.Time, minAge time.Duration) bool {
+	return minAge <= 0 || published.IsZero() || time.Since(published) >= minAge
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReleaseAge(t *testing.T) {
+	cases := []struct {
+		age      string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"0", 0},
+		{"72h", 72 * time.Hour},
+		{"36h30m", 36*time.Hour + 30*time.Minute},
+		{"3d", 72 * time.Hour},
+		{"1.5d", 36 * time.Hour},
+	}
+	for _, tc := range cases {
+		d, err := ParseReleaseAge(tc.age)
+		require.NoError(t, err, tc.age)
+		assert.Equal(t, tc.expected, d, tc.age)
+	}
+
+	for _, age := range []string{"soon", "xd", "-1h"} {
+		_, err := ParseReleaseAge(age)
+		assert.Error(t, err, age)
+	}
+}
+
+func TestPack_MinReleaseAge(t *testing.T) {
+	pack := Pack{}
+	assert.Equal(t, "", pack.GetMinReleaseAge())
+
+	require.NoError(t, pack.SetMinReleaseAge(" 3d "))
+	assert.Equal(t, "3d", pack.GetMinReleaseAge())
+	assert.Error(t, pack.SetMinReleaseAge("soon"))
+	assert.Equal(t, "3d", pack.GetMinReleaseAge(), "invalid ages aren't stored")
+
+	mod := &Mod{Name: "Test"}
+	d, err := pack.MinReleaseAgeFor(mod)
+	require.NoError(t, err)
+	assert.Equal(t, 72*time.Hour, d)
+
+	mod.MinReleaseAge = "0"
+	d, err = pack.MinReleaseAgeFor(mod)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), d, "mods can opt out")
+
+	mod.MinReleaseAge = "soon"
+	_, err = pack.MinReleaseAgeFor(mod)
+	assert.ErrorContains(t, err, "Test")
+
+	require.NoError(t, pack.SetMinReleaseAge(""))
+	assert.NotContains(t, pack.Options, MinReleaseAgeOption)
+}
+
+func TestReleaseOldEnough(t *testing.T) {
+	assert.True(t, ReleaseOldEnough(time.Now(), 0))
+	assert.True(t, ReleaseOldEnough(time.Time{}, time.Hour), "unknown dates are old enough")
+	assert.True(t, ReleaseOldEnough(time.Now().Add(-2*time.Hour), time.Hour))
+	assert.False(t, ReleaseOldEnough(time.Now().Add(-30*time.Minute), time.Hour))
+}
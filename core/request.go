@@ -30,3 +30,14 @@ func GetWithUAContext(ctx context.Context, url string, contentType string) (resp
 	req.Header.Set("Accept", contentType)
 	return defaultRequestClient.Do(req)
 }
+
+// HeadWithUA performs a HEAD request with the packwiz User-Agent, e.g. to check whether a
+// file has changed from its ETag/Last-Modified headers without downloading it.
+func HeadWithUA(url string) (resp *http.Response, err error) {
+	req, err := http.NewRequest("HEAD", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	return defaultRequestClient.Do(req)
+}
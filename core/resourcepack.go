@@ -0,0 +1,205 @@
+package core
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ResourcePackFolder is the folder resource packs are installed into
+const ResourcePackFolder = "resourcepacks"
+
+// resourcePackFormats maps the first release of Minecraft using each resource pack format
+// to the format, oldest first
+var resourcePackFormats = []struct {
+	MCVersion string
+	Format    int
+}{
+	{"1.6.1", 1},
+	{"1.9", 2},
+	{"1.11", 3},
+	{"1.13", 4},
+	{"1.15", 5},
+	{"1.16.2", 6},
+	{"1.17", 7},
+	{"1.18", 8},
+	{"1.19", 9},
+	{"1.19.3", 12},
+	{"1.19.4", 13},
+	{"1.20", 15},
+	{"1.20.2", 18},
+	{"1.20.3", 22},
+	{"1.20.5", 32},
+	{"1.21", 34},
+	{"1.21.2", 42},
+	{"1.21.4", 46},
+	{"1.21.5", 55},
+	{"1.21.6", 63},
+	{"1.21.7", 64},
+}
+
+// resourcePackFormatsKnownUntil is the last release covered by resourcePackFormats
+const resourcePackFormatsKnownUntil = "1.21.8"
+
+var releaseVersionRegex = regexp.MustCompile(`^\d+\.\d+(\.\d+)?$`)
+
+// ResourcePackFormatFor returns the resource pack format used by a release of Minecraft. It
+// returns false for snapshots, and for releases too old or too new to be known.
+func ResourcePackFormatFor(mcVersion string) (int, bool) {
+	if !releaseVersionRegex.MatchString(mcVersion) ||
+		CompareVersions(mcVersion, resourcePackFormats[0].MCVersion) < 0 ||
+		CompareVersions(mcVersion, resourcePackFormatsKnownUntil) > 0 {
+		return 0, false
+	}
+	format := 0
+	for _, v := range resourcePackFormats {
+		if CompareVersions(mcVersion, v.MCVersion) >= 0 {
+			format = v.Format
+		}
+	}
+	return format, true
+}
+
+// ResourcePackMeta is the pack section of a resource pack's pack.mcmeta
+type ResourcePackMeta struct {
+	PackFormat int
+	// MinFormat and MaxFormat are the range of formats the pack declares support for, if any
+	MinFormat int
+	MaxFormat int
+}
+
+// Supports reports whether the resource pack declares support for the given format
+func (m ResourcePackMeta) Supports(format int) bool {
+	if m.MinFormat != 0 || m.MaxFormat != 0 {
+		if format >= m.MinFormat && format <= m.MaxFormat {
+			return true
+		}
+	}
+	return m.PackFormat == format
+}
+
+// FormatString returns the format(s) the resource pack supports, for messages
+func (m ResourcePackMeta) FormatString() string {
+	if (m.MinFormat != 0 || m.MaxFormat != 0) && m.MinFormat != m.MaxFormat {
+		return fmt.Sprintf("%d-%d", m.MinFormat, m.MaxFormat)
+	}
+	return fmt.Sprint(m.PackFormat)
+}
+
+// parseFormatRange reads a supported_formats value (a number, [min, max] or
+// {"min_inclusive": min, "max_inclusive": max}) or a min_format/max_format value (a
+// number, or [major, minor])
+func parseFormatRange(raw json.RawMessage) (minFormat int, maxFormat int, err error) {
+	var single int
+	if err = json.Unmarshal(raw, &single); err == nil {
+		return single, single, nil
+	}
+	var list []int
+	if err = json.Unmarshal(raw, &list); err == nil && len(list) > 0 {
+		return list[0], list[len(list)-1], nil
+	}
+	var object struct {
+		Min int `json:"min_inclusive"`
+		Max int `json:"max_inclusive"`
+	}
+	if err = json.Unmarshal(raw, &object); err == nil {
+		return object.Min, object.Max, nil
+	}
+	return 0, 0, fmt.Errorf("invalid format range %s", raw)
+}
+
+// ParseResourcePackMeta parses the contents of a pack.mcmeta file
+func ParseResourcePackMeta(data []byte) (ResourcePackMeta, error) {
+	var mcmeta struct {
+		Pack struct {
+			PackFormat       int             `json:"pack_format"`
+			SupportedFormats json.RawMessage `json:"supported_formats"`
+			MinFormat        json.RawMessage `json:"min_format"`
+			MaxFormat        json.RawMessage `json:"max_format"`
+		} `json:"pack"`
+	}
+	if err := json.Unmarshal(data, &mcmeta); err != nil {
+		return ResourcePackMeta{}, fmt.Errorf("invalid pack.mcmeta: %w", err)
+	}
+
+	meta := ResourcePackMeta{PackFormat: mcmeta.Pack.PackFormat}
+	var err error
+	if len(mcmeta.Pack.SupportedFormats) > 0 {
+		meta.MinFormat, meta.MaxFormat, err = parseFormatRange(mcmeta.Pack.SupportedFormats)
+		if err != nil {
+			return ResourcePackMeta{}, err
+		}
+	}
+	// Formats from 1.21.9 are major.minor versions; only the major version is compared
+	if len(mcmeta.Pack.MinFormat) > 0 && len(mcmeta.Pack.MaxFormat) > 0 {
+		if meta.MinFormat, _, err = parseFormatRange(mcmeta.Pack.MinFormat); err != nil {
+			return ResourcePackMeta{}, err
+		}
+		if meta.MaxFormat, _, err = parseFormatRange(mcmeta.Pack.MaxFormat); err != nil {
+			return ResourcePackMeta{}, err
+		}
+		if meta.PackFormat == 0 {
+			meta.PackFormat = meta.MaxFormat
+		}
+	}
+	if meta.PackFormat == 0 && meta.MaxFormat == 0 {
+		return ResourcePackMeta{}, errors.New("pack.mcmeta doesn't declare a pack format")
+	}
+	return meta, nil
+}
+
+// ReadResourcePackMeta reads the pack.mcmeta file of a zipped resource pack
+func ReadResourcePackMeta(file io.ReaderAt, size int64) (ResourcePackMeta, error) {
+	archive, err := zip.NewReader(file, size)
+	if err != nil {
+		return ResourcePackMeta{}, fmt.Errorf("not a zip file: %w", err)
+	}
+	f, err := archive.Open("pack.mcmeta")
+	if err != nil {
+		return ResourcePackMeta{}, fmt.Errorf("no pack.mcmeta: %w", err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return ResourcePackMeta{}, err
+	}
+	return ParseResourcePackMeta(data)
+}
+
+// IsResourcePack reports whether mod is a zipped resource pack
+func IsResourcePack(mod *Mod) bool {
+	return mod.ModType == ResourcePackFolder && strings.HasSuffix(strings.ToLower(mod.FileName), ".zip")
+}
+
+// checkResourcePackFormat warns about resource packs that don't support the resource pack
+// format of the pack's Minecraft version
+func checkResourcePackFormat(pack *Pack, mod *Mod, file io.ReaderAt, size int64) []PackIssue {
+	mcVersion, err := pack.GetMCVersion()
+	if err != nil {
+		return nil
+	}
+	format, ok := ResourcePackFormatFor(mcVersion)
+	if !ok {
+		return nil
+	}
+
+	meta, err := ReadResourcePackMeta(file, size)
+	if err != nil {
+		return []PackIssue{{
+			Message: fmt.Sprintf("%s (%s) couldn't be read as a resource pack: %v", mod.Name, mod.FileName, err),
+			Mods:    []string{mod.Slug},
+		}}
+	}
+	if meta.Supports(format) {
+		return nil
+	}
+	return []PackIssue{{
+		Message: fmt.Sprintf("%s (%s) is made for resource pack format %s, but Minecraft %s uses format %d",
+			mod.Name, mod.FileName, meta.FormatString(), mcVersion, format),
+		Mods: []string{mod.Slug},
+	}}
+}
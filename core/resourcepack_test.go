@@ -0,0 +1,107 @@
+package core
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func zipWithMcmeta(t *testing.T, mcmeta string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	if mcmeta != "" {
+		f, err := w.Create("pack.mcmeta")
+		require.NoError(t, err)
+		_, err = f.Write([]byte(mcmeta))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestResourcePackFormatFor(t *testing.T) {
+	tests := map[string]int{
+		"1.8.9":  1,
+		"1.12.2": 3,
+		"1.16.1": 5,
+		"1.16.5": 6,
+		"1.20":   15,
+		"1.20.1": 15,
+		"1.20.4": 22,
+		"1.21.1": 34,
+		"1.21.8": 64,
+	}
+	for version, format := range tests {
+		got, ok := ResourcePackFormatFor(version)
+		assert.True(t, ok, version)
+		assert.Equal(t, format, got, version)
+	}
+
+	for _, version := range []string{"24w14a", "1.21.9", "1.5.2", "1.20-pre1"} {
+		_, ok := ResourcePackFormatFor(version)
+		assert.False(t, ok, version)
+	}
+}
+
+func TestParseResourcePackMeta(t *testing.T) {
+	meta, err := ParseResourcePackMeta([]byte(`{"pack":{"pack_format":15,"description":"x"}}`))
+	require.NoError(t, err)
+	assert.True(t, meta.Supports(15))
+	assert.False(t, meta.Supports(18))
+	assert.Equal(t, "15", meta.FormatString())
+
+	meta, err = ParseResourcePackMeta([]byte(`{"pack":{"pack_format":15,"supported_formats":[15,22]}}`))
+	require.NoError(t, err)
+	assert.True(t, meta.Supports(18))
+	assert.False(t, meta.Supports(32))
+	assert.Equal(t, "15-22", meta.FormatString())
+
+	meta, err = ParseResourcePackMeta([]byte(`{"pack":{"pack_format":34,"supported_formats":{"min_inclusive":32,"max_inclusive":42}}}`))
+	require.NoError(t, err)
+	assert.True(t, meta.Supports(42))
+
+	meta, err = ParseResourcePackMeta([]byte(`{"pack":{"min_format":64,"max_format":[69,0]}}`))
+	require.NoError(t, err)
+	assert.True(t, meta.Supports(64))
+	assert.True(t, meta.Supports(69))
+
+	_, err = ParseResourcePackMeta([]byte(`{"pack":{"description":"x"}}`))
+	assert.Error(t, err)
+}
+
+func TestCheckResourcePackFormat(t *testing.T) {
+	pack := NewPack("Pack", "dev", "1.0.0", "", "1.21.1", LoaderInfo{"fabric": "0.16.5"})
+	mod := NewMod("faithful", "Faithful 32x", "Faithful.zip", "client", ResourcePackFolder, "", false, false, nil, ModDownload{}, nil)
+	pack.SetMod(mod)
+
+	assert.Equal(t, []*Mod{mod}, ModsToCheckFiles(pack.GetModsList()))
+
+	t.Run("matching format", func(t *testing.T) {
+		file := zipWithMcmeta(t, `{"pack":{"pack_format":34}}`)
+		assert.Empty(t, CheckModFile(pack, mod, file, file.Size()))
+	})
+
+	t.Run("mismatched format", func(t *testing.T) {
+		file := zipWithMcmeta(t, `{"pack":{"pack_format":15}}`)
+		issues := CheckModFile(pack, mod, file, file.Size())
+		require.Len(t, issues, 1)
+		assert.Equal(t, "resourcepack-format", issues[0].Check)
+		assert.Contains(t, issues[0].Message, "format 15, but Minecraft 1.21.1 uses format 34")
+	})
+
+	t.Run("missing pack.mcmeta", func(t *testing.T) {
+		file := zipWithMcmeta(t, "")
+		issues := CheckModFile(pack, mod, file, file.Size())
+		require.Len(t, issues, 1)
+		assert.Contains(t, issues[0].Message, "couldn't be read as a resource pack")
+	})
+
+	t.Run("unzipped files aren't checked", func(t *testing.T) {
+		folder := NewMod("folder", "Folder", "pack.txt", "client", ResourcePackFolder, "", false, false, nil, ModDownload{}, nil)
+		assert.Empty(t, ModsToCheckFiles([]*Mod{folder}))
+	})
+}
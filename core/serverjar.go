@@ -0,0 +1,230 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// API base URLs of the plugin server platforms; variables so tests can replace them
+var (
+	paperApiUrl  = "https://fill.papermc.io/v3/projects/paper"
+	purpurApiUrl = "https://api.purpurmc.org/v2/purpur"
+)
+
+// ServerPlatforms are the pack.toml Versions keys of plugin server platforms, most
+// specific first (Purpur is a fork of Paper, so runs Paper plugins)
+var ServerPlatforms = []string{"purpur", "paper"}
+
+// serverPlatformFrom returns the plugin server platform set in a pack's Versions map,
+// if there is one
+func serverPlatformFrom(versions map[string]string) (string, bool) {
+	for _, platform := range ServerPlatforms {
+		if _, ok := versions[platform]; ok {
+			return platform, true
+		}
+	}
+	return "", false
+}
+
+// ServerJar is a build of a plugin server platform's server JAR
+type ServerJar struct {
+	Platform   string
+	MCVersion  string
+	Build      string
+	FileName   string
+	URL        string
+	HashFormat string
+	Hash       string
+}
+
+// AsMod returns a Mod downloading the JAR into the pack's root folder, so it can be fetched
+// (and verified) with a download session like any other file
+func (j ServerJar) AsMod() *Mod {
+	return NewMod(
+		j.Platform,
+		ComponentToFriendlyName(j.Platform)+" server",
+		j.FileName,
+		ServerSide,
+		"",
+		"",
+		false,
+		false,
+		nil,
+		ModDownload{
+			URL:        j.URL,
+			HashFormat: j.HashFormat,
+			Hash:       j.Hash,
+		},
+		nil,
+	)
+}
+
+// GetServerJar looks up the server JAR of build of platform ("paper" or "purpur") for
+// mcVersion
+func GetServerJar(platform string, mcVersion string, build string) (ServerJar, error) {
+	switch platform {
+	case "paper":
+		return getPaperServerJar(mcVersion, build)
+	case "purpur":
+		return getPurpurServerJar(mcVersion, build)
+	default:
+		return ServerJar{}, fmt.Errorf("%s is not a server platform", platform)
+	}
+}
+
+func getServerPlatformJson(url string, v any) error {
+	resp, err := GetWithUA(url, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch %s: invalid status code %v", url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", url, err)
+	}
+	return nil
+}
+
+type paperBuild struct {
+	ID        int    `json:"id"`
+	Channel   string `json:"channel"`
+	Downloads map[string]struct {
+		Name      string `json:"name"`
+		URL       string `json:"url"`
+		Checksums struct {
+			Sha256 string `json:"sha256"`
+		} `json:"checksums"`
+	} `json:"downloads"`
+}
+
+// stable returns true for builds of the stable (or recommended) channels
+func (b paperBuild) stable() bool {
+	return b.Channel == "STABLE" || b.Channel == "RECOMMENDED"
+}
+
+// fetchPaperBuilds fetches the builds of Paper for mcVersion, newest first
+func fetchPaperBuilds(mcVersion string) ([]paperBuild, error) {
+	var builds []paperBuild
+	if err := getServerPlatformJson(paperApiUrl+"/versions/"+url.PathEscape(mcVersion)+"/builds", &builds); err != nil {
+		return nil, fmt.Errorf("failed to fetch Paper builds: %w", err)
+	}
+	if len(builds) == 0 {
+		return nil, fmt.Errorf("no Paper builds found for Minecraft %s", mcVersion)
+	}
+	return builds, nil
+}
+
+// fetchPaperVersions returns the Paper builds for mcVersion, newest first, and the newest
+// stable build (or the newest build, if none are stable yet)
+func fetchPaperVersions(mcVersion string) ([]string, string, error) {
+	builds, err := fetchPaperBuilds(mcVersion)
+	if err != nil {
+		return nil, "", err
+	}
+
+	versions := make([]string, len(builds))
+	latest := ""
+	for i, build := range builds {
+		versions[i] = fmt.Sprint(build.ID)
+		if latest == "" && build.stable() {
+			latest = versions[i]
+		}
+	}
+	if latest == "" {
+		latest = versions[0]
+	}
+	return versions, latest, nil
+}
+
+func getPaperServerJar(mcVersion string, build string) (ServerJar, error) {
+	builds, err := fetchPaperBuilds(mcVersion)
+	if err != nil {
+		return ServerJar{}, err
+	}
+
+	for _, b := range builds {
+		if fmt.Sprint(b.ID) != build {
+			continue
+		}
+		download, ok := b.Downloads["server:default"]
+		if !ok {
+			return ServerJar{}, fmt.Errorf("Paper build %s has no server download", build)
+		}
+		return ServerJar{
+			Platform:   "paper",
+			MCVersion:  mcVersion,
+			Build:      build,
+			FileName:   download.Name,
+			URL:        download.URL,
+			HashFormat: "sha256",
+			Hash:       download.Checksums.Sha256,
+		}, nil
+	}
+	return ServerJar{}, fmt.Errorf("Paper build %s not found for Minecraft %s", build, mcVersion)
+}
+
+type purpurVersion struct {
+	Builds struct {
+		Latest string   `json:"latest"`
+		All    []string `json:"all"`
+	} `json:"builds"`
+}
+
+// fetchPurpurVersions returns the Purpur builds for mcVersion, newest first, and the newest build
+func fetchPurpurVersions(mcVersion string) ([]string, string, error) {
+	var version purpurVersion
+	if err := getServerPlatformJson(purpurApiUrl+"/"+url.PathEscape(mcVersion), &version); err != nil {
+		return nil, "", fmt.Errorf("failed to fetch Purpur builds: %w", err)
+	}
+	if len(version.Builds.All) == 0 {
+		return nil, "", fmt.Errorf("no Purpur builds found for Minecraft %s", mcVersion)
+	}
+
+	// Builds are listed oldest first
+	versions := make([]string, 0, len(version.Builds.All))
+	for i := len(version.Builds.All) - 1; i >= 0; i-- {
+		versions = append(versions, version.Builds.All[i])
+	}
+	latest := version.Builds.Latest
+	if latest == "" {
+		latest = versions[0]
+	}
+	return versions, latest, nil
+}
+
+func getPurpurServerJar(mcVersion string, build string) (ServerJar, error) {
+	buildUrl := purpurApiUrl + "/" + url.PathEscape(mcVersion) + "/" + url.PathEscape(build)
+
+	var info struct {
+		Result string `json:"result"`
+		MD5    string `json:"md5"`
+	}
+	if err := getServerPlatformJson(buildUrl, &info); err != nil {
+		return ServerJar{}, fmt.Errorf("failed to fetch Purpur build %s: %w", build, err)
+	}
+	if info.Result != "SUCCESS" || info.MD5 == "" {
+		return ServerJar{}, errors.New("Purpur build " + build + " has no server download")
+	}
+
+	return ServerJar{
+		Platform:   "purpur",
+		MCVersion:  mcVersion,
+		Build:      build,
+		FileName:   "purpur-" + mcVersion + "-" + build + ".jar",
+		URL:        buildUrl + "/download",
+		HashFormat: "md5",
+		Hash:       info.MD5,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withServerPlatformApis points the Paper and Purpur APIs at a test server for the duration
+// of the test
+func withServerPlatformApis(t *testing.T) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/paper/versions/1.21.4/builds":
+			_, _ = w.Write([]byte(`[` +
+				`{"id":232,"channel":"BETA","downloads":{"server:default":{"name":"paper-1.21.4-232.jar","url":"https://fill-data.papermc.io/v1/objects/abc/paper-1.21.4-232.jar","checksums":{"sha256":"abc"}}}},` +
+				`{"id":231,"channel":"STABLE","downloads":{"server:default":{"name":"paper-1.21.4-231.jar","url":"https://fill-data.papermc.io/v1/objects/def/paper-1.21.4-231.jar","checksums":{"sha256":"def"}}}}` +
+				`]`))
+		case "/paper/versions/1.99/builds":
+			_, _ = w.Write([]byte(`[]`))
+		case "/purpur/1.21.4":
+			_, _ = w.Write([]byte(`{"project":"purpur","version":"1.21.4","builds":{"latest":"2416","all":["2414","2415","2416"]}}`))
+		case "/purpur/1.21.4/2416":
+			_, _ = w.Write([]byte(`{"build":"2416","result":"SUCCESS","md5":"0123456789abcdef"}`))
+		case "/purpur/1.21.4/2415":
+			_, _ = w.Write([]byte(`{"build":"2415","result":"FAILURE"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	originalPaper, originalPurpur := paperApiUrl, purpurApiUrl
+	paperApiUrl, purpurApiUrl = server.URL+"/paper", server.URL+"/purpur"
+	t.Cleanup(func() { paperApiUrl, purpurApiUrl = originalPaper, originalPurpur })
+}
+
+func TestFetchPaperVersions(t *testing.T) {
+	withServerPlatformApis(t)
+
+	versions, latest, err := ModLoaders["paper"].VersionListGetter("1.21.4")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"232", "231"}, versions)
+	assert.Equal(t, "231", latest, "the newest stable build is preferred")
+
+	_, _, err = fetchPaperVersions("1.99")
+	assert.Error(t, err)
+}
+
+func TestFetchPurpurVersions(t *testing.T) {
+	withServerPlatformApis(t)
+
+	versions, latest, err := ModLoaders["purpur"].VersionListGetter("1.21.4")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2416", "2415", "2414"}, versions)
+	assert.Equal(t, "2416", latest)
+
+	_, _, err = fetchPurpurVersions("1.99")
+	assert.Error(t, err)
+}
+
+func TestGetServerJar(t *testing.T) {
+	withServerPlatformApis(t)
+
+	t.Run("paper", func(t *testing.T) {
+		jar, err := GetServerJar("paper", "1.21.4", "231")
+		require.NoError(t, err)
+		assert.Equal(t, ServerJar{
+			Platform:   "paper",
+			MCVersion:  "1.21.4",
+			Build:      "231",
+			FileName:   "paper-1.21.4-231.jar",
+			URL:        "https://fill-data.papermc.io/v1/objects/def/paper-1.21.4-231.jar",
+			HashFormat: "sha256",
+			Hash:       "def",
+		}, jar)
+
+		_, err = GetServerJar("paper", "1.21.4", "1")
+		assert.Error(t, err)
+	})
+
+	t.Run("purpur", func(t *testing.T) {
+		jar, err := GetServerJar("purpur", "1.21.4", "2416")
+		require.NoError(t, err)
+		assert.Equal(t, "purpur-1.21.4-2416.jar", jar.FileName)
+		assert.Equal(t, purpurApiUrl+"/1.21.4/2416/download", jar.URL)
+		assert.Equal(t, "md5", jar.HashFormat)
+		assert.Equal(t, "0123456789abcdef", jar.Hash)
+
+		_, err = GetServerJar("purpur", "1.21.4", "2415")
+		assert.Error(t, err, "failed builds have no download")
+	})
+
+	t.Run("unknown platform", func(t *testing.T) {
+		_, err := GetServerJar("fabric", "1.21.4", "0.16.0")
+		assert.Error(t, err)
+	})
+
+	t.Run("as mod", func(t *testing.T) {
+		jar, err := GetServerJar("paper", "1.21.4", "231")
+		require.NoError(t, err)
+		mod := jar.AsMod()
+		assert.Equal(t, "paper-1.21.4-231.jar", mod.GetRelDownloadPath())
+		assert.Equal(t, jar.URL, mod.Download.URL)
+		assert.Equal(t, "def", mod.Download.Hash)
+	})
+}
+
+func TestServerPlatformLoaders(t *testing.T) {
+	pack := Pack{Versions: map[string]string{"minecraft": "1.21.4", "purpur": "2416"}}
+	platform, ok := pack.GetServerPlatform()
+	assert.True(t, ok)
+	assert.Equal(t, "purpur", platform)
+	assert.Equal(t, []string{"purpur", "paper", "spigot", "bukkit"}, pack.GetCompatibleLoaders())
+
+	pack = Pack{Versions: map[string]string{"minecraft": "1.21.4", "fabric": "0.16.0", "paper": "231"}}
+	assert.Equal(t, []string{"fabric", "paper", "spigot", "bukkit"}, pack.GetCompatibleLoaders())
+
+	packToml := PackToml{Versions: map[string]string{"minecraft": "1.21.4", "paper": "231"}}
+	assert.Empty(t, packToml.GetLoaders())
+
+	// Hybrid packs have a single mod loader, alongside the server platform
+	packToml = PackToml{Versions: map[string]string{"minecraft": "1.21.4", "fabric": "0.16.0", "paper": "231"}}
+	assert.Equal(t, []string{"fabric"}, packToml.GetLoaders())
+	platform, ok = packToml.GetServerPlatform()
+	assert.True(t, ok)
+	assert.Equal(t, "paper", platform)
+
+	pack = Pack{Versions: map[string]string{"minecraft": "1.21.4"}}
+	_, ok = pack.GetServerPlatform()
+	assert.False(t, ok)
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Shader pack formats, named after the Modrinth loader of shader pack versions
+const (
+	// ShaderFormatIris is for shader packs made for Iris (a superset of the OptiFine format)
+	ShaderFormatIris = "iris"
+	// ShaderFormatOptifine is for shader packs made for OptiFine
+	ShaderFormatOptifine = "optifine"
+	// ShaderFormatCanvas is for Canvas pipelines, which are installed as resource packs
+	ShaderFormatCanvas = "canvas"
+	// ShaderFormatVanilla is for core shaders, which are resource packs that don't need a loader
+	ShaderFormatVanilla = "vanilla"
+)
+
+// ShaderPackFolder is the folder Iris and OptiFine format shader packs are installed into
+const ShaderPackFolder = "shaderpacks"
+
+// ShaderLoader is a mod that loads shader packs
+type ShaderLoader struct {
+	Name string
+	// Slugs are the slugs the mod is published under, on Modrinth and CurseForge
+	Slugs []string
+	// InstallSlug is the Modrinth slug to install the mod from, or "" if it isn't on Modrinth
+	InstallSlug string
+	// ModLoaders are the mod loaders the mod is available for
+	ModLoaders []string
+	// Formats are the shader pack formats the mod loads
+	Formats []string
+}
+
+// ShaderLoaders are the known shader loader mods, in order of preference
+var ShaderLoaders = []ShaderLoader{
+	{
+		Name:        "Iris",
+		Slugs:       []string{"iris", "irisshaders"},
+		InstallSlug: "iris",
+		ModLoaders:  []string{"fabric", "quilt", "neoforge"},
+		Formats:     []string{ShaderFormatIris, ShaderFormatOptifine},
+	},
+	{
+		Name:        "Oculus",
+		Slugs:       []string{"oculus"},
+		InstallSlug: "oculus",
+		ModLoaders:  []string{"forge"},
+		Formats:     []string{ShaderFormatIris, ShaderFormatOptifine},
+	},
+	{
+		Name:        "Canvas",
+		Slugs:       []string{"canvas", "canvas-renderer"},
+		InstallSlug: "canvas",
+		ModLoaders:  []string{"fabric", "quilt"},
+		Formats:     []string{ShaderFormatCanvas},
+	},
+	{
+		Name:       "OptiFine",
+		Slugs:      []string{"optifine", "optifabric"},
+		ModLoaders: []string{"forge", "fabric"},
+		Formats:    []string{ShaderFormatOptifine},
+	},
+}
+
+// ShaderLoadersFor returns the shader loaders that load the given shader pack format
+func ShaderLoadersFor(format string) []ShaderLoader {
+	var loaders []ShaderLoader
+	for _, loader := range ShaderLoaders {
+		if slices.Contains(loader.Formats, format) {
+			loaders = append(loaders, loader)
+		}
+	}
+	return loaders
+}
+
+// FindShaderLoader returns a shader loader installed in the pack that loads the given format
+func (p *Pack) FindShaderLoader(format string) (ShaderLoader, bool) {
+	for _, loader := range ShaderLoadersFor(format) {
+		if p.HasMod(loader.Slugs...) {
+			return loader, true
+		}
+	}
+	return ShaderLoader{}, false
+}
+
+// SuggestShaderLoader returns the preferred installable shader loader for the given format that
+// is available for the pack's mod loaders
+func (p *Pack) SuggestShaderLoader(format string) (ShaderLoader, bool) {
+	packLoaders := p.GetCompatibleLoaders()
+	for _, loader := range ShaderLoadersFor(format) {
+		if loader.InstallSlug == "" {
+			continue
+		}
+		for _, modLoader := range loader.ModLoaders {
+			if slices.Contains(packLoaders, modLoader) {
+				return loader, true
+			}
+		}
+	}
+	return ShaderLoader{}, false
+}
+
+// IsShaderPack reports whether mod is an Iris or OptiFine format shader pack
+func IsShaderPack(mod *Mod) bool {
+	return mod.ModType == ShaderPackFolder
+}
+
+func shaderLoaderNames(loaders []ShaderLoader) string {
+	names := make([]string, 0, len(loaders))
+	for _, loader := range loaders {
+		names = append(names, loader.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// checkShaderLoader warns about shader packs installed in a pack without a shader loader mod
+func checkShaderLoader(pack *Pack) []PackIssue {
+	var shaderPacks []*Mod
+	for _, mod := range pack.Mods {
+		if IsShaderPack(mod) {
+			shaderPacks = append(shaderPacks, mod)
+		}
+	}
+	if len(shaderPacks) == 0 {
+		return nil
+	}
+	// Iris and OptiFine format shader packs are both loaded by a loader of the OptiFine format
+	if _, ok := pack.FindShaderLoader(ShaderFormatOptifine); ok {
+		return nil
+	}
+
+	message := fmt.Sprintf("%d shader pack(s) are installed, but the pack has no shader loader mod (%s) to load them",
+		len(shaderPacks), shaderLoaderNames(ShaderLoadersFor(ShaderFormatOptifine)))
+	if loader, ok := pack.SuggestShaderLoader(ShaderFormatIris); ok {
+		message += fmt.Sprintf("; install %s with 'packwiz modrinth add %s'", loader.Name, loader.InstallSlug)
+	}
+	slugs := modSlugs(shaderPacks)
+	return []PackIssue{{
+		Message: message + ": " + strings.Join(slugs, ", "),
+		Mods:    slugs,
+	}}
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func shaderTestPack(loader string) *Pack {
+	pack := NewPack("Pack", "dev", "1.0.0", "", "1.21.1", LoaderInfo{loader: "1.0.0"})
+	pack.SetMod(NewMod("complementary-reimagined", "Complementary Shaders - Reimagined", "ComplementaryReimagined.zip", "client", ShaderPackFolder, "", false, false, nil, ModDownload{}, nil))
+	return pack
+}
+
+func TestShaderLoadersFor(t *testing.T) {
+	assert.Equal(t, "Iris, Oculus, OptiFine", shaderLoaderNames(ShaderLoadersFor(ShaderFormatOptifine)))
+	assert.Equal(t, "Iris, Oculus", shaderLoaderNames(ShaderLoadersFor(ShaderFormatIris)))
+	assert.Equal(t, "Canvas", shaderLoaderNames(ShaderLoadersFor(ShaderFormatCanvas)))
+	assert.Empty(t, ShaderLoadersFor(ShaderFormatVanilla))
+}
+
+func TestSuggestShaderLoader(t *testing.T) {
+	loader, ok := shaderTestPack("fabric").SuggestShaderLoader(ShaderFormatIris)
+	require.True(t, ok)
+	assert.Equal(t, "Iris", loader.Name)
+
+	loader, ok = shaderTestPack("forge").SuggestShaderLoader(ShaderFormatIris)
+	require.True(t, ok)
+	assert.Equal(t, "oculus", loader.InstallSlug)
+
+	_, ok = shaderTestPack("forge").SuggestShaderLoader(ShaderFormatCanvas)
+	assert.False(t, ok, "Canvas isn't available for Forge")
+}
+
+func TestCheckShaderLoader(t *testing.T) {
+	t.Run("shader pack without a loader", func(t *testing.T) {
+		issues := CheckPack(shaderTestPack("quilt"))
+		require.Len(t, issues, 1)
+		assert.Equal(t, "shader-loader", issues[0].Check)
+		assert.Equal(t, []string{"complementary-reimagined"}, issues[0].Mods)
+		assert.Contains(t, issues[0].Message, "packwiz modrinth add iris")
+	})
+
+	t.Run("shader pack with a loader", func(t *testing.T) {
+		pack := shaderTestPack("forge")
+		pack.SetMod(NewMod("oculus", "Oculus", "oculus.jar", "client", "mods", "", false, false, nil, ModDownload{}, nil))
+		assert.Empty(t, CheckPack(pack))
+	})
+
+	t.Run("OptiFine loads shader packs", func(t *testing.T) {
+		pack := shaderTestPack("forge")
+		pack.SetMod(NewMod("optifine", "OptiFine", "OptiFine_1.21.1_HD_U_J1.jar", "client", "mods", "", false, false, nil, ModDownload{}, nil))
+		assert.Empty(t, CheckPack(pack))
+	})
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSourceUnavailable is returned (possibly wrapped) by Source methods that can't be used
+// in the current configuration - for example a provider that requires an API key which
+// hasn't been set, or one that has no search API. Callers combining several Sources can
+// skip Sources that return it.
+var ErrSourceUnavailable = errors.New("source unavailable")
+
+// ErrProjectNotFound is returned (possibly wrapped) by Source.ResolveProject when the
+// provider has no project with the given identifier, as opposed to failing to look it up
+var ErrProjectNotFound = errors.New("project not found")
+
+// SourceRequest carries the pack being installed into and any per-request options to every
+// Source method.
+type SourceRequest struct {
+	Pack Pack
+	// MetaFolder overrides the folder new metadata files are placed in; if empty, the
+	// Source picks a folder based on the project type (mods, resourcepacks, etc.)
+	MetaFolder string
+	// Options holds provider-specific settings, such as a GitHub asset regex or a
+	// Modrinth version filename. Sources ignore options they don't recognise.
+	Options map[string]string
+}
+
+// Option returns the named provider-specific option, or "" if it isn't set.
+func (r SourceRequest) Option(name string) string {
+	return r.Options[name]
+}
+
+// SourceProject is a project found or resolved by a Source
+type SourceProject struct {
+	// Source is the name of the Source this project came from
+	Source    string
+	ID        string
+	Slug      string
+	Name      string
+	Summary   string
+	Downloads uint64
+	// Data holds provider-specific state (e.g. API responses, or a version requested in the
+	// project URL) for use by later calls on the same Source
+	Data any
+}
+
+// SourceVersion is an installable version of a SourceProject
+type SourceVersion struct {
+	ID string
+	// Version is a human-readable version label, such as a version number or release tag
+	Version  string
+	FileName string
+	// Data holds provider-specific state for use by later calls on the same Source
+	Data any
+}
+
+// Source finds projects from a provider and builds the Mod metadata to install them. It
+// complements Updater, which keeps already installed mods up to date.
+type Source interface {
+	// GetName returns the name of the source; Sources that also update the mods they
+	// create should use the same name as their Updater
+	GetName() string
+	// Search returns projects matching query that are compatible with the pack, best
+	// matches first
+	Search(query string, req SourceRequest) ([]SourceProject, error)
+	// ResolveProject looks up a single project from a provider-specific identifier: a slug,
+	// ID or URL. Versions or files referenced by a URL are recorded so that SelectVersion
+	// prefers them. It returns ErrProjectNotFound if the provider reports there is no such
+	// project, where the provider makes that distinguishable.
+	ResolveProject(identifier string, req SourceRequest) (SourceProject, error)
+	// ListVersions returns candidate versions of project that are compatible with the pack
+	ListVersions(project SourceProject, req SourceRequest) ([]SourceVersion, error)
+	// SelectVersion chooses the version to install from versions, usually the latest one
+	SelectVersion(project SourceProject, versions []SourceVersion, req SourceRequest) (SourceVersion, error)
+	// NewMod builds the metadata for installing version of project. It doesn't download
+	// the file or add the mod to the pack.
+	NewMod(project SourceProject, version SourceVersion, req SourceRequest) (*Mod, error)
+	// FindMissingDependencies returns Mods for the required dependencies of version that
+	// aren't already in the pack
+	FindMissingDependencies(version SourceVersion, req SourceRequest) ([]*Mod, error)
+}
+
+// NewModFromSource resolves identifier using src, selects the version to install and
+// builds its Mod. The resolved version is also returned so that callers can look up its
+// dependencies with FindMissingDependencies.
+func NewModFromSource(src Source, identifier string, req SourceRequest) (*Mod, SourceVersion, error) {
+	project, err := src.ResolveProject(identifier, req)
+	if err != nil {
+		return nil, SourceVersion{}, err
+	}
+	return NewModFromSourceProject(src, project, req)
+}
+
+// NewModFromSourceProject selects the version of an already resolved (or searched) project
+// to install, and builds its Mod.
+func NewModFromSourceProject(src Source, project SourceProject, req SourceRequest) (*Mod, SourceVersion, error) {
+	versions, err := src.ListVersions(project, req)
+	if err != nil {
+		return nil, SourceVersion{}, err
+	}
+	if len(versions) == 0 {
+		return nil, SourceVersion{}, fmt.Errorf("no versions of %s are compatible with this pack", project.Name)
+	}
+
+	version, err := src.SelectVersion(project, versions, req)
+	if err != nil {
+		return nil, SourceVersion{}, err
+	}
+
+	mod, err := src.NewMod(project, version, req)
+	if err != nil {
+		return nil, SourceVersion{}, err
+	}
+	// Record the version label for Sources that don't, unless it is just the file name
+	if mod.Version == "" && version.Version != mod.FileName {
+		mod.Version = version.Version
+	}
+	return mod, version, nil
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource is a minimal in-memory Source: projects are keyed by identifier, and every
+// project has the versions listed in versions, of which the last is selected.
+type fakeSource struct {
+	name     string
+	versions []string
+	deps     []*Mod
+}
+
+func (f fakeSource) GetName() string { return f.name }
+
+func (f fakeSource) Search(query string, _ SourceRequest) ([]SourceProject, error) {
+	return []SourceProject{{Source: f.name, ID: query, Name: query}}, nil
+}
+
+func (f fakeSource) ResolveProject(identifier string, _ SourceRequest) (SourceProject, error) {
+	if identifier == "missing" {
+		return SourceProject{}, errors.New("not found")
+	}
+	return SourceProject{Source: f.name, ID: identifier, Slug: identifier, Name: identifier}, nil
+}
+
+func (f fakeSource) ListVersions(SourceProject, SourceRequest) ([]SourceVersion, error) {
+	versions := make([]SourceVersion, len(f.versions))
+	for i, v := range f.versions {
+		versions[i] = SourceVersion{ID: v, Version: v, FileName: v + ".jar"}
+	}
+	return versions, nil
+}
+
+func (f fakeSource) SelectVersion(_ SourceProject, versions []SourceVersion, _ SourceRequest) (SourceVersion, error) {
+	return versions[len(versions)-1], nil
+}
+
+func (f fakeSource) NewMod(project SourceProject, version SourceVersion, req SourceRequest) (*Mod, error) {
+	return &Mod{Slug: project.Slug, Name: project.Name, FileName: version.FileName, ModType: req.MetaFolder}, nil
+}
+
+func (f fakeSource) FindMissingDependencies(SourceVersion, SourceRequest) ([]*Mod, error) {
+	return f.deps, nil
+}
+
+func TestRegistry_Sources(t *testing.T) {
+	reg := NewRegistry()
+	reg.AddSource(fakeSource{name: "zeta"})
+	reg.AddSource(fakeSource{name: "alpha"})
+
+	src, ok := reg.GetSource("zeta")
+	require.True(t, ok)
+	assert.Equal(t, "zeta", src.GetName())
+
+	_, ok = reg.GetSource("missing")
+	assert.False(t, ok)
+
+	names := make([]string, 0)
+	for _, s := range reg.Sources() {
+		names = append(names, s.GetName())
+	}
+	assert.Equal(t, []string{"alpha", "zeta"}, names)
+
+	_, ok = NewRegistry().GetSource("zeta")
+	assert.False(t, ok, "a fresh Registry must not see sources added to another")
+}
+
+func TestNewModFromSource(t *testing.T) {
+	req := SourceRequest{MetaFolder: "mods"}
+
+	t.Run("selects a version and builds the mod", func(t *testing.T) {
+		src := fakeSource{name: "fake", versions: []string{"1.0", "2.0"}}
+		mod, version, err := NewModFromSource(src, "example", req)
+		require.NoError(t, err)
+		assert.Equal(t, "2.0", version.Version)
+		assert.Equal(t, "example", mod.Slug)
+		assert.Equal(t, "2.0.jar", mod.FileName)
+		assert.Equal(t, "2.0", mod.Version, "the version label is recorded if the source doesn't")
+		assert.Equal(t, "mods", mod.ModType)
+	})
+
+	t.Run("resolve errors are returned", func(t *testing.T) {
+		src := fakeSource{name: "fake", versions: []string{"1.0"}}
+		_, _, err := NewModFromSource(src, "missing", req)
+		assert.Error(t, err)
+	})
+
+	t.Run("no compatible versions is an error", func(t *testing.T) {
+		src := fakeSource{name: "fake"}
+		_, _, err := NewModFromSource(src, "example", req)
+		assert.Error(t, err)
+	})
+}
+
+func TestSourceRequest_Option(t *testing.T) {
+	req := SourceRequest{Options: map[string]string{"regex": ".*"}}
+	assert.Equal(t, ".*", req.Option("regex"))
+	assert.Equal(t, "", req.Option("branch"))
+	assert.Equal(t, "", SourceRequest{}.Option("branch"))
+}
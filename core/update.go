@@ -93,6 +93,7 @@ func GetUpdatableMods(reg *Registry, pack Pack) (UpdateDataList, error) {
 			if check.Error != nil {
 				return nil, fmt.Errorf("failed to check for updates for mod: %s - %s\n", mod.Slug, check.Error.Error())
 			}
+			logHeldBack(reg, mod, check)
 
 			if check.UpdateAvailable {
 				if mod.Pin {
@@ -100,6 +101,7 @@ func GetUpdatableMods(reg *Registry, pack Pack) (UpdateDataList, error) {
 					continue
 				}
 
+				reg.logger.Infof("update available for mod: %s (%s)\n", mod.Slug, check.UpdateString)
 				updatable.Append(source, mod, check.CachedState)
 			}
 		}
@@ -125,11 +127,13 @@ func UpdateSingleMod(reg *Registry, pack Pack, mod *Mod) error {
 		return fmt.Errorf("invalid update check response for mod: %s", mod.Name)
 	}
 	check := checks[0]
+	logHeldBack(reg, mod, check)
 
 	if !check.UpdateAvailable {
 		reg.logger.Infof("mod: %s is already up to date\n", mod.Name)
 		return nil
 	} else {
+		reg.logger.Infof("updating mod: %s (%s)\n", mod.Slug, check.UpdateString)
 		updateData := make(UpdateDataList)
 		updateData.Append(updater.GetName(), mod, check.CachedState)
 
@@ -155,6 +159,20 @@ func UpdateAllMods(reg *Registry, pack Pack) error {
 	return updateMods(reg, updateData)
 }
 
+// logHeldBack reports newer versions of mod that weren't offered because of its version
+// constraint or the minimum release age, and warns if its constraint was ignored
+func logHeldBack(reg *Registry, mod *Mod, check UpdateCheck) {
+	if check.HeldBack != "" {
+		reg.logger.Infof("held back update for mod: %s to %s (version constraint %q)\n", mod.Slug, check.HeldBack, mod.VersionConstraint)
+	}
+	if check.ConstraintIgnored {
+		reg.logger.Warnf("version constraint %q of mod: %s can't be applied to its source; use pin to stop it from being updated\n", mod.VersionConstraint, mod.Slug)
+	}
+	if check.TooRecent != "" {
+		reg.logger.Infof("held back update for mod: %s to %s (released too recently)\n", mod.Slug, check.TooRecent)
+	}
+}
+
 func updateMods(reg *Registry, updateData UpdateDataList) error {
 	reg = resolveRegistry(reg)
 
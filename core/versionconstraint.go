@@ -0,0 +1,126 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// VersionSatisfies returns true if version meets every comma-separated clause in
+// constraint. A clause is a comparison (">=4.2", "<5", "=4.2.1", "!=4.2.0") using
+// CompareVersions (so "=4.2" matches 4.2.0), a bare version prefix ("4.2" matches 4.2,
+// 4.2.1 and 4.2-beta), a tilde range ("~4.2" matches 4.2 and later 4.2.x versions) or a
+// caret range ("^4.2" matches 4.2 and later 4.x versions). An empty constraint is always
+// satisfied.
+func VersionSatisfies(version string, constraint string) (bool, error) {
+	for _, clause := range strings.Split(constraint, ",") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+
+		op := ""
+		for _, prefix := range []string{">=", "<=", "!=", ">", "<", "=", "~", "^"} {
+			if strings.HasPrefix(clause, prefix) {
+				op = prefix
+				break
+			}
+		}
+		target := strings.TrimSpace(strings.TrimPrefix(clause, op))
+		if target == "" {
+			return false, fmt.Errorf("invalid version constraint %q", constraint)
+		}
+
+		cmp := CompareVersions(version, target)
+		var ok bool
+		switch op {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case "~", "^":
+			parts := strings.Split(target, ".")
+			keep := 1
+			if op == "~" && len(parts) > 1 {
+				keep = 2
+			}
+			ok = cmp >= 0 && versionHasPrefix(version, strings.Join(parts[:keep], "."))
+		default:
+			ok = versionHasPrefix(version, target)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// versionHasPrefix returns true if version is prefix, or starts with prefix followed by
+// a separator
+func versionHasPrefix(version string, prefix string) bool {
+	return version == prefix || strings.HasPrefix(version, prefix+".") ||
+		strings.HasPrefix(version, prefix+"-") || strings.HasPrefix(version, prefix+"+")
+}
+
+// ValidateVersionConstraint returns an error if constraint can't be parsed
+func ValidateVersionConstraint(constraint string) error {
+	_, err := VersionSatisfies("0", constraint)
+	return err
+}
+
+var versionLabelRegex = regexp.MustCompile(`(?i)(mc)?(\d+(?:\.\d+)+(?:-(?:alpha|beta|pre|rc)(?:\.?\d+)*)?)`)
+
+// ConstraintVersion returns the part of a provider's version label (a Modrinth version
+// number, CurseForge file name or release tag) that version constraints are compared
+// against: the first dotted version number in it that isn't one of mcVersions or
+// prefixed with "mc". Labels without one are returned unchanged.
+func ConstraintVersion(label string, mcVersions []string) string {
+	for _, match := range versionLabelRegex.FindAllStringSubmatch(label, -1) {
+		if match[1] != "" || slices.Contains(mcVersions, match[2]) {
+			continue
+		}
+		return match[2]
+	}
+	return label
+}
+
+// AcceptsVersion reports whether the mod's version constraint (if any) allows updating to
+// the version with the given label, see ConstraintVersion
+func (m *Mod) AcceptsVersion(label string, mcVersions []string) (bool, error) {
+	if m.VersionConstraint == "" {
+		return true, nil
+	}
+	ok, err := VersionSatisfies(ConstraintVersion(label, mcVersions), m.VersionConstraint)
+	if err != nil {
+		return false, fmt.Errorf("invalid version constraint for %s: %w", m.Name, err)
+	}
+	return ok, nil
+}
+
+// FilterAcceptedVersions returns the candidates whose version label (as returned by label)
+// is accepted by the mod's version constraint, keeping their order
+func FilterAcceptedVersions[T any](m *Mod, mcVersions []string, candidates []T, label func(T) string) ([]T, error) {
+	if m.VersionConstraint == "" {
+		return candidates, nil
+	}
+	var accepted []T
+	for _, c := range candidates {
+		ok, err := m.AcceptsVersion(label(c), mcVersions)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			accepted = append(accepted, c)
+		}
+	}
+	return accepted, nil
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionSatisfies(t *testing.T) {
+	cases := []struct {
+		version    string
+		constraint string
+		expected   bool
+	}{
+		{"4.2.1", "", true},
+		{"4.2.1", ">=4.2,<5", true},
+		{"5.0.0", ">=4.2,<5", false},
+		{"4.1.9", ">=4.2", false},
+		{"4.2.1", "4.2", true},
+		{"4.2-beta", "4.2", true},
+		{"4.20.0", "4.2", false},
+		{"4.2.1", "=4.2.1", true},
+		{"4.2.1", "!=4.2.1", false},
+		{"4.2.0", "=4.2", true},
+		{"4.2", "!=4.2.0", false},
+		{"4.10", ">4.9", true},
+		{"4.2.5", "~4.2", true},
+		{"4.3.0", "~4.2", false},
+		{"4.2.0", "~4.2.1", false},
+		{"4.2.3", "~4.2.1", true},
+		{"4.9.0", "^4.2", true},
+		{"4.1.0", "^4.2", false},
+		{"5.0.0", "^4.2", false},
+		{"4.7", "~4", true},
+	}
+	for _, tc := range cases {
+		ok, err := VersionSatisfies(tc.version, tc.constraint)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, ok, "%s %s", tc.version, tc.constraint)
+	}
+
+	_, err := VersionSatisfies("1.0", ">=")
+	assert.Error(t, err)
+	assert.Error(t, ValidateVersionConstraint("~"))
+	assert.NoError(t, ValidateVersionConstraint("~4.2"))
+}
+
+func TestConstraintVersion(t *testing.T) {
+	mcVersions := []string{"1.20.1", "1.20"}
+	cases := []struct {
+		label    string
+		expected string
+	}{
+		{"4.2.1", "4.2.1"},
+		{"mc1.20.1-0.5.3", "0.5.3"},
+		{"sodium-fabric-0.5.3+mc1.20.1.jar", "0.5.3"},
+		{"jei-1.20.1-forge-15.2.0.27.jar", "15.2.0.27"},
+		{"mod-2.0-beta.1.jar", "2.0-beta.1"},
+		{"release", "release"},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, ConstraintVersion(tc.label, mcVersions), tc.label)
+	}
+}
+
+func TestMod_AcceptsVersion(t *testing.T) {
+	mod := &Mod{Name: "Test"}
+	ok, err := mod.AcceptsVersion("5.0.0", nil)
+	require.NoError(t, err)
+	assert.True(t, ok, "mods without a constraint accept every version")
+
+	mod.VersionConstraint = "<5"
+	ok, err = mod.AcceptsVersion("mod-1.20.1-5.0.0.jar", []string{"1.20.1"})
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	accepted, err := FilterAcceptedVersions(mod, []string{"1.20.1"}, []string{"5.1", "4.9", "5.0", "4.8"}, func(s string) string { return s })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4.9", "4.8"}, accepted)
+
+	mod.VersionConstraint = ">="
+	_, err = mod.AcceptsVersion("1.0", nil)
+	assert.ErrorContains(t, err, "Test")
+}
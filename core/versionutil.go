@@ -54,6 +54,17 @@ var ModLoaders = map[string]ModLoaderComponent{
 			return GetLoaderCache().GetVersions(mcVersion, "neoforge")
 		},
 	},
+	// Plugin server platforms, versioned by build number (see serverjar.go)
+	"paper": {
+		Name:              "paper",
+		FriendlyName:      "Paper",
+		VersionListGetter: fetchPaperVersions,
+	},
+	"purpur": {
+		Name:              "purpur",
+		FriendlyName:      "Purpur",
+		VersionListGetter: fetchPurpurVersions,
+	},
 }
 
 func ComponentToFriendlyName(component string) string {
@@ -368,28 +379,48 @@ func neoforgeMavenVersionToKey(version string) (string, string) {
 }
 
 // ----
-type mavenXmlMetadata struct {
+
+// MavenMetadata is the parsed contents of a Maven repository's maven-metadata.xml file
+// for an artifact
+type MavenMetadata struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
 	Versioning struct {
+		Latest   string `xml:"latest"`
+		Release  string `xml:"release"`
 		Versions struct {
 			Version []string `xml:"version"`
 		} `xml:"versions"`
 	} `xml:"versioning"`
 }
 
-func fetchMavenList(url string, versionCb func(version string) string) ([]string, error) {
+// FetchMavenMetadata fetches and parses the maven-metadata.xml file at url
+func FetchMavenMetadata(url string) (MavenMetadata, error) {
 	resp, err := GetWithUA(url, "application/xml")
 	if err != nil {
-		return nil, err
+		return MavenMetadata{}, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != 200 {
+		return MavenMetadata{}, fmt.Errorf("failed to fetch %s: invalid status code %v", url, resp.StatusCode)
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return MavenMetadata{}, err
 	}
 
-	var metadata mavenXmlMetadata
+	var metadata MavenMetadata
 	if err := xml.Unmarshal(body, &metadata); err != nil {
+		return MavenMetadata{}, fmt.Errorf("failed to parse %s: %w", url, err)
+	}
+	return metadata, nil
+}
+
+func fetchMavenList(url string, versionCb func(version string) string) ([]string, error) {
+	metadata, err := FetchMavenMetadata(url)
+	if err != nil {
 		return nil, err
 	}
 
@@ -410,22 +441,11 @@ func fetchMavenList(url string, versionCb func(version string) string) ([]string
 }
 
 func fetchMavenMap(url string, keyValueCb func(version string) (string, string)) (VersionMap, error) {
-	resp, err := GetWithUA(url, "application/xml")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	metadata, err := FetchMavenMetadata(url)
 	if err != nil {
 		return nil, err
 	}
 
-	var metadata mavenXmlMetadata
-	if err := xml.Unmarshal(body, &metadata); err != nil {
-		return nil, err
-	}
-
 	versionMap := make(VersionMap)
 
 	for _, version := range metadata.Versioning.Versions.Version {
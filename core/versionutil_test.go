@@ -1,9 +1,12 @@
 package core
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNeoforgeMavenVersionToKey(t *testing.T) {
@@ -71,3 +74,36 @@ func TestNeoforgeMavenVersionToKey_Merge(t *testing.T) {
 	}, buckets["26.1-snapshot-6"])
 	assert.ElementsMatch(t, []string{"26.1.0.0-alpha.11+snapshot-7"}, buckets["26.1-snapshot-7"])
 }
+
+func TestFetchMavenMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/shedaniel/cloth/cloth-config/maven-metadata.xml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>me.shedaniel.cloth</groupId>
+  <artifactId>cloth-config</artifactId>
+  <versioning>
+    <latest>11.1.106</latest>
+    <release>11.1.106</release>
+    <versions>
+      <version>11.0.99</version>
+      <version>11.1.106</version>
+    </versions>
+  </versioning>
+</metadata>`))
+	}))
+	t.Cleanup(server.Close)
+
+	metadata, err := FetchMavenMetadata(server.URL + "/me/shedaniel/cloth/cloth-config/maven-metadata.xml")
+	require.NoError(t, err)
+	assert.Equal(t, "me.shedaniel.cloth", metadata.GroupID)
+	assert.Equal(t, "cloth-config", metadata.ArtifactID)
+	assert.Equal(t, "11.1.106", metadata.Versioning.Release)
+	assert.Equal(t, []string{"11.0.99", "11.1.106"}, metadata.Versioning.Versions.Version)
+
+	_, err = FetchMavenMetadata(server.URL + "/missing/maven-metadata.xml")
+	assert.Error(t, err)
+}
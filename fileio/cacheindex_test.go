@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -144,3 +145,29 @@ func TestCacheIndexHandle_GetRemainingHashes(t *testing.T) {
 	remaining := handle.GetRemainingHashes([]string{"sha256", "sha1"})
 	assert.Equal(t, []string{"sha1"}, remaining)
 }
+
+func TestStoreInCache(t *testing.T) {
+	withTestCache(t)
+
+	hash, err := StoreInCache(strings.NewReader("asset contents"))
+	require.NoError(t, err)
+	assert.Equal(t, sha256Hex("asset contents"), hash)
+
+	// Storing the same contents again reuses the existing entry
+	hash, err = StoreInCache(strings.NewReader("asset contents"))
+	require.NoError(t, err)
+	assert.Equal(t, sha256Hex("asset contents"), hash)
+
+	index, err := OpenCacheIndex()
+	require.NoError(t, err)
+	assert.Len(t, index.Hashes[cacheHashFormat], 1)
+	handle := index.GetHandleFromHash(cacheHashFormat, hash)
+	require.NotNil(t, handle)
+	data, err := os.ReadFile(handle.Path())
+	require.NoError(t, err)
+	assert.Equal(t, "asset contents", string(data))
+
+	temps, err := os.ReadDir(filepath.Join(index.cachePath, "temp"))
+	require.NoError(t, err)
+	assert.Empty(t, temps)
+}
@@ -778,6 +778,55 @@ func (c *CacheIndex) Save() error {
 	return nil
 }
 
+// StoreInCache streams data into the local download cache and returns its
+// cacheHashFormat (sha256) hash. Providers that have to download a file just to hash it
+// use this so that a later DownloadSession (e.g. an export) reuses the file instead of
+// downloading it again.
+func StoreInCache(data io.Reader) (string, error) {
+	cacheIndex, err := loadCacheIndex()
+	if err != nil {
+		return "", err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Join(cacheIndex.cachePath, "temp"), "download-tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file for download: %w", err)
+	}
+	// Removed unless ownership is transferred to the cache by CreateFromTemp
+	stored := false
+	defer func() {
+		if !stored {
+			_ = tempFile.Close()
+			_ = os.Remove(tempFile.Name())
+		}
+	}()
+
+	hasher, err := core.GetHashImpl(cacheHashFormat)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(io.MultiWriter(tempFile, hasher), data); err != nil {
+		return "", fmt.Errorf("failed to download: %w", err)
+	}
+	hash := hasher.String()
+
+	handle, alreadyExists := cacheIndex.NewHandleFromHashes(map[string]string{cacheHashFormat: hash})
+	if !alreadyExists {
+		file, err := handle.CreateFromTemp(tempFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to move file %s to cache: %w", handle.Path(), err)
+		}
+		stored = true
+		_ = file.Close()
+		_ = handle.UpdateIndex()
+	}
+
+	if err := cacheIndex.Save(); err != nil {
+		return "", fmt.Errorf("error writing cache index: %w", err)
+	}
+	return hash, nil
+}
+
 // CreateDownloadSession builds a DownloadSession for the given mods, bootstrapping the
 // local cache and planning download tasks/manual downloads for each mod that isn't
 // already cached with one of hashesToObtain. reg resolves each mod's MetaDownloader;
@@ -1,8 +1,10 @@
 package fileio
 
 import (
+	"errors"
+	"fmt"
 	gitignore "github.com/sabhiram/go-gitignore"
-	"os"
+	"io/fs"
 	"strings"
 )
 
@@ -28,16 +30,51 @@ var ignoreDefaults = []string{
 	"packwiz", // Note: also excludes packwiz/ as a directory - you can negate this pattern if you want a directory called packwiz
 }
 
-func readGitignore(path string) (*gitignore.GitIgnore, bool) {
-	data, err := os.ReadFile(path)
+// readGitignore compiles the ignoreDefaults followed by the rules in the ignore file at path in
+// fsys, returning whether the file exists. A missing file isn't an error: only the defaults apply.
+func readGitignore(fsys fs.FS, path string) (*gitignore.GitIgnore, bool, error) {
+	data, err := fs.ReadFile(fsys, path)
 	if err != nil {
-		// TODO: check for read errors (and present them)
-		return gitignore.CompileIgnoreLines(ignoreDefaults...), false
+		if errors.Is(err, fs.ErrNotExist) {
+			return gitignore.CompileIgnoreLines(ignoreDefaults...), false, nil
+		}
+		return nil, false, fmt.Errorf("failed to read %s: %w", path, err)
 	}
 
 	s := strings.Split(string(data), "\n")
 	var lines []string
 	lines = append(lines, ignoreDefaults...)
 	lines = append(lines, s...)
-	return gitignore.CompileIgnoreLines(lines...), true
+	return gitignore.CompileIgnoreLines(lines...), true, nil
+}
+
+// IgnoreRule is a rule that excludes files from the index
+type IgnoreRule struct {
+	// Pattern is the rule as written
+	Pattern string
+	// Default is true for the built-in rules that apply to every pack, which .packwizignore can
+	// override with negated (!) patterns, and false for rules in .packwizignore
+	Default bool
+	// Line is the 1-based line number of the rule in .packwizignore, or in the built-in rules
+	Line int
+}
+
+func (r IgnoreRule) String() string {
+	if r.Default {
+		return fmt.Sprintf("built-in rule %d: %s", r.Line, r.Pattern)
+	}
+	return fmt.Sprintf(".packwizignore line %d: %s", r.Line, r.Pattern)
+}
+
+// matchIgnoreRule returns the rule of ignore (as compiled by readGitignore) that excludes path,
+// or nil if none does
+func matchIgnoreRule(ignore *gitignore.GitIgnore, path string) *IgnoreRule {
+	matches, pattern := ignore.MatchesPathHow(path)
+	if !matches || pattern == nil {
+		return nil
+	}
+	if pattern.LineNo <= len(ignoreDefaults) {
+		return &IgnoreRule{Pattern: pattern.Line, Default: true, Line: pattern.LineNo}
+	}
+	return &IgnoreRule{Pattern: pattern.Line, Line: pattern.LineNo - len(ignoreDefaults)}
 }
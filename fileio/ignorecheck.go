@@ -0,0 +1,84 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// IgnoreCheck says whether a path in the pack folder is in the index, and why refreshing the
+// index leaves it out if it does
+type IgnoreCheck struct {
+	// Path is the checked path, as given
+	Path string
+	// IndexPath is the path relative to the pack root, as it appears in the index
+	IndexPath string
+	// Exists is true if there is a file or folder at the path
+	Exists bool
+	// IsDir is true if the path is a folder
+	IsDir bool
+	// Indexed is true if the file is in index.toml, or for a folder, any file in it is
+	Indexed bool
+	// Excluded is true if refreshing the index leaves the path out
+	Excluded bool
+	// Reason explains why the path is excluded when no ignore rule is responsible, e.g. because
+	// it is pack.toml
+	Reason string
+	// Rule is the ignore rule that excludes the path, or the folder it is in
+	Rule *IgnoreRule
+	// MatchedPath is the index path Reason or Rule applies to: IndexPath itself, or the folder
+	// containing it
+	MatchedPath string
+}
+
+// CheckIgnored reports, for each of paths, whether it is in the pack's index and whether
+// refreshing the index would leave it out, with the ignore rule (from ignoreDefaults or
+// .packwizignore) responsible. Paths that don't exist are checked as files.
+func CheckIgnored(index *core.IndexFS, packFilePath string, paths []string) ([]IgnoreCheck, error) {
+	filter, err := newIndexFilter(hostStorage, index, packFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := make([]IgnoreCheck, 0, len(paths))
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		relPath, err := filter.indexPath(absPath)
+		if err != nil {
+			return nil, err
+		}
+		check := IgnoreCheck{Path: path, IndexPath: relPath}
+		if stat, err := os.Stat(absPath); err == nil {
+			check.Exists = true
+			check.IsDir = stat.IsDir()
+		}
+
+		excl, err := filter.exclusionOf(absPath, check.IsDir)
+		if err != nil {
+			return nil, err
+		}
+		if excl != nil {
+			check.Excluded = true
+			check.Reason = excl.reason
+			check.Rule = excl.rule
+			check.MatchedPath, err = filter.indexPath(excl.path)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for p := range index.Files {
+			if p == relPath || (check.IsDir && (relPath == "." || strings.HasPrefix(p, relPath+"/"))) {
+				check.Indexed = true
+				break
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
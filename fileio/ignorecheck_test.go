@@ -0,0 +1,88 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckIgnored(t *testing.T) {
+	dir := t.TempDir()
+	index := refreshTestIndex(t, dir)
+	packPath := filepath.Join(dir, "pack.toml")
+	require.NoError(t, os.WriteFile(packPath, []byte("name = \"test\"\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "config", "sub"), 0755))
+	for _, file := range []string{"config/a.cfg", "config/sub/b.cfg", "export.zip", "debug.log"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(file), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".packwizignore"), []byte("# comment\nconfig/sub/\n*.log\n"), 0644))
+	_, err := RefreshIndexFiles(&index, packPath, nil)
+	require.NoError(t, err)
+
+	paths := []string{"config/a.cfg", "config/sub/b.cfg", "config/sub", "config", "export.zip", "debug.log", "pack.toml", "missing.cfg"}
+	for i, path := range paths {
+		paths[i] = filepath.Join(dir, path)
+	}
+	checks, err := CheckIgnored(&index, packPath, paths)
+	require.NoError(t, err)
+	require.Len(t, checks, len(paths))
+
+	check := checks[0]
+	assert.Equal(t, "config/a.cfg", check.IndexPath)
+	assert.True(t, check.Exists)
+	assert.True(t, check.Indexed)
+	assert.False(t, check.Excluded)
+
+	// Files in an ignored folder report the folder's rule
+	check = checks[1]
+	assert.True(t, check.Excluded)
+	assert.False(t, check.Indexed)
+	require.NotNil(t, check.Rule)
+	assert.Equal(t, IgnoreRule{Pattern: "config/sub/", Line: 2}, *check.Rule)
+	assert.Equal(t, "config/sub", check.MatchedPath)
+	assert.Equal(t, ".packwizignore line 2: config/sub/", check.Rule.String())
+
+	check = checks[2]
+	assert.True(t, check.IsDir)
+	assert.True(t, check.Excluded)
+	assert.Equal(t, "config/sub", check.MatchedPath)
+
+	// A folder is indexed if any file in it is
+	check = checks[3]
+	assert.True(t, check.IsDir)
+	assert.True(t, check.Indexed)
+	assert.False(t, check.Excluded)
+
+	check = checks[4]
+	assert.True(t, check.Excluded)
+	require.NotNil(t, check.Rule)
+	assert.True(t, check.Rule.Default)
+	assert.Equal(t, "/*.zip", check.Rule.Pattern)
+	assert.Equal(t, "/*.zip", ignoreDefaults[check.Rule.Line-1])
+
+	check = checks[5]
+	require.NotNil(t, check.Rule)
+	assert.Equal(t, IgnoreRule{Pattern: "*.log", Line: 3}, *check.Rule)
+
+	check = checks[6]
+	assert.True(t, check.Excluded)
+	assert.Nil(t, check.Rule)
+	assert.Equal(t, "the pack file", check.Reason)
+
+	check = checks[7]
+	assert.False(t, check.Exists)
+	assert.False(t, check.Excluded)
+}
+
+func TestRefreshIndexFiles_UnreadableIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	index := refreshTestIndex(t, dir)
+	// A folder can't be read as a file
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".packwizignore"), 0755))
+
+	_, err := RefreshIndexFiles(&index, filepath.Join(dir, "pack.toml"), nil)
+	assert.ErrorContains(t, err, ".packwizignore")
+}
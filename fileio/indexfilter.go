@@ -0,0 +1,209 @@
+package fileio
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	gitignore "github.com/sabhiram/go-gitignore"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// indexFilter decides which files in the pack folder belong in the index: everything except the
+// pack and index files themselves, .packwizignore, the LocalCacheFolder and whatever
+// .packwizignore (or ignoreDefaults) excludes
+type indexFilter struct {
+	fsys fs.FS
+	// ignoreFile is the name of .packwizignore in fsys
+	ignoreFile   string
+	packRoot     string
+	pathPF       string
+	pathIndex    string
+	pathIgnore   string
+	pathCache    string
+	ignore       *gitignore.GitIgnore
+	ignoreExists bool
+}
+
+// exclusion explains why a file is left out of the index
+type exclusion struct {
+	// reason is set when the file is excluded other than by an ignore rule
+	reason string
+	// rule is the ignore rule that excludes the file, or one of the folders it is in
+	rule *IgnoreRule
+	// path is the file, or the folder it is in, that is excluded
+	path string
+}
+
+func newIndexFilter(fsys fs.FS, index *core.IndexFS, packFilePath string) (*indexFilter, error) {
+	// Is case-sensitivity a problem?
+	pathPF, err := filepath.Abs(packFilePath)
+	if err != nil {
+		return nil, err
+	}
+	pathIndex, err := filepath.Abs(index.GetFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	packRoot, err := filepath.Abs(index.GetPackRoot())
+	if err != nil {
+		return nil, err
+	}
+	pathIgnore, err := filepath.Abs(filepath.Join(packRoot, ".packwizignore"))
+	if err != nil {
+		return nil, err
+	}
+	pathCache, err := filepath.Abs(filepath.Join(packRoot, LocalCacheFolder))
+	if err != nil {
+		return nil, err
+	}
+
+	f := &indexFilter{
+		fsys:       fsys,
+		ignoreFile: filepath.Join(index.GetPackRoot(), ".packwizignore"),
+		packRoot:   packRoot,
+		pathPF:     pathPF,
+		pathIndex:  pathIndex,
+		pathIgnore: pathIgnore,
+		pathCache:  pathCache,
+	}
+	if err := f.reloadIgnore(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// reloadIgnore reads .packwizignore again, e.g. after it has changed. If it can't be read, the
+// previous rules are kept.
+func (f *indexFilter) reloadIgnore() error {
+	ignore, ignoreExists, err := readGitignore(f.fsys, f.ignoreFile)
+	if err != nil {
+		return err
+	}
+	f.ignore, f.ignoreExists = ignore, ignoreExists
+	return nil
+}
+
+// isIgnoreFile returns true if path is the pack's .packwizignore
+func (f *indexFilter) isIgnoreFile(path string) bool {
+	absPath, err := filepath.Abs(path)
+	return err == nil && absPath == f.pathIgnore
+}
+
+// dirExclusion returns why the folder at path (below the pack root) and everything in it is left
+// out of the index, or nil if it isn't
+func (f *indexFilter) dirExclusion(path string) (*exclusion, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if absPath == f.pathCache {
+		return &exclusion{reason: "packwiz's local cache folder", path: path}, nil
+	}
+	// Don't traverse ignored directories (consistent with Git handling of ignored dirs)
+	rule, err := f.matchIgnoreRule(path, true)
+	if err != nil || rule == nil {
+		return nil, err
+	}
+	return &exclusion{rule: rule, path: path}, nil
+}
+
+// fileExclusion returns why the file at path is left out of the index, not taking the folders it
+// is in into account (see exclusionOf), or nil if it isn't
+func (f *indexFilter) fileExclusion(path string) (*exclusion, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	// Exit if the files are the same as the pack/index files
+	if absPath == f.pathPF {
+		return &exclusion{reason: "the pack file", path: path}, nil
+	}
+	if absPath == f.pathIndex {
+		return &exclusion{reason: "the index file", path: path}, nil
+	}
+	if f.ignoreExists {
+		if absPath == f.pathIgnore {
+			return &exclusion{reason: "the ignore file", path: path}, nil
+		}
+	}
+	rule, err := f.matchIgnoreRule(path, false)
+	if err != nil || rule == nil {
+		return nil, err
+	}
+	return &exclusion{rule: rule, path: path}, nil
+}
+
+// matchIgnoreRule returns the ignore rule that excludes the file or folder at path, matched (like
+// Git does) relative to the pack root, or nil if none does
+func (f *indexFilter) matchIgnoreRule(path string, isDir bool) (*IgnoreRule, error) {
+	rel, err := f.indexPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if isDir {
+		// Patterns ending in / only match folders
+		rel += "/"
+	}
+	return matchIgnoreRule(f.ignore, rel), nil
+}
+
+// indexPath returns path relative to the pack root, as it would appear in the index
+func (f *indexFilter) indexPath(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(f.packRoot, absPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// exclusionOf returns why the file (or folder, if isDir) at path is left out of the index, either
+// itself or because one of the folders it is in is, or nil if it isn't
+func (f *indexFilter) exclusionOf(path string, isDir bool) (*exclusion, error) {
+	rel, err := f.indexPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if rel == "." {
+		// Never ignore pack root itself (gitignore doesn't allow ignoring the root)
+		return nil, nil
+	}
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return &exclusion{reason: "outside the pack folder", path: path}, nil
+	}
+	dir := f.packRoot
+	parts := strings.Split(rel, "/")
+	for _, part := range parts[:len(parts)-1] {
+		dir = filepath.Join(dir, part)
+		excl, err := f.dirExclusion(dir)
+		if err != nil || excl != nil {
+			return excl, err
+		}
+	}
+	if isDir {
+		return f.dirExclusion(path)
+	}
+	return f.fileExclusion(path)
+}
+
+func (f *indexFilter) excludesDir(path string) (bool, error) {
+	excl, err := f.dirExclusion(path)
+	return excl != nil, err
+}
+
+func (f *indexFilter) excludesFile(path string) (bool, error) {
+	excl, err := f.fileExclusion(path)
+	return excl != nil, err
+}
+
+// excludes returns true if the file at path is left out of the index, see exclusionOf
+func (f *indexFilter) excludes(path string) (bool, error) {
+	excl, err := f.exclusionOf(path, false)
+	return excl != nil, err
+}
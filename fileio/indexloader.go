@@ -6,7 +6,6 @@ import (
 	"github.com/pelletier/go-toml/v2"
 	"io"
 	"io/fs"
-	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -15,9 +14,14 @@ import (
 
 // LoadIndex attempts to load the index file from a path
 func LoadIndex(indexFile string) (core.IndexFS, error) {
+	return LoadIndexFrom(hostStorage, indexFile)
+}
+
+// LoadIndexFrom attempts to load the index file at indexFile in fsys
+func LoadIndexFrom(fsys fs.FS, indexFile string) (core.IndexFS, error) {
 	// Decode as indexTomlRepresentation then convert to IndexFS
 	var rep core.IndexTomlRepresentation
-	raw, err := os.ReadFile(indexFile)
+	raw, err := fs.ReadFile(fsys, indexFile)
 	if err != nil {
 		return core.IndexFS{}, err
 	}
@@ -37,16 +41,29 @@ func LoadIndex(indexFile string) (core.IndexFS, error) {
 }
 
 func LoadAllMods(index *core.IndexFS) ([]*core.ModToml, error) {
+	return LoadAllModsFrom(hostStorage, index)
+}
+
+// LoadAllModsFrom loads every metadata file in the index from fsys
+func LoadAllModsFrom(fsys fs.FS, index *core.IndexFS) ([]*core.ModToml, error) {
 	modPaths, err := index.GetAllMods()
 	if err != nil {
 		return nil, err
 	}
 	mods := make([]*core.ModToml, len(modPaths))
 	for i, v := range modPaths {
-		modData, err := LoadMod(v)
+		modData, err := LoadModFrom(fsys, v)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read metadata file %s: %w", v, err)
 		}
+		// Keep the whole folder path relative to the pack root, for metadata files in nested
+		// folders such as config/paxi/datapacks
+		if rel, err := filepath.Rel(index.GetPackRoot(), filepath.Dir(v)); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			modData.SetMetaFolder(filepath.ToSlash(rel))
+		}
+		if relPath, err := index.RelIndexPath(v); err == nil {
+			modData.SetIndexEntries(index.GetEntries(relPath))
+		}
 		mods[i] = &modData
 	}
 	return mods, nil
@@ -57,29 +74,97 @@ func LoadAllMods(index *core.IndexFS) ([]*core.ModToml, error) {
 // file walk. progressFn, if non-nil, is called after each file is processed with the current file
 // count, total file count, and the path just processed, allowing the caller to drive its own
 // progress reporting (e.g. a terminal progress bar).
-func RefreshIndexFiles(index *core.IndexFS, packFilePath string, progressFn func(current, total int, path string)) error {
-	// Is case-sensitivity a problem?
-	pathPF, err := filepath.Abs(packFilePath)
+//
+// Files whose size and modification time are unchanged since the last refresh reuse the hash
+// recorded in the pack's stat cache (see LocalCacheFolder) instead of being hashed again. Files
+// and folders that can't be read don't stop the refresh: they keep their previous index entries,
+// and are returned as skipped errors for the caller to report.
+func RefreshIndexFiles(index *core.IndexFS, packFilePath string, progressFn func(current, total int, path string)) (skipped []error, err error) {
+	return RefreshIndexFilesIn(hostStorage, index, packFilePath, progressFn)
+}
+
+// RefreshIndexFilesIn is RefreshIndexFiles for a pack in st, where the stat cache is saved too
+func RefreshIndexFilesIn(st Storage, index *core.IndexFS, packFilePath string, progressFn func(current, total int, path string)) (skipped []error, err error) {
+	return refreshIndexFiles(st, index, packFilePath, progressFn, st)
+}
+
+// IndexCheck is the result of CheckIndexFiles
+type IndexCheck struct {
+	// Files lists the files refreshing the index would add, remove or rehash
+	Files core.IndexDiff
+	// IndexHashStale is true if the index hash in pack.toml doesn't match the refreshed index
+	IndexHashStale bool
+}
+
+// UpToDate returns true if refreshing the index wouldn't change index.toml or pack.toml
+func (c IndexCheck) UpToDate() bool {
+	return c.Files.IsEmpty() && !c.IndexHashStale
+}
+
+// CheckIndexFiles refreshes a copy of the pack's index in memory, as RefreshIndexFiles would, and
+// compares it with index.toml and the index hash recorded in pack.toml. Nothing is written, not
+// even the stat cache. Files that can't be read are returned as skipped errors, as they are by
+// RefreshIndexFiles.
+func CheckIndexFiles(pack *core.PackToml, packFilePath string) (check IndexCheck, skipped []error, err error) {
+	return CheckIndexFilesIn(hostStorage, pack, packFilePath)
+}
+
+// CheckIndexFilesIn is CheckIndexFiles for a pack in fsys
+func CheckIndexFilesIn(fsys fs.FS, pack *core.PackToml, packFilePath string) (check IndexCheck, skipped []error, err error) {
+	current, err := LoadPackIndexFileFrom(fsys, pack)
 	if err != nil {
-		return err
+		return IndexCheck{}, nil, err
 	}
-	pathIndex, err := filepath.Abs(index.GetFilePath())
+	refreshed, err := LoadPackIndexFileFrom(fsys, pack)
 	if err != nil {
-		return err
+		return IndexCheck{}, nil, err
 	}
 
-	packRoot := index.GetPackRoot()
-	pathIgnore, err := filepath.Abs(filepath.Join(packRoot, ".packwizignore"))
+	skipped, err = refreshIndexFiles(fsys, &refreshed, packFilePath, nil, nil)
 	if err != nil {
-		return err
+		return IndexCheck{}, nil, err
 	}
-	ignore, ignoreExists := readGitignore(pathIgnore)
 
+	repr, err := refreshed.ToWritable()
+	if err != nil {
+		return IndexCheck{}, nil, err
+	}
+	result, err := repr.Marshal()
+	if err != nil {
+		return IndexCheck{}, nil, err
+	}
+
+	return IndexCheck{
+		Files:          current.DiffFiles(refreshed),
+		IndexHashStale: !strings.EqualFold(pack.Index.HashFormat, result.HashFormat) || pack.Index.Hash != result.Hash,
+	}, skipped, nil
+}
+
+// refreshIndexFiles refreshes the index of the pack in fsys, saving the stat cache to saveTo
+// unless it is nil
+func refreshIndexFiles(fsys fs.FS, index *core.IndexFS, packFilePath string, progressFn func(current, total int, path string), saveTo Storage) (skipped []error, err error) {
+	filter, err := newIndexFilter(fsys, index, packFilePath)
+	if err != nil {
+		return nil, err
+	}
+	packRoot := index.GetPackRoot()
+
+	var unreadable []string
 	var fileList []string
-	err = filepath.WalkDir(packRoot, func(path string, info os.DirEntry, err error) error {
+	fileInfo := make(map[string]fs.FileInfo)
+	err = fs.WalkDir(fsys, packRoot, func(path string, info fs.DirEntry, err error) error {
 		if err != nil {
-			// TODO: Handle errors on individual files properly
-			return err
+			if path == packRoot {
+				return err
+			}
+			// Keep the previous entries of files that can't be read, rather than failing the
+			// whole refresh or dropping them from the index
+			skipped = append(skipped, fmt.Errorf("failed to read %s: %w", path, err))
+			unreadable = append(unreadable, path)
+			if info != nil && info.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
 		}
 
 		// Never ignore pack root itself (gitignore doesn't allow ignoring the root)
@@ -88,69 +173,123 @@ func RefreshIndexFiles(index *core.IndexFS, packFilePath string, progressFn func
 		}
 
 		if info.IsDir() {
-			// Don't traverse ignored directories (consistent with Git handling of ignored dirs)
-			if ignore.MatchesPath(path) {
+			excluded, err := filter.excludesDir(path)
+			if err != nil {
+				return err
+			}
+			if excluded {
 				return fs.SkipDir
 			}
 			// Don't add directories to the file list
 			return nil
 		}
-		// Exit if the files are the same as the pack/index files
-		absPath, err := filepath.Abs(path)
+		excluded, err := filter.excludesFile(path)
 		if err != nil {
 			return err
 		}
-		if absPath == pathPF || absPath == pathIndex {
+		if excluded {
 			return nil
 		}
-		if ignoreExists {
-			if absPath == pathIgnore {
-				return nil
-			}
-		}
-		if ignore.MatchesPath(path) {
+
+		// Stat rather than using info, to follow symlinks to the file that is hashed
+		stat, err := fs.Stat(fsys, path)
+		if err != nil {
+			skipped = append(skipped, fmt.Errorf("failed to read %s: %w", path, err))
+			unreadable = append(unreadable, path)
 			return nil
 		}
-
 		fileList = append(fileList, path)
+		fileInfo[path] = stat
 		return nil
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := hashFilesInto(index, fileList, progressFn); err != nil {
-		return err
+	index.ClearFound()
+	prevCache := loadStatCache(fsys, packRoot)
+	nextCache := newStatCache()
+	total := len(fileList)
+	current := 0
+
+	var toHash []string
+	for _, path := range fileList {
+		relPath, err := index.RelIndexPath(path)
+		if err != nil {
+			return nil, err
+		}
+		hashString, ok := prevCache.lookup(relPath, fileInfo[path])
+		if !ok {
+			toHash = append(toHash, path)
+			continue
+		}
+		if err := index.UpdateFileHashGiven(path, core.DefaultHashFormat, hashString, isMetaFilePath(path)); err != nil {
+			return nil, err
+		}
+		nextCache.store(relPath, fileInfo[path], hashString)
+		current++
+		if progressFn != nil {
+			progressFn(current, total, path)
+		}
+	}
+
+	failed, err := hashFilesInto(fsys, index, toHash, func(path, hashString string) {
+		if relPath, err := index.RelIndexPath(path); err == nil {
+			nextCache.store(relPath, fileInfo[path], hashString)
+		}
+		current++
+		if progressFn != nil {
+			progressFn(current, total, path)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	for path, hashErr := range failed {
+		skipped = append(skipped, hashErr)
+		unreadable = append(unreadable, path)
+	}
+
+	for _, path := range unreadable {
+		if err := index.MarkFound(path); err != nil {
+			return nil, err
+		}
 	}
 
 	// Check all the files exist, remove them if they don't
 	for p, file := range index.Files {
 		found, err := file.MarkedFound()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if !found {
 			delete(index.Files, p)
 		}
 	}
 
-	return nil
+	if saveTo != nil {
+		if err := nextCache.save(saveTo, packRoot); err != nil {
+			skipped = append(skipped, err)
+		}
+	}
+
+	return skipped, nil
 }
 
 // UpdateIndexFile hashes the file at path and records the result in the index.
 func UpdateIndexFile(in *core.IndexFS, path string) error {
-	hashString, markAsMetaFile, err := hashFile(path)
+	hashString, markAsMetaFile, err := hashFile(hostStorage, path)
 	if err != nil {
 		return err
 	}
 	return in.UpdateFileHashGiven(path, core.DefaultHashFormat, hashString, markAsMetaFile)
 }
 
-// hashFile computes path's DefaultHashFormat hash and whether it should be marked as a
-// meta file, doing no index access - safe to call concurrently across multiple files,
-// unlike core.IndexFS.UpdateFileHashGiven, which mutates a plain unsynchronized map.
-func hashFile(path string) (hashString string, markAsMetaFile bool, err error) {
-	f, err := os.Open(path)
+// hashFile computes the DefaultHashFormat hash of the file at path in fsys and whether it should
+// be marked as a meta file, doing no index access - safe to call concurrently across multiple
+// files, unlike core.IndexFS.UpdateFileHashGiven, which mutates a plain unsynchronized map.
+func hashFile(fsys fs.FS, path string) (hashString string, markAsMetaFile bool, err error) {
+	f, err := fsys.Open(path)
 	if err != nil {
 		return "", false, err
 	}
@@ -171,22 +310,27 @@ func hashFile(path string) (hashString string, markAsMetaFile bool, err error) {
 		return "", false, err
 	}
 
-	// If the file has an extension of pw.toml, set markAsMetaFile to true
-	markAsMetaFile = strings.HasSuffix(filepath.Base(path), core.MetaExtension)
+	return h.String(), isMetaFilePath(path), nil
+}
 
-	return h.String(), markAsMetaFile, nil
+// isMetaFilePath returns true if the file at path should be marked as a meta file, i.e. it has
+// an extension of pw.toml
+func isMetaFilePath(path string) bool {
+	return strings.HasSuffix(filepath.Base(path), core.MetaExtension)
 }
 
 // hashFilesInto hashes each of paths using a bounded pool of runtime.NumCPU() worker
 // goroutines (hashing is I/O-bound and safe to parallelize), while index.UpdateFileHashGiven
 // - a plain unsynchronized map mutation - is only ever called from this function's own
-// goroutine as results arrive. progressFn, if non-nil, is called once per file, in
-// whatever order results complete in (no ordering guarantee vs. paths). The first error
-// from any worker stops further dispatch and is returned once in-flight work drains.
-func hashFilesInto(index *core.IndexFS, paths []string, progressFn func(current, total int, path string)) error {
+// goroutine as results arrive. hashed, if non-nil, is called from that same goroutine once
+// per file that was hashed, in whatever order results complete in (no ordering guarantee vs.
+// paths). Files that can't be hashed are left untouched in the index and returned in failed,
+// keyed by path; only an error updating the index stops further dispatch, and is returned
+// once in-flight work drains.
+func hashFilesInto(fsys fs.FS, index *core.IndexFS, paths []string, hashed func(path, hashString string)) (failed map[string]error, err error) {
 	total := len(paths)
 	if total == 0 {
-		return nil
+		return nil, nil
 	}
 
 	type result struct {
@@ -213,7 +357,7 @@ func hashFilesInto(index *core.IndexFS, paths []string, progressFn func(current,
 		go func() {
 			defer wg.Done()
 			for path := range pathCh {
-				hashString, markAsMetaFile, err := hashFile(path)
+				hashString, markAsMetaFile, err := hashFile(fsys, path)
 				resultCh <- result{path: path, hashString: hashString, markAsMetaFile: markAsMetaFile, err: err}
 			}
 		}()
@@ -233,31 +377,31 @@ func hashFilesInto(index *core.IndexFS, paths []string, progressFn func(current,
 		close(resultCh)
 	}()
 
-	var firstErr error
-	current := 0
 	for res := range resultCh {
-		if res.err != nil {
-			if firstErr == nil {
-				firstErr = fmt.Errorf("failed to hash %s: %w", res.path, res.err)
-				cancel()
-			}
-			continue
-		}
-		if firstErr != nil {
+		if err != nil {
 			// Already stopping - avoid doing more index work, but keep draining resultCh
 			// so in-flight workers (dispatched before cancel) don't block forever on send.
 			continue
 		}
-		if err := index.UpdateFileHashGiven(res.path, core.DefaultHashFormat, res.hashString, res.markAsMetaFile); err != nil {
-			firstErr = err
+		if res.err != nil {
+			if failed == nil {
+				failed = make(map[string]error)
+			}
+			failed[res.path] = fmt.Errorf("failed to hash %s: %w", res.path, res.err)
+			continue
+		}
+		if updateErr := index.UpdateFileHashGiven(res.path, core.DefaultHashFormat, res.hashString, res.markAsMetaFile); updateErr != nil {
+			err = updateErr
 			cancel()
 			continue
 		}
-		current++
-		if progressFn != nil {
-			progressFn(current, total, res.path)
+		if hashed != nil {
+			hashed(res.path, res.hashString)
 		}
 	}
 
-	return firstErr
+	if err != nil {
+		return nil, err
+	}
+	return failed, nil
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/leocov-dev/packwiz-nxt/core"
 	"github.com/stretchr/testify/assert"
@@ -36,10 +37,11 @@ func TestRefreshIndexFiles_ParallelHashingCorrect(t *testing.T) {
 	require.NoError(t, err)
 
 	var progressCalls int
-	err = RefreshIndexFiles(&index, filepath.Join(dir, "pack.toml"), func(current, total int, path string) {
+	skipped, err := RefreshIndexFiles(&index, filepath.Join(dir, "pack.toml"), func(current, total int, path string) {
 		progressCalls++
 	})
 	require.NoError(t, err)
+	assert.Empty(t, skipped)
 
 	assert.Equal(t, len(contents), progressCalls)
 	assert.Len(t, index.Files, len(contents))
@@ -55,6 +57,153 @@ func TestRefreshIndexFiles_ParallelHashingCorrect(t *testing.T) {
 	}
 }
 
+// refreshTestIndex writes an empty index.toml into dir and loads it
+func refreshTestIndex(t *testing.T, dir string) core.IndexFS {
+	t.Helper()
+	indexPath := filepath.Join(dir, "index.toml")
+	require.NoError(t, os.WriteFile(indexPath, []byte("hash-format = \"sha256\"\n"), 0644))
+	index, err := LoadIndex(indexPath)
+	require.NoError(t, err)
+	return index
+}
+
+func TestRefreshIndexFiles_StatCache(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config", "a.cfg")
+	require.NoError(t, os.MkdirAll(filepath.Dir(cfgPath), 0755))
+	require.NoError(t, os.WriteFile(cfgPath, []byte("a=1"), 0644))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(cfgPath, old, old))
+
+	index := refreshTestIndex(t, dir)
+	skipped, err := RefreshIndexFiles(&index, filepath.Join(dir, "pack.toml"), nil)
+	require.NoError(t, err)
+	assert.Empty(t, skipped)
+	assert.FileExists(t, filepath.Join(dir, LocalCacheFolder, ".gitignore"))
+	assert.NotContains(t, index.Files, LocalCacheFolder+"/refresh.json")
+
+	// Unchanged files reuse their cached hash rather than being hashed again
+	cache := loadStatCache(hostStorage, dir)
+	entry := cache.Files["config/a.cfg"]
+	entry.Hash = "cached"
+	cache.Files["config/a.cfg"] = entry
+	require.NoError(t, cache.save(hostStorage, dir))
+
+	_, err = RefreshIndexFiles(&index, filepath.Join(dir, "pack.toml"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", index.Files["config/a.cfg"].(*core.IndexFile).Hash)
+
+	// Changed files are hashed again
+	require.NoError(t, os.WriteFile(cfgPath, []byte("a=22"), 0644))
+	require.NoError(t, os.Chtimes(cfgPath, old, old))
+	_, err = RefreshIndexFiles(&index, filepath.Join(dir, "pack.toml"), nil)
+	require.NoError(t, err)
+	sum := sha256.Sum256([]byte("a=22"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), index.Files["config/a.cfg"].(*core.IndexFile).Hash)
+}
+
+func TestRefreshIndexFiles_RemovesDeletedFiles(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config", "a.cfg")
+	require.NoError(t, os.MkdirAll(filepath.Dir(cfgPath), 0755))
+	require.NoError(t, os.WriteFile(cfgPath, []byte("a=1"), 0644))
+
+	index := refreshTestIndex(t, dir)
+	_, err := RefreshIndexFiles(&index, filepath.Join(dir, "pack.toml"), nil)
+	require.NoError(t, err)
+	assert.Contains(t, index.Files, "config/a.cfg")
+
+	// Refreshing the same index again drops files that have since been deleted
+	require.NoError(t, os.Remove(cfgPath))
+	_, err = RefreshIndexFiles(&index, filepath.Join(dir, "pack.toml"), nil)
+	require.NoError(t, err)
+	assert.NotContains(t, index.Files, "config/a.cfg")
+}
+
+func TestRefreshIndexFiles_UnreadableFilesKeepEntries(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "config"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "a.cfg"), []byte("a=1"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "b.cfg"), []byte("b=1"), 0644))
+
+	index := refreshTestIndex(t, dir)
+	_, err := RefreshIndexFiles(&index, filepath.Join(dir, "pack.toml"), nil)
+	require.NoError(t, err)
+	previousHash := index.Files["config/a.cfg"].(*core.IndexFile).Hash
+
+	// A dangling symlink can't be read, but doesn't stop the rest of the refresh
+	require.NoError(t, os.Remove(filepath.Join(dir, "config", "a.cfg")))
+	require.NoError(t, os.Symlink(filepath.Join(dir, "missing.cfg"), filepath.Join(dir, "config", "a.cfg")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "c.cfg"), []byte("c=1"), 0644))
+
+	var progressCalls int
+	skipped, err := RefreshIndexFiles(&index, filepath.Join(dir, "pack.toml"), func(current, total int, path string) {
+		progressCalls++
+	})
+	require.NoError(t, err)
+	require.Len(t, skipped, 1)
+	assert.Contains(t, skipped[0].Error(), "a.cfg")
+	assert.Equal(t, 2, progressCalls)
+
+	require.Contains(t, index.Files, "config/a.cfg")
+	assert.Equal(t, previousHash, index.Files["config/a.cfg"].(*core.IndexFile).Hash)
+	assert.Contains(t, index.Files, "config/b.cfg")
+	assert.Contains(t, index.Files, "config/c.cfg")
+}
+
+func TestCheckIndexFiles(t *testing.T) {
+	resetViper(t)
+	dir := t.TempDir()
+	require.NoError(t, WriteAll(testPack(t), dir))
+	packPath := filepath.Join(dir, "pack.toml")
+
+	refresh := func() {
+		t.Helper()
+		packToml, err := LoadPackFile(packPath)
+		require.NoError(t, err)
+		index, err := LoadPackIndexFile(&packToml)
+		require.NoError(t, err)
+		_, err = RefreshIndexFiles(&index, packPath, nil)
+		require.NoError(t, err)
+		require.NoError(t, WriteIndex(&index))
+		packToml.RefreshIndexHash(index)
+		require.NoError(t, NewPackWriter().Write(&packToml))
+	}
+	check := func() IndexCheck {
+		t.Helper()
+		packToml, err := LoadPackFile(packPath)
+		require.NoError(t, err)
+		result, skipped, err := CheckIndexFiles(&packToml, packPath)
+		require.NoError(t, err)
+		assert.Empty(t, skipped)
+		return result
+	}
+
+	refresh()
+	assert.True(t, check().UpToDate())
+
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, LocalCacheFolder)))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "config"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "a.cfg"), []byte("a=1"), 0644))
+	result := check()
+	assert.False(t, result.UpToDate())
+	assert.Equal(t, []string{"config/a.cfg"}, result.Files.Added)
+	assert.True(t, result.IndexHashStale)
+	assert.NoDirExists(t, filepath.Join(dir, LocalCacheFolder), "checking writes nothing")
+
+	refresh()
+	assert.True(t, check().UpToDate())
+
+	// pack.toml is stale on its own if its index hash doesn't match
+	packToml, err := LoadPackFile(packPath)
+	require.NoError(t, err)
+	packToml.Index.Hash = "stale"
+	require.NoError(t, NewPackWriter().Write(&packToml))
+	result = check()
+	assert.True(t, result.Files.IsEmpty())
+	assert.True(t, result.IndexHashStale)
+}
+
 func TestLoadIndex(t *testing.T) {
 	t.Run("missing file is an error", func(t *testing.T) {
 		_, err := LoadIndex(filepath.Join(t.TempDir(), "does-not-exist.toml"))
@@ -17,6 +17,29 @@ func (m IndexWriter) Write(writable Writable) error {
 	return err
 }
 
+// WriteIndex atomically writes the index file, recording the hash it was written with in index
+// so that PackToml.RefreshIndexHash can store it in pack.toml
+func WriteIndex(index *core.IndexFS) error {
+	return WriteIndexTo(hostStorage, index)
+}
+
+// WriteIndexTo is WriteIndex for an index in st
+func WriteIndexTo(st Storage, index *core.IndexFS) error {
+	repr, err := index.ToWritable()
+	if err != nil {
+		return err
+	}
+	result, err := repr.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := st.WriteFile(repr.GetFilePath(), result.Value); err != nil {
+		return err
+	}
+	index.UpdateHash(result.HashFormat, result.Hash)
+	return nil
+}
+
 // InitIndexFile creates the index file for pack if it does not already exist.
 // It returns true if a new file was created, false if the file already existed.
 func InitIndexFile(pack core.PackToml) (bool, error) {
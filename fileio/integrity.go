@@ -0,0 +1,120 @@
+package fileio
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// HashMismatch is a file whose contents don't match the hash recorded for it
+type HashMismatch struct {
+	// Path is the file that failed verification
+	Path string
+	// RecordedBy is the path of the file that records the expected hash
+	RecordedBy string
+	HashFormat string
+	// Expected is empty if no hash is recorded for the file
+	Expected string
+	// Actual is empty if the file couldn't be read, see Reason
+	Actual string
+	// Reason explains why a file listed in RecordedBy couldn't be read, such as it being missing
+	Reason string
+}
+
+func (m HashMismatch) String() string {
+	if m.Reason != "" {
+		return fmt.Sprintf("%s: listed in %s, but %s", m.Path, m.RecordedBy, m.Reason)
+	}
+	if m.Expected == "" {
+		return fmt.Sprintf("%s: no hash recorded in %s", m.Path, m.RecordedBy)
+	}
+	return fmt.Sprintf("%s: %s records %s hash %s, but the file's is %s", m.Path, m.RecordedBy, m.HashFormat, m.Expected, m.Actual)
+}
+
+// HashMismatchError is returned by a strict load (see LoadOptions) when files don't match their
+// recorded hashes
+type HashMismatchError struct {
+	Mismatches []HashMismatch
+}
+
+func (e *HashMismatchError) Error() string {
+	lines := make([]string, 0, len(e.Mismatches)+1)
+	lines = append(lines, fmt.Sprintf("%d file(s) don't match their recorded hashes:", len(e.Mismatches)))
+	for _, m := range e.Mismatches {
+		lines = append(lines, "  "+m.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// VerifyPackHashes checks the hash chain of pack, whose files are in fsys: index.toml against the
+// index hash in pack.toml, and every metadata file against its entry in index.toml. Every
+// mismatch is returned, including files that are missing or can't be read, so that they can all
+// be fixed by refreshing the index; an error is only returned if index.toml can't be parsed or a
+// hash format is unknown.
+//
+// Override files aren't checked, as packwiz refresh is expected to rehash them as they change.
+func VerifyPackHashes(fsys fs.FS, pack *core.PackToml) ([]HashMismatch, error) {
+	var mismatches []HashMismatch
+	// check returns false if the file couldn't be read, which is recorded as a mismatch
+	check := func(path, recordedBy, hashFormat, expected string) (bool, error) {
+		mismatch := HashMismatch{Path: path, RecordedBy: recordedBy, HashFormat: hashFormat, Expected: expected}
+		data, err := fs.ReadFile(fsys, path)
+		if errors.Is(err, fs.ErrNotExist) {
+			mismatch.Reason = "the file is missing"
+		} else if err != nil {
+			mismatch.Reason = fmt.Sprintf("it can't be read: %v", err)
+		}
+		if mismatch.Reason != "" {
+			mismatches = append(mismatches, mismatch)
+			return false, nil
+		}
+
+		mismatch.Actual, err = hashBytes(hashFormat, data)
+		if err != nil {
+			return false, fmt.Errorf("failed to hash %s: %w", path, err)
+		}
+		if expected == "" || !strings.EqualFold(mismatch.Actual, expected) {
+			mismatches = append(mismatches, mismatch)
+		}
+		return true, nil
+	}
+
+	indexPath := packIndexPath(pack)
+	hashFormat := pack.Index.HashFormat
+	if hashFormat == "" {
+		hashFormat = core.DefaultHashFormat
+	}
+	if ok, err := check(indexPath, pack.GetFilePath(), hashFormat, pack.Index.Hash); err != nil {
+		return nil, err
+	} else if !ok {
+		return mismatches, nil
+	}
+
+	index, err := LoadIndexFrom(fsys, indexPath)
+	if err != nil {
+		return nil, err
+	}
+	repr, err := index.ToWritable()
+	if err != nil {
+		return nil, err
+	}
+	// Metadata files may be listed more than once, with different aliases; check each once
+	checked := make(map[string]bool)
+	for _, file := range repr.Files {
+		if !file.MetaFile || checked[file.File] {
+			continue
+		}
+		checked[file.File] = true
+		hashFormat := file.HashFormat
+		if hashFormat == "" {
+			hashFormat = index.DefaultModHashFormat
+		}
+		if _, err := check(index.ResolveIndexPath(file.File), indexPath, hashFormat, file.Hash); err != nil {
+			return nil, err
+		}
+	}
+	return mismatches, nil
+}
@@ -0,0 +1,120 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func TestVerifyPackHashes(t *testing.T) {
+	resetViper(t)
+
+	t.Run("matching pack", func(t *testing.T) {
+		st := NewMemStorage()
+		require.NoError(t, WriteAllTo(st, testPack(t), "."))
+		packToml, err := LoadPackFileFrom(st, "pack.toml")
+		require.NoError(t, err)
+
+		mismatches, err := VerifyPackHashes(st, &packToml)
+		require.NoError(t, err)
+		assert.Empty(t, mismatches)
+	})
+
+	t.Run("every mismatch is reported", func(t *testing.T) {
+		st := NewMemStorage()
+		require.NoError(t, WriteAllTo(st, testPack(t), "."))
+		mod, err := st.ReadFile("mods/balm.pw.toml")
+		require.NoError(t, err)
+		require.NoError(t, st.WriteFile("mods/balm.pw.toml", append(mod, '\n')))
+		index, err := st.ReadFile("index.toml")
+		require.NoError(t, err)
+		require.NoError(t, st.WriteFile("index.toml", append(index, '\n')))
+		packToml, err := LoadPackFileFrom(st, "pack.toml")
+		require.NoError(t, err)
+
+		mismatches, err := VerifyPackHashes(st, &packToml)
+		require.NoError(t, err)
+		require.Len(t, mismatches, 2)
+		assert.Equal(t, "index.toml", mismatches[0].Path)
+		assert.Equal(t, "pack.toml", mismatches[0].RecordedBy)
+		assert.Equal(t, packToml.Index.Hash, mismatches[0].Expected)
+		assert.Equal(t, "mods/balm.pw.toml", mismatches[1].Path)
+		assert.Equal(t, "index.toml", mismatches[1].RecordedBy)
+		assert.Equal(t, core.DefaultHashFormat, mismatches[1].HashFormat)
+		assert.NotEqual(t, mismatches[1].Expected, mismatches[1].Actual)
+	})
+
+	t.Run("missing files are mismatches", func(t *testing.T) {
+		st := NewMemStorage()
+		require.NoError(t, WriteAllTo(st, testPack(t), "."))
+		require.NoError(t, st.Remove("mods/balm.pw.toml"))
+		packToml, err := LoadPackFileFrom(st, "pack.toml")
+		require.NoError(t, err)
+
+		mismatches, err := VerifyPackHashes(st, &packToml)
+		require.NoError(t, err)
+		require.Len(t, mismatches, 1)
+		assert.Equal(t, "mods/balm.pw.toml", mismatches[0].Path)
+		assert.Empty(t, mismatches[0].Actual)
+		assert.Contains(t, mismatches[0].String(), "missing")
+
+		require.NoError(t, st.Remove("index.toml"))
+		mismatches, err = VerifyPackHashes(st, &packToml)
+		require.NoError(t, err)
+		require.Len(t, mismatches, 1)
+		assert.Equal(t, "index.toml", mismatches[0].Path)
+	})
+
+	t.Run("unparseable index", func(t *testing.T) {
+		st := NewMemStorage()
+		require.NoError(t, WriteAllTo(st, testPack(t), "."))
+		require.NoError(t, st.WriteFile("index.toml", []byte("not = [toml")))
+		packToml, err := LoadPackFileFrom(st, "pack.toml")
+		require.NoError(t, err)
+
+		_, err = VerifyPackHashes(st, &packToml)
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadAllWith_Strict(t *testing.T) {
+	resetViper(t)
+	dir := t.TempDir()
+	require.NoError(t, WriteAll(testPack(t), dir))
+	packPath := filepath.Join(dir, "pack.toml")
+
+	_, err := LoadAllWith(packPath, LoadOptions{Strict: true})
+	require.NoError(t, err)
+
+	modPath := filepath.Join(dir, "mods", "balm.pw.toml")
+	mod, err := os.ReadFile(modPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(modPath, append(mod, '\n'), 0644))
+
+	_, err = LoadAll(packPath)
+	require.NoError(t, err, "only strict loads check hashes")
+	_, err = LoadAllWith(packPath, LoadOptions{Strict: true})
+	var mismatchErr *HashMismatchError
+	require.ErrorAs(t, err, &mismatchErr)
+	require.Len(t, mismatchErr.Mismatches, 1)
+	assert.Equal(t, modPath, mismatchErr.Mismatches[0].Path)
+	assert.Contains(t, err.Error(), modPath)
+
+	// Refreshing fixes the mismatch
+	packToml, err := LoadPackFile(packPath)
+	require.NoError(t, err)
+	index, err := LoadPackIndexFile(&packToml)
+	require.NoError(t, err)
+	_, err = RefreshIndexFiles(&index, packPath, nil)
+	require.NoError(t, err)
+	require.NoError(t, WriteIndex(&index))
+	packToml.RefreshIndexHash(index)
+	require.NoError(t, WritePackFileTo(hostStorage, &packToml))
+	_, err = LoadAllWith(packPath, LoadOptions{Strict: true})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,223 @@
+package fileio
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemStorage is a Storage that keeps files in memory. Folders exist as long as there are files
+// in them. It is safe for concurrent use.
+type MemStorage struct {
+	mu    sync.RWMutex
+	files map[string]*memFile
+}
+
+type memFile struct {
+	data    []byte
+	modTime time.Time
+}
+
+// NewMemStorage returns an empty MemStorage
+func NewMemStorage() *MemStorage {
+	return &MemStorage{files: make(map[string]*memFile)}
+}
+
+func (m *MemStorage) Open(name string) (fs.File, error) {
+	clean, err := cleanStorageName("open", name)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if file, ok := m.files[clean]; ok {
+		return &memOpenFile{info: memFileInfo{name: path.Base(clean), size: int64(len(file.data)), modTime: file.modTime}, Reader: bytes.NewReader(file.data)}, nil
+	}
+	entries, ok := m.readDir(clean)
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &memDir{info: memFileInfo{name: path.Base(clean), dir: true}, entries: entries}, nil
+}
+
+func (m *MemStorage) ReadFile(name string) ([]byte, error) {
+	clean, err := cleanStorageName("read", name)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	file, ok := m.files[clean]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return bytes.Clone(file.data), nil
+}
+
+func (m *MemStorage) ReadDir(name string) ([]fs.DirEntry, error) {
+	clean, err := cleanStorageName("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries, ok := m.readDir(clean)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	return entries, nil
+}
+
+// readDir lists the folder dir, returning false if there is no such folder. m.mu must be held.
+func (m *MemStorage) readDir(dir string) ([]fs.DirEntry, bool) {
+	prefix := dir + "/"
+	if dir == "." {
+		prefix = ""
+	}
+	found := dir == "."
+	children := make(map[string]fs.DirEntry)
+	for name, file := range m.files {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		found = true
+		child, _, isDir := strings.Cut(name[len(prefix):], "/")
+		if _, ok := children[child]; ok {
+			continue
+		}
+		if isDir {
+			children[child] = fs.FileInfoToDirEntry(memFileInfo{name: child, dir: true})
+		} else {
+			children[child] = fs.FileInfoToDirEntry(memFileInfo{name: child, size: int64(len(file.data)), modTime: file.modTime})
+		}
+	}
+	if !found {
+		return nil, false
+	}
+
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, entry := range children {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, true
+}
+
+func (m *MemStorage) WriteFile(name string, data []byte) error {
+	return m.writeFile(name, data, time.Now())
+}
+
+func (m *MemStorage) writeFile(name string, data []byte, modTime time.Time) error {
+	clean, err := cleanStorageName("write", name)
+	if err != nil {
+		return err
+	}
+	if clean == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// A file can't also be a folder
+	for dir := path.Dir(clean); dir != "."; dir = path.Dir(dir) {
+		if _, ok := m.files[dir]; ok {
+			return &fs.PathError{Op: "write", Path: name, Err: fs.ErrExist}
+		}
+	}
+	if _, isDir := m.readDir(clean); isDir {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrExist}
+	}
+
+	m.files[clean] = &memFile{data: bytes.Clone(data), modTime: modTime}
+	return nil
+}
+
+func (m *MemStorage) Remove(name string) error {
+	clean, err := cleanStorageName("remove", name)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.files, clean)
+	return nil
+}
+
+// Files returns the names of every file in the storage, sorted
+func (m *MemStorage) Files() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type memFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) ModTime() time.Time { return i.modTime }
+func (i memFileInfo) IsDir() bool        { return i.dir }
+func (i memFileInfo) Sys() any           { return nil }
+
+func (i memFileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0755
+	}
+	return 0644
+}
+
+type memOpenFile struct {
+	info memFileInfo
+	*bytes.Reader
+}
+
+func (f *memOpenFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memOpenFile) Close() error               { return nil }
+
+type memDir struct {
+	info    memFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(remaining) {
+		n = len(remaining)
+	}
+	d.offset += n
+	return remaining[:n], nil
+}
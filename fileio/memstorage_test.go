@@ -0,0 +1,107 @@
+package fileio
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func TestMemStorage_FS(t *testing.T) {
+	st := NewMemStorage()
+	require.NoError(t, st.WriteFile("pack.toml", []byte("name = 'a'")))
+	require.NoError(t, st.WriteFile("mods/balm.pw.toml", []byte("name = 'Balm'")))
+	require.NoError(t, st.WriteFile("config/deep/a.cfg", []byte("a=1")))
+
+	var walked []string
+	require.NoError(t, fs.WalkDir(st, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			walked = append(walked, path+"/")
+		} else {
+			walked = append(walked, path)
+		}
+		return nil
+	}))
+	assert.Equal(t, []string{"./", "config/", "config/deep/", "config/deep/a.cfg", "mods/", "mods/balm.pw.toml", "pack.toml"}, walked)
+	assert.Equal(t, []string{"config/deep/a.cfg", "mods/balm.pw.toml", "pack.toml"}, st.Files())
+
+	info, err := fs.Stat(st, "config/deep/a.cfg")
+	require.NoError(t, err)
+	assert.Equal(t, "a.cfg", info.Name())
+	assert.Equal(t, int64(3), info.Size())
+	info, err = fs.Stat(st, "config")
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+	_, err = fs.Stat(st, "missing")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	data, err := fs.ReadFile(st, "config/deep/../deep/a.cfg")
+	require.NoError(t, err, "redundant elements are cleaned")
+	assert.Equal(t, "a=1", string(data))
+}
+
+func TestMemStorage_WriteFile(t *testing.T) {
+	st := NewMemStorage()
+	data := []byte("a=1")
+	require.NoError(t, st.WriteFile("config/a.cfg", data))
+	data[0] = 'b'
+	read, err := fs.ReadFile(st, "config/a.cfg")
+	require.NoError(t, err)
+	assert.Equal(t, "a=1", string(read), "written data is copied")
+
+	assert.ErrorIs(t, st.WriteFile("config/a.cfg/b.cfg", nil), fs.ErrExist, "a file can't be a folder")
+	assert.ErrorIs(t, st.WriteFile("config", nil), fs.ErrExist, "a folder can't be a file")
+	assert.ErrorIs(t, st.WriteFile(".", nil), fs.ErrInvalid)
+	assert.ErrorIs(t, st.WriteFile("../a.cfg", nil), fs.ErrInvalid)
+
+	require.NoError(t, st.Remove("config/a.cfg"))
+	_, err = fs.Stat(st, "config")
+	assert.ErrorIs(t, err, fs.ErrNotExist, "empty folders don't exist")
+	require.NoError(t, st.WriteFile("config", nil))
+}
+
+func TestMemStorage_Pack(t *testing.T) {
+	resetViper(t)
+	st := NewMemStorage()
+	pack := testPack(t)
+	pack.SetOverride(core.NewOverrideFile("config/a.cfg", core.ClientSide, core.BytesContent("a=1")))
+	require.NoError(t, WriteAllTo(st, pack, "."))
+
+	loaded, err := LoadAllFrom(st, "pack.toml")
+	require.NoError(t, err)
+	assert.Contains(t, loaded.Mods, "balm")
+	require.Contains(t, loaded.Overrides, "config/a.cfg")
+	assert.Equal(t, FSContent{FS: st, Name: "config/a.cfg"}, loaded.Overrides["config/a.cfg"].Content)
+
+	packToml, err := LoadPackFileFrom(st, "pack.toml")
+	require.NoError(t, err)
+	result, _, err := CheckIndexFilesIn(st, &packToml, "pack.toml")
+	require.NoError(t, err)
+	assert.True(t, result.UpToDate(), "%+v", result)
+
+	// Refresh picks up a new file, and the result is up to date once written back
+	require.NoError(t, st.WriteFile("config/b.cfg", []byte("b=1")))
+	result, _, err = CheckIndexFilesIn(st, &packToml, "pack.toml")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"config/b.cfg"}, result.Files.Added)
+
+	index, err := LoadPackIndexFileFrom(st, &packToml)
+	require.NoError(t, err)
+	_, err = RefreshIndexFilesIn(st, &index, "pack.toml", nil)
+	require.NoError(t, err)
+	require.NoError(t, WriteIndexTo(st, &index))
+	packToml.RefreshIndexHash(index)
+	require.NoError(t, WritePackFileTo(st, &packToml))
+
+	result, _, err = CheckIndexFilesIn(st, &packToml, "pack.toml")
+	require.NoError(t, err)
+	assert.True(t, result.UpToDate(), "%+v", result)
+	_, err = fs.Stat(st, LocalCacheFolder)
+	assert.NoError(t, err, "the stat cache is saved to the storage")
+}
@@ -3,16 +3,21 @@ package fileio
 import (
 	"github.com/leocov-dev/packwiz-nxt/core"
 	"github.com/pelletier/go-toml/v2"
-	"os"
+	"io/fs"
 )
 
 // LoadMod attempts to load a mod file from a path
 func LoadMod(modFile string) (core.ModToml, error) {
+	return LoadModFrom(hostStorage, modFile)
+}
+
+// LoadModFrom attempts to load the mod file at modFile in fsys
+func LoadModFrom(fsys fs.FS, modFile string) (core.ModToml, error) {
 	var mod core.ModToml
 
-	// TODO: currently not loading Alias and Preserve from index.toml
+	// Alias and Preserve are recorded in index.toml, see LoadAllMods
 
-	raw, err := os.ReadFile(modFile)
+	raw, err := fs.ReadFile(fsys, modFile)
 	if err != nil {
 		return mod, err
 	}
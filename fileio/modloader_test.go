@@ -1,6 +1,7 @@
 package fileio
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -41,7 +42,7 @@ func TestLoadMod(t *testing.T) {
 	t.Run("malformed TOML is an error", func(t *testing.T) {
 		dir := t.TempDir()
 		modPath := filepath.Join(dir, "bad.pw.toml")
-		require.NoError(t, writeFile("not valid = [toml", modPath))
+		require.NoError(t, os.WriteFile(modPath, []byte("not valid = [toml"), 0644))
 
 		_, err := LoadMod(modPath)
 		assert.Error(t, err)
@@ -41,12 +41,7 @@ func WriteModAndUpdateIndex(pack *core.PackToml, modMeta *core.ModToml, metaPath
 		return err
 	}
 
-	repr, err := index.ToWritable()
-	if err != nil {
-		return err
-	}
-	indexWriter := NewIndexWriter()
-	err = indexWriter.Write(&repr)
+	err = WriteIndex(&index)
 	if err != nil {
 		return err
 	}
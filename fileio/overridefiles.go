@@ -0,0 +1,91 @@
+package fileio
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// LocalFileContent is a core.ContentProvider for a file on disk, given its path
+type LocalFileContent string
+
+func (l LocalFileContent) Open() (io.ReadCloser, error) {
+	return os.Open(string(l))
+}
+
+// LoadOverrideFiles returns the non-metafile entries of the index as override files, whose
+// contents are read from the pack folder
+func LoadOverrideFiles(index *core.IndexFS) ([]*core.OverrideFile, error) {
+	return LoadOverrideFilesFrom(hostStorage, index)
+}
+
+// LoadOverrideFilesFrom returns the non-metafile entries of the index as override files, whose
+// contents are read from fsys
+func LoadOverrideFilesFrom(fsys fs.FS, index *core.IndexFS) ([]*core.OverrideFile, error) {
+	repr, err := index.ToWritable()
+	if err != nil {
+		return nil, err
+	}
+	var overrides []*core.OverrideFile
+	for _, file := range repr.Files {
+		if file.MetaFile {
+			continue
+		}
+		content := FSContent{FS: fsys, Name: index.ResolveIndexPath(file.File)}
+		overrides = append(overrides, core.FromIndexFile(file, index.DefaultModHashFormat, content))
+	}
+	return overrides, nil
+}
+
+// writeOverrideFiles writes the contents of the pack's override files into targetDir in st. Files
+// without contents, or whose contents are already the file being written, are left as they are.
+func writeOverrideFiles(st Storage, pack core.Pack, targetDir string) error {
+	for _, override := range pack.Overrides {
+		if override.Content == nil {
+			continue
+		}
+		target := filepath.Join(targetDir, filepath.FromSlash(override.Path))
+		if isContentOf(override.Content, st, target) {
+			continue
+		}
+
+		data, err := readContent(override.Content)
+		if err != nil {
+			return fmt.Errorf("failed to read override file %s: %w", override.Path, err)
+		}
+		if err := st.WriteFile(target, data); err != nil {
+			return fmt.Errorf("failed to write override file %s: %w", override.Path, err)
+		}
+	}
+	return nil
+}
+
+// isContentOf returns true if content reads the file at name in st
+func isContentOf(content core.ContentProvider, st Storage, name string) bool {
+	var source any
+	var ok bool
+	switch c := content.(type) {
+	case LocalFileContent:
+		source, ok = fileLocation(hostStorage, string(c))
+	case FSContent:
+		source, ok = fileLocation(c.FS, c.Name)
+	}
+	if !ok {
+		return false
+	}
+	target, ok := fileLocation(st, name)
+	return ok && source == target
+}
+
+func readContent(content core.ContentProvider) ([]byte, error) {
+	r, err := content.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
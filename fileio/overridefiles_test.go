@@ -0,0 +1,99 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func TestWriteAllThenLoadAll_Overrides(t *testing.T) {
+	resetViper(t)
+	dir := t.TempDir()
+	pack := testPack(t)
+	pack.SetOverride(core.NewOverrideFile("config/a.cfg", core.ClientSide, core.BytesContent("a=1")))
+	preserved := core.NewOverrideFile("options.txt", core.EmptySide, core.BytesContent("fov:1"))
+	preserved.Preserve = true
+	pack.SetOverride(preserved)
+
+	require.NoError(t, WriteAll(pack, dir))
+	packToml, err := LoadPackFile(filepath.Join(dir, "pack.toml"))
+	require.NoError(t, err)
+	result, _, err := CheckIndexFiles(&packToml, filepath.Join(dir, "pack.toml"))
+	require.NoError(t, err)
+	assert.True(t, result.UpToDate(), "%+v", result)
+
+	data, err := os.ReadFile(filepath.Join(dir, "config", "a.cfg"))
+	require.NoError(t, err)
+	assert.Equal(t, "a=1", string(data))
+
+	loaded, err := LoadAll(filepath.Join(dir, "pack.toml"))
+	require.NoError(t, err)
+	require.Len(t, loaded.Overrides, 2)
+	require.Contains(t, loaded.Overrides, "config/a.cfg")
+	cfg := loaded.Overrides["config/a.cfg"]
+	assert.Equal(t, core.ClientSide, cfg.Side)
+	assert.Equal(t, pack.Overrides["config/a.cfg"].Hash, cfg.Hash)
+	assert.Equal(t, core.DefaultHashFormat, cfg.HashFormat)
+	require.Contains(t, loaded.Overrides, "options.txt")
+	assert.True(t, loaded.Overrides["options.txt"].Preserve)
+
+	// Writing the loaded pack back keeps the override entries and files
+	require.NoError(t, WriteAll(*loaded, dir))
+	reloaded, err := LoadAll(filepath.Join(dir, "pack.toml"))
+	require.NoError(t, err)
+	assert.Len(t, reloaded.Overrides, 2)
+	data, err = os.ReadFile(filepath.Join(dir, "config", "a.cfg"))
+	require.NoError(t, err)
+	assert.Equal(t, "a=1", string(data))
+
+	packToml, err = LoadPackFile(filepath.Join(dir, "pack.toml"))
+	require.NoError(t, err)
+	result, _, err = CheckIndexFiles(&packToml, filepath.Join(dir, "pack.toml"))
+	require.NoError(t, err)
+	assert.True(t, result.UpToDate(), "%+v", result)
+
+	// Writing it elsewhere copies the override files from the pack folder
+	other := t.TempDir()
+	require.NoError(t, WriteAll(*loaded, other))
+	data, err = os.ReadFile(filepath.Join(other, "options.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "fov:1", string(data))
+}
+
+func TestLoadOverrideFiles(t *testing.T) {
+	dir := t.TempDir()
+	indexPath := filepath.Join(dir, "index.toml")
+	require.NoError(t, os.WriteFile(indexPath, []byte(`hash-format = "sha1"
+
+[[files]]
+file = "config/a.cfg"
+hash = "abc"
+side = "server"
+
+[[files]]
+file = "config/a.cfg"
+alias = "config/b.cfg"
+hash = "abc"
+
+[[files]]
+file = "mods/balm.pw.toml"
+hash = "def"
+metafile = true
+`), 0644))
+	index, err := LoadIndex(indexPath)
+	require.NoError(t, err)
+
+	overrides, err := LoadOverrideFiles(&index)
+	require.NoError(t, err)
+	require.Len(t, overrides, 2)
+	assert.Equal(t, "config/a.cfg", overrides[0].GetInstallPath())
+	assert.Equal(t, core.ServerSide, overrides[0].Side)
+	assert.Equal(t, "sha1", overrides[0].HashFormat)
+	assert.Equal(t, "config/b.cfg", overrides[1].GetInstallPath())
+	assert.Equal(t, FSContent{FS: hostStorage, Name: filepath.Join(dir, "config", "a.cfg")}, overrides[1].Content)
+}
@@ -5,14 +5,19 @@ import (
 	"github.com/leocov-dev/packwiz-nxt/core"
 	"github.com/pelletier/go-toml/v2"
 	"github.com/spf13/viper"
-	"os"
+	"io/fs"
 	"path/filepath"
 )
 
 // LoadPackFile loads the modpack metadata to a PackToml struct
 func LoadPackFile(packPath string) (core.PackToml, error) {
+	return LoadPackFileFrom(hostStorage, packPath)
+}
+
+// LoadPackFileFrom loads the modpack metadata at packPath in fsys
+func LoadPackFileFrom(fsys fs.FS, packPath string) (core.PackToml, error) {
 	var modpack core.PackToml
-	raw, err := os.ReadFile(packPath)
+	raw, err := fs.ReadFile(fsys, packPath)
 	if err != nil {
 		return core.PackToml{}, err
 	}
@@ -39,29 +44,78 @@ func LoadPackFile(packPath string) (core.PackToml, error) {
 }
 
 func LoadPackIndexFile(pack *core.PackToml) (core.IndexFS, error) {
+	return LoadPackIndexFileFrom(hostStorage, pack)
+}
+
+// LoadPackIndexFileFrom loads the index file of pack from fsys
+func LoadPackIndexFileFrom(fsys fs.FS, pack *core.PackToml) (core.IndexFS, error) {
+	return LoadIndexFrom(fsys, packIndexPath(pack))
+}
+
+// packIndexPath returns the path of the index file of pack
+func packIndexPath(pack *core.PackToml) string {
 	if filepath.IsAbs(pack.Index.File) {
-		return LoadIndex(pack.Index.File)
+		return pack.Index.File
 	}
 	fileNative := filepath.FromSlash(pack.Index.File)
-	return LoadIndex(filepath.Join(pack.GetPackDir(), fileNative))
+	return filepath.Join(pack.GetPackDir(), fileNative)
+}
+
+// LoadOptions changes how LoadAllWith loads a pack
+type LoadOptions struct {
+	// Strict checks that index.toml matches the index hash in pack.toml, and that every
+	// metadata file matches its entry in index.toml (see VerifyPackHashes), failing with a
+	// *HashMismatchError listing every mismatch otherwise
+	Strict bool
 }
 
 func LoadAll(packPath string) (*core.Pack, error) {
-	packMeta, err := LoadPackFile(packPath)
+	return LoadAllFrom(hostStorage, packPath)
+}
+
+// LoadAllWith is LoadAll with options
+func LoadAllWith(packPath string, options LoadOptions) (*core.Pack, error) {
+	return LoadAllFromWith(hostStorage, packPath, options)
+}
+
+// LoadAllFrom loads the pack whose pack.toml is at packPath in fsys, with its index, mods and
+// override files, whose contents are read from fsys when needed
+func LoadAllFrom(fsys fs.FS, packPath string) (*core.Pack, error) {
+	return LoadAllFromWith(fsys, packPath, LoadOptions{})
+}
+
+// LoadAllFromWith is LoadAllFrom with options
+func LoadAllFromWith(fsys fs.FS, packPath string, options LoadOptions) (*core.Pack, error) {
+	packMeta, err := LoadPackFileFrom(fsys, packPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Strict {
+		mismatches, err := VerifyPackHashes(fsys, &packMeta)
+		if err != nil {
+			return nil, err
+		}
+		if len(mismatches) > 0 {
+			return nil, &HashMismatchError{Mismatches: mismatches}
+		}
+	}
+
+	indexMeta, err := LoadPackIndexFileFrom(fsys, &packMeta)
 	if err != nil {
 		return nil, err
 	}
 
-	indexMeta, err := LoadPackIndexFile(&packMeta)
+	modMetas, err := LoadAllModsFrom(fsys, &indexMeta)
 	if err != nil {
 		return nil, err
 	}
 
-	modMetas, err := LoadAllMods(&indexMeta)
+	overrides, err := LoadOverrideFilesFrom(fsys, &indexMeta)
 	if err != nil {
 		return nil, err
 	}
 
-	pack := core.FromPackAndModsMeta(packMeta, modMetas)
+	pack := core.FromPackAndModsMeta(packMeta, modMetas, overrides)
 	return pack, nil
 }
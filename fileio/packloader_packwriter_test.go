@@ -1,6 +1,7 @@
 package fileio
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -58,6 +59,55 @@ func TestWriteAllThenLoadAll_RoundTrip(t *testing.T) {
 	assert.Equal(t, "balm-fabric.jar", loaded.Mods["balm"].FileName)
 }
 
+func TestWriteAllThenLoadAll_NestedMetaFolder(t *testing.T) {
+	resetViper(t)
+	dir := t.TempDir()
+	pack := testPack(t)
+	pack.SetDatapackFolder("config/paxi/datapacks")
+	pack.SetMod(core.NewMod(
+		"terralith", "Terralith", "Terralith.zip", core.UniversalSide, "config/paxi/datapacks", "",
+		false, false,
+		core.ModUpdate{},
+		core.ModDownload{URL: "https://example.com/Terralith.zip", HashFormat: "sha1", Hash: "def456"},
+		nil,
+	))
+
+	require.NoError(t, WriteAll(pack, dir))
+
+	loaded, err := LoadAll(filepath.Join(dir, "pack.toml"))
+	require.NoError(t, err)
+
+	require.Contains(t, loaded.Mods, "terralith")
+	assert.Equal(t, "config/paxi/datapacks", loaded.Mods["terralith"].ModType)
+	assert.Equal(t, "mods", loaded.Mods["balm"].ModType)
+	assert.True(t, loaded.IsDatapack(loaded.Mods["terralith"]))
+}
+
+func TestWriteAllThenLoadAll_AliasAndPreserve(t *testing.T) {
+	resetViper(t)
+	dir := t.TempDir()
+	pack := testPack(t)
+	require.NoError(t, pack.Mods["balm"].SetCopies([]core.ModCopy{
+		{Preserve: true},
+		{Alias: "mods/extra/balm-fabric.jar"},
+	}))
+
+	require.NoError(t, WriteAll(pack, dir))
+
+	loaded, err := LoadAll(filepath.Join(dir, "pack.toml"))
+	require.NoError(t, err)
+	require.Contains(t, loaded.Mods, "balm")
+	assert.Equal(t, pack.Mods["balm"].GetCopies(), loaded.Mods["balm"].GetCopies())
+
+	// Writing the loaded pack back leaves the index as refreshing it would
+	require.NoError(t, WriteAll(*loaded, dir))
+	packToml, err := LoadPackFile(filepath.Join(dir, "pack.toml"))
+	require.NoError(t, err)
+	result, _, err := CheckIndexFiles(&packToml, filepath.Join(dir, "pack.toml"))
+	require.NoError(t, err)
+	assert.True(t, result.UpToDate(), "%+v", result)
+}
+
 func TestLoadPackFile(t *testing.T) {
 	resetViper(t)
 
@@ -69,7 +119,7 @@ func TestLoadPackFile(t *testing.T) {
 	t.Run("malformed TOML is an error", func(t *testing.T) {
 		dir := t.TempDir()
 		packPath := filepath.Join(dir, "pack.toml")
-		require.NoError(t, writeFile("not valid = [toml", packPath))
+		require.NoError(t, os.WriteFile(packPath, []byte("not valid = [toml"), 0644))
 
 		_, err := LoadPackFile(packPath)
 		assert.Error(t, err)
@@ -17,21 +17,21 @@ func (p PackWriter) Write(writable Writable) error {
 	return err
 }
 
-func writeFile(text string, targetPath string) error {
-	f, err := CreateFile(targetPath)
+// WritePackFileTo writes pack.toml to st, e.g. after RefreshIndexHash
+func WritePackFileTo(st Storage, pack *core.PackToml) error {
+	result, err := pack.Marshal()
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-
-	if _, err = f.Write([]byte(text)); err != nil {
-		return err
-	}
-
-	return nil
+	return st.WriteFile(pack.GetFilePath(), result.Value)
 }
 
 func WritePackAndIndex(pack core.Pack, targetDir string) error {
+	return WritePackAndIndexTo(hostStorage, pack, targetDir)
+}
+
+// WritePackAndIndexTo writes pack.toml and index.toml into targetDir in st
+func WritePackAndIndexTo(st Storage, pack core.Pack, targetDir string) error {
 	packTarget := filepath.Join(targetDir, "pack.toml")
 	indexTarget := filepath.Join(targetDir, "index.toml")
 
@@ -39,7 +39,7 @@ func WritePackAndIndex(pack core.Pack, targetDir string) error {
 	if err != nil {
 		return err
 	}
-	if err = writeFile(packToml, packTarget); err != nil {
+	if err = st.WriteFile(packTarget, []byte(packToml)); err != nil {
 		return err
 	}
 
@@ -47,7 +47,7 @@ func WritePackAndIndex(pack core.Pack, targetDir string) error {
 	if err != nil {
 		return err
 	}
-	if err = writeFile(indexToml, indexTarget); err != nil {
+	if err = st.WriteFile(indexTarget, []byte(indexToml)); err != nil {
 		return err
 	}
 
@@ -55,7 +55,17 @@ func WritePackAndIndex(pack core.Pack, targetDir string) error {
 }
 
 func WriteAll(pack core.Pack, targetDir string) error {
-	if err := WritePackAndIndex(pack, targetDir); err != nil {
+	return WriteAllTo(hostStorage, pack, targetDir)
+}
+
+// WriteAllTo writes pack.toml, index.toml, every mod's metadata file and the contents of the
+// override files into targetDir in st
+func WriteAllTo(st Storage, pack core.Pack, targetDir string) error {
+	if err := WritePackAndIndexTo(st, pack, targetDir); err != nil {
+		return err
+	}
+
+	if err := writeOverrideFiles(st, pack, targetDir); err != nil {
 		return err
 	}
 
@@ -65,7 +75,7 @@ func WriteAll(pack core.Pack, targetDir string) error {
 			return err
 		}
 		modTarget := filepath.Join(targetDir, mod.GetRelMetaPath())
-		if err = writeFile(modToml, modTarget); err != nil {
+		if err = st.WriteFile(modTarget, []byte(modToml)); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,302 @@
+package fileio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// DefaultRemoteConcurrency is the number of metadata files LoadRemotePack fetches at once when
+// no concurrency is given
+const DefaultRemoteConcurrency = 8
+
+// maxRemoteMetaSize bounds the size of pack.toml, index.toml and metadata files fetched by
+// LoadRemotePack, so a misbehaving server can't make it read without limit
+const maxRemoteMetaSize = 16 << 20
+
+// IntegrityError is returned when a file fetched from a remote pack doesn't match the hash
+// recorded for it by its parent: the index hash in pack.toml, or the file's entry in index.toml
+type IntegrityError struct {
+	// URL is the file that failed verification
+	URL string
+	// RecordedBy is the URL of the file that records the expected hash
+	RecordedBy string
+	HashFormat string
+	Expected   string
+	Actual     string
+}
+
+func (e *IntegrityError) Error() string {
+	if e.Expected == "" {
+		return fmt.Sprintf("integrity check failed for %s: %s records no hash for it", e.URL, e.RecordedBy)
+	}
+	return fmt.Sprintf("integrity check failed for %s: %s hash is %s, but %s records %s", e.URL, e.HashFormat, e.Actual, e.RecordedBy, e.Expected)
+}
+
+// LoadRemotePack loads the pack published at packURL, the URL of its pack.toml. index.toml and
+// every metadata file are fetched relative to it, with up to concurrency requests at once
+// (DefaultRemoteConcurrency if concurrency <= 0), and each is checked against the hash its parent
+// records, returning an *IntegrityError on a mismatch. Fetching stops at the first error or when
+// ctx is cancelled.
+//
+// Override files aren't fetched up front: their contents are fetched, and verified against their
+// index entries, when they are read.
+func LoadRemotePack(ctx context.Context, packURL string, concurrency int) (*core.Pack, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultRemoteConcurrency
+	}
+	packLocation, err := url.Parse(packURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pack URL %s: %w", packURL, err)
+	}
+
+	st := NewMemStorage()
+	packData, err := fetchRemoteFile(ctx, packLocation.String())
+	if err != nil {
+		return nil, err
+	}
+	var packMeta core.PackToml
+	if err := toml.Unmarshal(packData, &packMeta); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", packURL, err)
+	}
+	if err := st.WriteFile("pack.toml", packData); err != nil {
+		return nil, err
+	}
+
+	indexName, err := cleanStorageName("open", packMeta.Index.File)
+	if err != nil {
+		return nil, fmt.Errorf("index file %s of %s is outside the pack", packMeta.Index.File, packURL)
+	}
+	indexLocation := resolveRemotePath(packLocation, indexName)
+	indexData, err := fetchRemoteFile(ctx, indexLocation.String())
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyRemoteFile(indexData, indexLocation.String(), packURL, packMeta.Index.HashFormat, packMeta.Index.Hash); err != nil {
+		return nil, err
+	}
+	var index core.IndexTomlRepresentation
+	if err := toml.Unmarshal(indexData, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", indexLocation, err)
+	}
+	if index.DefaultModHashFormat == "" {
+		index.DefaultModHashFormat = core.DefaultHashFormat
+	}
+	if err := st.WriteFile(indexName, indexData); err != nil {
+		return nil, err
+	}
+
+	// Metadata files may be listed more than once, with different aliases; fetch each once
+	var metaFiles []core.IndexFile
+	seen := make(map[string]bool)
+	for _, file := range index.Files {
+		if !file.MetaFile || seen[file.File] {
+			continue
+		}
+		seen[file.File] = true
+		if file.HashFormat == "" {
+			file.HashFormat = index.DefaultModHashFormat
+		}
+		metaFiles = append(metaFiles, file)
+	}
+	if err := fetchRemoteMetaFiles(ctx, st, indexLocation, indexName, metaFiles, concurrency); err != nil {
+		return nil, err
+	}
+
+	pack, err := LoadAllFrom(st, "pack.toml")
+	if err != nil {
+		return nil, err
+	}
+	for _, override := range pack.Overrides {
+		override.Content = remoteContent{
+			url:        resolveRemotePath(indexLocation, override.Path).String(),
+			recordedBy: indexLocation.String(),
+			hashFormat: override.HashFormat,
+			hash:       override.Hash,
+		}
+	}
+	return pack, nil
+}
+
+// fetchRemoteMetaFiles fetches and verifies files, listed in the index at indexLocation, into st
+// next to indexName, using a pool of concurrency workers. The first error cancels the rest.
+func fetchRemoteMetaFiles(ctx context.Context, st Storage, indexLocation *url.URL, indexName string, files []core.IndexFile, concurrency int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var firstErr error
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	fetch := func(file core.IndexFile) error {
+		name, err := cleanStorageName("open", path.Join(path.Dir(indexName), file.File))
+		if err != nil {
+			return fmt.Errorf("metadata file %s in %s is outside the pack", file.File, indexLocation)
+		}
+		location := resolveRemotePath(indexLocation, file.File).String()
+		data, err := fetchRemoteFile(ctx, location)
+		if err != nil {
+			return err
+		}
+		if err := verifyRemoteFile(data, location, indexLocation.String(), file.HashFormat, file.Hash); err != nil {
+			return err
+		}
+		return st.WriteFile(name, data)
+	}
+
+	fileCh := make(chan core.IndexFile)
+	var wg sync.WaitGroup
+	for i := 0; i < min(concurrency, len(files)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range fileCh {
+				if err := fetch(file); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+dispatch:
+	for _, file := range files {
+		select {
+		case fileCh <- file:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(fileCh)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	// The parent context may have been cancelled while dispatching, before any fetch failed
+	return ctx.Err()
+}
+
+// resolveRemotePath returns the URL of the file at the slash-separated path p, relative to the
+// folder of the file at base
+func resolveRemotePath(base *url.URL, p string) *url.URL {
+	return base.ResolveReference(&url.URL{Path: p})
+}
+
+// fetchRemoteFile returns the contents of the file at location
+func fetchRemoteFile(ctx context.Context, location string) ([]byte, error) {
+	resp, err := core.GetWithUAContext(ctx, location, "application/toml, */*;q=0.8")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", location, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", location, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteMetaSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", location, err)
+	}
+	if len(data) > maxRemoteMetaSize {
+		return nil, fmt.Errorf("failed to fetch %s: larger than %d bytes", location, maxRemoteMetaSize)
+	}
+	return data, nil
+}
+
+// verifyRemoteFile checks data, fetched from location, against the hash recorded by recordedBy
+func verifyRemoteFile(data []byte, location, recordedBy, hashFormat, hash string) error {
+	if hash == "" {
+		return &IntegrityError{URL: location, RecordedBy: recordedBy, HashFormat: hashFormat}
+	}
+	actual, err := hashBytes(hashFormat, data)
+	if err != nil {
+		return fmt.Errorf("failed to verify %s: %w", location, err)
+	}
+	return checkRemoteHash(location, recordedBy, hashFormat, hash, actual)
+}
+
+func checkRemoteHash(location, recordedBy, hashFormat, expected, actual string) error {
+	if !strings.EqualFold(actual, expected) {
+		return &IntegrityError{URL: location, RecordedBy: recordedBy, HashFormat: hashFormat, Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+func hashBytes(hashFormat string, data []byte) (string, error) {
+	h, err := core.GetHashImpl(hashFormat)
+	if err != nil {
+		return "", err
+	}
+	if _, err := h.Write(data); err != nil {
+		return "", err
+	}
+	return h.String(), nil
+}
+
+// remoteContent is a core.ContentProvider for an override file of a remote pack. Its contents
+// are fetched when opened, and reading them returns an *IntegrityError at the end if they don't
+// match the hash recorded in the index.
+type remoteContent struct {
+	url        string
+	recordedBy string
+	hashFormat string
+	hash       string
+}
+
+func (c remoteContent) Open() (io.ReadCloser, error) {
+	if c.hash == "" {
+		return nil, &IntegrityError{URL: c.url, RecordedBy: c.recordedBy, HashFormat: c.hashFormat}
+	}
+	h, err := core.GetHashImpl(c.hashFormat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify %s: %w", c.url, err)
+	}
+	resp, err := core.GetWithUA(c.url, "application/octet-stream")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", c.url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %s: %s", c.url, resp.Status)
+	}
+	return &verifyingReader{content: c, body: resp.Body, hash: h}, nil
+}
+
+// verifyingReader hashes what is read from body, and replaces io.EOF with an *IntegrityError if
+// the hash doesn't match
+type verifyingReader struct {
+	content remoteContent
+	body    io.ReadCloser
+	hash    core.HashStringer
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	r.hash.Write(p[:n])
+	if errors.Is(err, io.EOF) {
+		c := r.content
+		if err := checkRemoteHash(c.url, c.recordedBy, c.hashFormat, c.hash, r.hash.String()); err != nil {
+			return n, err
+		}
+	}
+	return n, err
+}
+
+func (r *verifyingReader) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,111 @@
+package fileio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// serveTestPack writes the test pack, with an override file, to a MemStorage and serves it under
+// /pack/, returning the storage and the URL of pack.toml
+func serveTestPack(t *testing.T) (*MemStorage, string) {
+	t.Helper()
+	st := NewMemStorage()
+	pack := testPack(t)
+	pack.SetOverride(core.NewOverrideFile("config/a.cfg", core.ClientSide, core.BytesContent("a=1")))
+	require.NoError(t, WriteAllTo(st, pack, "."))
+
+	server := httptest.NewServer(http.StripPrefix("/pack/", http.FileServer(http.FS(st))))
+	t.Cleanup(server.Close)
+	return st, server.URL + "/pack/pack.toml"
+}
+
+func TestLoadRemotePack(t *testing.T) {
+	resetViper(t)
+	_, packURL := serveTestPack(t)
+
+	pack, err := LoadRemotePack(context.Background(), packURL, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "PackA", pack.Name)
+	require.Contains(t, pack.Mods, "balm")
+	assert.Equal(t, "balm-fabric.jar", pack.Mods["balm"].FileName)
+
+	require.Contains(t, pack.Overrides, "config/a.cfg")
+	data, err := readContent(pack.Overrides["config/a.cfg"].Content)
+	require.NoError(t, err)
+	assert.Equal(t, "a=1", string(data))
+}
+
+func TestLoadRemotePack_Integrity(t *testing.T) {
+	resetViper(t)
+
+	t.Run("tampered metadata file", func(t *testing.T) {
+		st, packURL := serveTestPack(t)
+		require.NoError(t, st.WriteFile("mods/balm.pw.toml", []byte("name = 'Not Balm'")))
+
+		_, err := LoadRemotePack(context.Background(), packURL, 2)
+		var integrityErr *IntegrityError
+		require.ErrorAs(t, err, &integrityErr)
+		assert.Equal(t, packURL[:len(packURL)-len("pack.toml")]+"mods/balm.pw.toml", integrityErr.URL)
+		assert.Equal(t, packURL[:len(packURL)-len("pack.toml")]+"index.toml", integrityErr.RecordedBy)
+		assert.Equal(t, core.DefaultHashFormat, integrityErr.HashFormat)
+		assert.NotEqual(t, integrityErr.Expected, integrityErr.Actual)
+	})
+
+	t.Run("tampered index", func(t *testing.T) {
+		st, packURL := serveTestPack(t)
+		index, err := st.ReadFile("index.toml")
+		require.NoError(t, err)
+		require.NoError(t, st.WriteFile("index.toml", append(index, '\n')))
+
+		_, err = LoadRemotePack(context.Background(), packURL, 0)
+		var integrityErr *IntegrityError
+		require.ErrorAs(t, err, &integrityErr)
+		assert.Equal(t, packURL, integrityErr.RecordedBy)
+	})
+
+	t.Run("tampered override file", func(t *testing.T) {
+		st, packURL := serveTestPack(t)
+		require.NoError(t, st.WriteFile("config/a.cfg", []byte("a=2")))
+
+		pack, err := LoadRemotePack(context.Background(), packURL, 0)
+		require.NoError(t, err, "override files are only fetched when read")
+		_, err = readContent(pack.Overrides["config/a.cfg"].Content)
+		var integrityErr *IntegrityError
+		assert.ErrorAs(t, err, &integrityErr)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		st, packURL := serveTestPack(t)
+		require.NoError(t, st.Remove("mods/balm.pw.toml"))
+
+		_, err := LoadRemotePack(context.Background(), packURL, 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+}
+
+func TestLoadRemotePack_Cancelled(t *testing.T) {
+	resetViper(t)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = io.WriteString(w, "")
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := LoadRemotePack(ctx, server.URL+"/pack.toml", 0)
+	assert.True(t, errors.Is(err, context.Canceled), "%v", err)
+	assert.Zero(t, requests.Load())
+}
@@ -0,0 +1,102 @@
+package fileio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// LocalCacheFolder is the folder in the pack root where packwiz keeps local state that isn't
+// part of the pack, such as the stat cache used by RefreshIndexFiles. It ignores itself with
+// its own .gitignore and is never added to the index.
+const LocalCacheFolder = ".packwiz-cache"
+
+const statCacheFile = "refresh.json"
+const statCacheLatestVersion = 1
+
+// statCacheRacyWindow is how close to the time the stat cache was saved a file can have been
+// modified for its cached hash to still be trusted: a file changed again within the file
+// system's timestamp granularity after it was hashed would otherwise keep its old hash.
+const statCacheRacyWindow = 2 * time.Second
+
+type statCacheEntry struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mtime"`
+	Hash    string    `json:"hash"`
+}
+
+// statCache records the size, modification time and hash of each file hashed by
+// RefreshIndexFiles, keyed by index path, so unchanged files aren't hashed again
+type statCache struct {
+	Version    int                       `json:"version"`
+	HashFormat string                    `json:"hash-format"`
+	SavedAt    time.Time                 `json:"saved-at"`
+	Files      map[string]statCacheEntry `json:"files"`
+}
+
+func newStatCache() *statCache {
+	return &statCache{
+		Version:    statCacheLatestVersion,
+		HashFormat: core.DefaultHashFormat,
+		Files:      make(map[string]statCacheEntry),
+	}
+}
+
+// loadStatCache reads the stat cache of the pack at packRoot in fsys. The cache only saves work,
+// so a missing, unreadable or outdated cache is returned as an empty one.
+func loadStatCache(fsys fs.FS, packRoot string) *statCache {
+	data, err := fs.ReadFile(fsys, filepath.Join(packRoot, LocalCacheFolder, statCacheFile))
+	if err != nil {
+		return newStatCache()
+	}
+	var cache statCache
+	if err := json.Unmarshal(data, &cache); err != nil ||
+		cache.Version != statCacheLatestVersion || cache.HashFormat != core.DefaultHashFormat || cache.Files == nil {
+		return newStatCache()
+	}
+	return &cache
+}
+
+// lookup returns the cached hash of the file at relPath, if its size and modification time
+// are unchanged since it was hashed
+func (c *statCache) lookup(relPath string, info fs.FileInfo) (string, bool) {
+	entry, ok := c.Files[relPath]
+	if !ok || entry.Size != info.Size() || !entry.ModTime.Equal(info.ModTime()) {
+		return "", false
+	}
+	if !entry.ModTime.Before(c.SavedAt.Add(-statCacheRacyWindow)) {
+		return "", false
+	}
+	return entry.Hash, true
+}
+
+func (c *statCache) store(relPath string, info fs.FileInfo, hash string) {
+	c.Files[relPath] = statCacheEntry{Size: info.Size(), ModTime: info.ModTime(), Hash: hash}
+}
+
+// save writes the stat cache into the LocalCacheFolder of the pack at packRoot in st
+func (c *statCache) save(st Storage, packRoot string) error {
+	dir := filepath.Join(packRoot, LocalCacheFolder)
+	gitignorePath := filepath.Join(dir, ".gitignore")
+	if _, err := fs.Stat(st, gitignorePath); errors.Is(err, fs.ErrNotExist) {
+		if err := st.WriteFile(gitignorePath, []byte("# Local packwiz state, not part of the pack\n*\n")); err != nil {
+			return fmt.Errorf("failed to write %s: %w", gitignorePath, err)
+		}
+	}
+
+	c.SavedAt = time.Now()
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to serialise stat cache: %w", err)
+	}
+
+	if err := st.WriteFile(filepath.Join(dir, statCacheFile), data); err != nil {
+		return fmt.Errorf("failed to write stat cache: %w", err)
+	}
+	return nil
+}
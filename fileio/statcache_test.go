@@ -0,0 +1,59 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testFileInfo struct {
+	os.FileInfo
+	size    int64
+	modTime time.Time
+}
+
+func (i testFileInfo) Size() int64        { return i.size }
+func (i testFileInfo) ModTime() time.Time { return i.modTime }
+
+func TestStatCacheLookup(t *testing.T) {
+	modTime := time.Now().Add(-time.Hour)
+	cache := newStatCache()
+	cache.store("config/a.cfg", testFileInfo{size: 3, modTime: modTime}, "hash")
+	cache.SavedAt = time.Now()
+
+	hash, ok := cache.lookup("config/a.cfg", testFileInfo{size: 3, modTime: modTime})
+	assert.True(t, ok)
+	assert.Equal(t, "hash", hash)
+
+	_, ok = cache.lookup("config/a.cfg", testFileInfo{size: 4, modTime: modTime})
+	assert.False(t, ok, "size changed")
+	_, ok = cache.lookup("config/a.cfg", testFileInfo{size: 3, modTime: modTime.Add(time.Second)})
+	assert.False(t, ok, "modification time changed")
+	_, ok = cache.lookup("config/b.cfg", testFileInfo{size: 3, modTime: modTime})
+	assert.False(t, ok, "not cached")
+
+	cache.SavedAt = modTime.Add(time.Second)
+	_, ok = cache.lookup("config/a.cfg", testFileInfo{size: 3, modTime: modTime})
+	assert.False(t, ok, "files modified just before the cache was saved aren't trusted")
+}
+
+func TestLoadStatCache(t *testing.T) {
+	dir := t.TempDir()
+	assert.Empty(t, loadStatCache(hostStorage, dir).Files, "missing cache is empty")
+
+	cache := newStatCache()
+	cache.store("config/a.cfg", testFileInfo{size: 3, modTime: time.Now()}, "hash")
+	require.NoError(t, cache.save(hostStorage, dir))
+	assert.Equal(t, "hash", loadStatCache(hostStorage, dir).Files["config/a.cfg"].Hash)
+
+	gitignore, err := os.ReadFile(filepath.Join(dir, LocalCacheFolder, ".gitignore"))
+	require.NoError(t, err)
+	assert.Contains(t, string(gitignore), "*")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, LocalCacheFolder, statCacheFile), []byte("{not json"), 0644))
+	assert.Empty(t, loadStatCache(hostStorage, dir).Files, "corrupt cache is empty")
+}
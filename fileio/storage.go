@@ -0,0 +1,158 @@
+package fileio
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Storage is where a pack's files are kept, so packs can be loaded, refreshed and written without
+// touching the file system: it is read through fs.FS and written through StorageWriter. Names
+// are slash-separated paths relative to the root of the storage, as for fs.FS, although the
+// storages in this package also accept the OS path separator.
+//
+// DirStorage keeps a pack in a folder on disk, MemStorage in memory and ZipStorage in a zip
+// archive.
+type Storage interface {
+	fs.FS
+	StorageWriter
+}
+
+// StorageWriter writes the files of a Storage
+type StorageWriter interface {
+	// WriteFile creates or replaces the file at name with data, creating the folders it is in
+	// as needed
+	WriteFile(name string, data []byte) error
+	// Remove removes the file at name. Removing a file that doesn't exist isn't an error.
+	Remove(name string) error
+}
+
+// hostStorage is the Storage used by the functions that take file paths (LoadAll, WriteAll,
+// RefreshIndexFiles, ...): its names are OS paths, relative to the working directory or
+// absolute, as used by the os package
+var hostStorage Storage = dirStorage{}
+
+// dirStorage keeps files in the folder root, or anywhere on disk if root is ""
+type dirStorage struct {
+	root string
+}
+
+// DirStorage returns a Storage for the files in the folder dir. Files are written atomically, so
+// other processes never see partially written files.
+func DirStorage(dir string) Storage {
+	if dir == "" {
+		dir = "."
+	}
+	return dirStorage{root: dir}
+}
+
+// osPath returns the OS path of the file at name
+func (d dirStorage) osPath(op string, name string) (string, error) {
+	if d.root == "" {
+		return name, nil
+	}
+	clean, err := cleanStorageName(op, name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d.root, filepath.FromSlash(clean)), nil
+}
+
+func (d dirStorage) Open(name string) (fs.File, error) {
+	p, err := d.osPath("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+func (d dirStorage) Stat(name string) (fs.FileInfo, error) {
+	p, err := d.osPath("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Stat(p)
+}
+
+func (d dirStorage) ReadFile(name string) ([]byte, error) {
+	p, err := d.osPath("read", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(p)
+}
+
+func (d dirStorage) ReadDir(name string) ([]fs.DirEntry, error) {
+	p, err := d.osPath("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadDir(p)
+}
+
+func (d dirStorage) WriteFile(name string, data []byte) error {
+	p, err := d.osPath("write", name)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(p, data)
+}
+
+func (d dirStorage) Remove(name string) error {
+	p, err := d.osPath("remove", name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// cleanStorageName turns name into a valid fs.FS name, accepting the OS path separator and
+// redundant elements such as "./"
+func cleanStorageName(op string, name string) (string, error) {
+	clean := path.Clean(filepath.ToSlash(name))
+	if !fs.ValidPath(clean) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return clean, nil
+}
+
+// FSContent is a core.ContentProvider for the file at Name in FS
+type FSContent struct {
+	FS   fs.FS
+	Name string
+}
+
+func (c FSContent) Open() (io.ReadCloser, error) {
+	return c.FS.Open(c.Name)
+}
+
+// fileLocation identifies the file at name in fsys, if fsys is one of the storages in this
+// package, so that files in different storages can be compared
+func fileLocation(fsys fs.FS, name string) (any, bool) {
+	switch s := fsys.(type) {
+	case dirStorage:
+		p, err := s.osPath("open", name)
+		if err != nil {
+			return nil, false
+		}
+		abs, err := filepath.Abs(p)
+		return abs, err == nil
+	case *MemStorage:
+		clean, err := cleanStorageName("open", name)
+		return memLocation{storage: s, name: clean}, err == nil
+	case *ZipStorage:
+		return fileLocation(s.MemStorage, name)
+	}
+	return nil, false
+}
+
+type memLocation struct {
+	storage *MemStorage
+	name    string
+}
@@ -0,0 +1,67 @@
+package fileio
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirStorage(t *testing.T) {
+	dir := t.TempDir()
+	st := DirStorage(dir)
+
+	require.NoError(t, st.WriteFile("config/a.cfg", []byte("a=1")))
+	data, err := os.ReadFile(filepath.Join(dir, "config", "a.cfg"))
+	require.NoError(t, err)
+	assert.Equal(t, "a=1", string(data))
+
+	data, err = fs.ReadFile(st, "./config/../config/a.cfg")
+	require.NoError(t, err, "redundant elements are cleaned")
+	assert.Equal(t, "a=1", string(data))
+
+	require.NoError(t, st.Remove("config/a.cfg"))
+	require.NoError(t, st.Remove("config/a.cfg"), "removing a missing file isn't an error")
+	_, err = fs.Stat(st, "config/a.cfg")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	for _, name := range []string{"../outside.txt", "/etc/passwd"} {
+		err := st.WriteFile(name, []byte("x"))
+		assert.ErrorIs(t, err, fs.ErrInvalid, name)
+	}
+	_, err = os.Stat(filepath.Join(filepath.Dir(dir), "outside.txt"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestFSContent(t *testing.T) {
+	st := NewMemStorage()
+	require.NoError(t, st.WriteFile("a.txt", []byte("hello")))
+
+	r, err := FSContent{FS: st, Name: "a.txt"}.Open()
+	require.NoError(t, err)
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestFileLocation(t *testing.T) {
+	dir := t.TempDir()
+	host, ok := fileLocation(hostStorage, filepath.Join(dir, "a.txt"))
+	require.True(t, ok)
+	inDir, ok := fileLocation(DirStorage(dir), "a.txt")
+	require.True(t, ok)
+	assert.Equal(t, host, inDir, "the same file on disk")
+
+	mem := NewMemStorage()
+	a, _ := fileLocation(mem, "a.txt")
+	b, _ := fileLocation(mem, "./a.txt")
+	other, _ := fileLocation(NewMemStorage(), "a.txt")
+	assert.True(t, a == b)
+	assert.False(t, a == other, "files in different storages differ")
+	assert.False(t, a == host)
+}
@@ -0,0 +1,316 @@
+package fileio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// WatchIndexFiles keeps the pack's index up to date as files in the pack folder change, until ctx
+// is done. Changes are collected until none have happened for debounce; then the index entries of
+// the changed files are updated with UpdateIndexFile (or removed, for deleted files), and
+// index.toml and pack.toml are rewritten atomically. Both are re-read before being rewritten, so
+// changes made to them meanwhile, by other packwiz commands or by hand, are kept. Files excluded by .packwizignore are left
+// alone, and a change to .packwizignore itself refreshes the whole index with RefreshIndexFiles,
+// as it can include or exclude any file.
+//
+// index should be up to date when watching starts, e.g. just refreshed. onUpdate, if non-nil, is
+// called after each rewrite with the index paths that changed and the files that couldn't be read.
+func WatchIndexFiles(ctx context.Context, pack *core.PackToml, index *core.IndexFS, debounce time.Duration, onUpdate func(changed []string, skipped []error)) error {
+	filter, err := newIndexFilter(hostStorage, index, pack.GetFilePath())
+	if err != nil {
+		return err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	w := &indexWatcher{pack: pack, index: index, filter: filter, watcher: watcher}
+	if _, err := w.watchDir(index.GetPackRoot()); err != nil {
+		return err
+	}
+
+	pending := make(map[string]bool)
+	var watchErrs []error
+	fullRefresh := false
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+
+	flush := func() error {
+		var changed []string
+		var skipped []error
+		var err error
+		if fullRefresh {
+			changed, skipped, err = w.refreshAll()
+		} else {
+			changed, skipped, err = w.update(pending)
+		}
+		if err != nil {
+			return err
+		}
+		skipped = append(watchErrs, skipped...)
+		pending = make(map[string]bool)
+		watchErrs = nil
+		fullRefresh = false
+
+		if len(changed) > 0 {
+			if err := w.write(changed); err != nil {
+				return err
+			}
+		}
+		if onUpdate != nil && (len(changed) > 0 || len(skipped) > 0) {
+			onUpdate(changed, skipped)
+		}
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Don't lose changes made just before stopping
+			if len(pending) > 0 || fullRefresh {
+				return flush()
+			}
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			if filter.isIgnoreFile(event.Name) {
+				fullRefresh = true
+			} else {
+				pending[event.Name] = true
+			}
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			// Events may have been missed (e.g. fsnotify.ErrEventOverflow), so the whole index has
+			// to be refreshed
+			watchErrs = append(watchErrs, fmt.Errorf("error watching pack files: %w", err))
+			fullRefresh = true
+			timer.Reset(debounce)
+		case <-timer.C:
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+type indexWatcher struct {
+	pack    *core.PackToml
+	index   *core.IndexFS
+	filter  *indexFilter
+	watcher *fsnotify.Watcher
+}
+
+// watchDir watches dir and every folder in it that isn't excluded from the index (fsnotify
+// doesn't watch recursively), returning the files in them
+func (w *indexWatcher) watchDir(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, info os.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			// Reported when the file is updated
+			return nil
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			return nil
+		}
+		if path != w.index.GetPackRoot() {
+			excluded, err := w.filter.excludesDir(path)
+			if err != nil {
+				return err
+			}
+			if excluded {
+				return fs.SkipDir
+			}
+		}
+		return w.watcher.Add(path)
+	})
+	return files, err
+}
+
+// update updates the index entries of the changed paths, returning the index paths that changed
+func (w *indexWatcher) update(pending map[string]bool) (changed []string, skipped []error, err error) {
+	paths := make([]string, 0, len(pending))
+	for path := range pending {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	updated := make(map[string]bool)
+	for _, path := range paths {
+		excluded, err := w.filter.excludes(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if excluded {
+			continue
+		}
+
+		stat, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			// Deleted or renamed away: remove the file, or everything in the folder
+			changed = append(changed, w.removeEntries(path)...)
+			continue
+		} else if err != nil {
+			skipped = append(skipped, fmt.Errorf("failed to read %s: %w", path, err))
+			continue
+		}
+
+		files := []string{path}
+		if stat.IsDir() {
+			// New folders have to be watched too; files may already have been created in them
+			files, err = w.watchDir(path)
+			if err != nil {
+				skipped = append(skipped, fmt.Errorf("failed to watch %s: %w", path, err))
+				continue
+			}
+		}
+		for _, file := range files {
+			if updated[file] {
+				continue
+			}
+			updated[file] = true
+			if excluded, err := w.filter.excludes(file); err != nil || excluded {
+				continue
+			}
+			if err := UpdateIndexFile(w.index, file); err != nil {
+				skipped = append(skipped, fmt.Errorf("failed to hash %s: %w", file, err))
+				continue
+			}
+			if relPath, err := w.index.RelIndexPath(file); err == nil {
+				changed = append(changed, relPath)
+			}
+		}
+	}
+	return changed, skipped, nil
+}
+
+// removeEntries removes the index entry of path, and those of any files under it if it was a
+// folder, returning the removed index paths
+func (w *indexWatcher) removeEntries(path string) []string {
+	relPath, err := w.index.RelIndexPath(path)
+	if err != nil {
+		return nil
+	}
+	var removed []string
+	for p := range w.index.Files {
+		if p == relPath || strings.HasPrefix(p, relPath+"/") {
+			delete(w.index.Files, p)
+			removed = append(removed, p)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// refreshAll refreshes the whole index after .packwizignore has changed or events were missed,
+// returning the index paths that changed
+func (w *indexWatcher) refreshAll() (changed []string, skipped []error, err error) {
+	if err := w.filter.reloadIgnore(); err != nil {
+		// Keep watching with the previous rules until .packwizignore can be read
+		return nil, []error{err}, nil
+	}
+	// Folders that are no longer ignored have to be watched
+	if _, err := w.watchDir(w.index.GetPackRoot()); err != nil {
+		return nil, nil, err
+	}
+
+	previous, err := w.index.ToWritable()
+	if err != nil {
+		return nil, nil, err
+	}
+	before, err := core.NewIndexFromTomlRepr(previous)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	skipped, err = RefreshIndexFiles(w.index, w.pack.GetFilePath(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	diff := before.DiffFiles(*w.index)
+	changed = append(append(append(changed, diff.Added...), diff.Removed...), diff.Rehashed...)
+	sort.Strings(changed)
+	return changed, skipped, nil
+}
+
+// write atomically rewrites index.toml with the entries of the changed index paths, then
+// pack.toml with the new index hash. Both files are re-read first, and the watcher continues from
+// what was written, so other changes to them since the last write aren't reverted.
+func (w *indexWatcher) write(changed []string) error {
+	pack, err := readPackToml(w.pack.GetFilePath())
+	if err != nil {
+		return err
+	}
+	index, err := LoadIndex(packIndexPath(&pack))
+	if err != nil {
+		return err
+	}
+
+	for _, p := range changed {
+		entries := w.index.GetEntries(p)
+		if len(entries) == 0 {
+			delete(index.Files, p)
+			continue
+		}
+		// Only the hash is taken from the watcher's entry; aliases and other fields are kept
+		entry := entries[0]
+		format := entry.HashFormat
+		if format == "" {
+			format = w.index.DefaultModHashFormat
+		}
+		if err := index.UpdateFileHashGiven(index.ResolveIndexPath(p), format, entry.Hash, entry.MetaFile); err != nil {
+			return err
+		}
+	}
+
+	if err := WriteIndex(&index); err != nil {
+		return err
+	}
+	pack.RefreshIndexHash(index)
+	if _, err := writeMarshalledAtomic(&pack); err != nil {
+		return err
+	}
+	*w.index = index
+	*w.pack = pack
+	return nil
+}
+
+// readPackToml reads pack.toml without validating it, as LoadPackFile does when loading a pack
+func readPackToml(packPath string) (core.PackToml, error) {
+	var pack core.PackToml
+	raw, err := os.ReadFile(packPath)
+	if err != nil {
+		return core.PackToml{}, err
+	}
+	if err := toml.Unmarshal(raw, &pack); err != nil {
+		return core.PackToml{}, fmt.Errorf("failed to read %s: %w", packPath, err)
+	}
+	pack.SetFilePath(packPath)
+	return pack, nil
+}
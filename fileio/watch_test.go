@@ -0,0 +1,107 @@
+package fileio
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func TestWatchIndexFiles(t *testing.T) {
+	resetViper(t)
+	dir := t.TempDir()
+	require.NoError(t, WriteAll(testPack(t), dir))
+	packPath := filepath.Join(dir, "pack.toml")
+
+	packToml, err := LoadPackFile(packPath)
+	require.NoError(t, err)
+	index, err := LoadPackIndexFile(&packToml)
+	require.NoError(t, err)
+	_, err = RefreshIndexFiles(&index, packPath, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan []string, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- WatchIndexFiles(ctx, &packToml, &index, 20*time.Millisecond, func(changed []string, skipped []error) {
+			updates <- changed
+		})
+	}()
+	// Give the watcher time to start watching
+	time.Sleep(100 * time.Millisecond)
+
+	waitForUpdate := func() []string {
+		t.Helper()
+		select {
+		case changed := <-updates:
+			return changed
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for the index to update")
+			return nil
+		}
+	}
+	checkUpToDate := func() {
+		t.Helper()
+		reloaded, err := LoadPackFile(packPath)
+		require.NoError(t, err)
+		result, _, err := CheckIndexFiles(&reloaded, packPath)
+		require.NoError(t, err)
+		assert.True(t, result.UpToDate(), "index on disk is up to date: %+v", result)
+	}
+
+	// Files in new folders are added
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "config", "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "sub", "a.cfg"), []byte("a=1"), 0644))
+	assert.Contains(t, waitForUpdate(), "config/sub/a.cfg")
+	assert.Contains(t, index.Files, "config/sub/a.cfg")
+	checkUpToDate()
+
+	// Ignored files are left alone, and changing .packwizignore refreshes the whole index
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".packwizignore"), []byte("config/sub/\n"), 0644))
+	assert.Equal(t, []string{"config/sub/a.cfg"}, waitForUpdate())
+	assert.NotContains(t, index.Files, "config/sub/a.cfg")
+	checkUpToDate()
+	require.NoError(t, os.Remove(filepath.Join(dir, ".packwizignore")))
+	assert.Equal(t, []string{"config/sub/a.cfg"}, waitForUpdate())
+
+	// Changes made to pack.toml and index.toml while watching are kept
+	packData, err := os.ReadFile(packPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(packPath, append(packData, []byte("\n[options]\ndatapack-folder = \"datapacks\"\n")...), 0644))
+	onDisk, err := LoadIndex(filepath.Join(dir, "index.toml"))
+	require.NoError(t, err)
+	repr, err := onDisk.ToWritable()
+	require.NoError(t, err)
+	for i := range repr.Files {
+		if repr.Files[i].File == "mods/balm.pw.toml" {
+			repr.Files[i].Preserve = true
+		}
+	}
+	onDisk, err = core.NewIndexFromTomlRepr(repr)
+	require.NoError(t, err)
+	require.NoError(t, WriteIndex(&onDisk))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "sub", "b.cfg"), []byte("b=1"), 0644))
+	assert.Equal(t, []string{"config/sub/b.cfg"}, waitForUpdate())
+	reloaded, err := LoadAll(packPath)
+	require.NoError(t, err)
+	assert.Equal(t, "datapacks", reloaded.GetDatapackFolder())
+	assert.True(t, reloaded.Mods["balm"].GetCopies()[0].Preserve)
+	assert.Contains(t, index.Files, "config/sub/b.cfg")
+	checkUpToDate()
+
+	// Deleted folders remove everything in them
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "config")))
+	assert.Contains(t, waitForUpdate(), "config/sub/a.cfg")
+	assert.NotContains(t, index.Files, "config/sub/a.cfg")
+	checkUpToDate()
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -51,3 +51,13 @@ func writeMarshalled(writable Writable) (core.MarshalResult, error) {
 
 	return result, nil
 }
+
+// writeMarshalledAtomic is writeMarshalled, but writes the file atomically (see
+// writeFileAtomic), for files that other processes may be reading while they are rewritten
+func writeMarshalledAtomic(writable Writable) (core.MarshalResult, error) {
+	result, err := writable.Marshal()
+	if err != nil {
+		return result, err
+	}
+	return result, writeFileAtomic(writable.GetFilePath(), result.Value)
+}
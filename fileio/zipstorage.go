@@ -0,0 +1,79 @@
+package fileio
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ZipStorage is a Storage for a pack kept in a zip archive. The archive is read into memory by
+// ReadZipStorage, changes are made in memory, and Save writes out a new archive.
+type ZipStorage struct {
+	*MemStorage
+}
+
+// NewZipStorage returns an empty ZipStorage, for creating a new archive
+func NewZipStorage() *ZipStorage {
+	return &ZipStorage{MemStorage: NewMemStorage()}
+}
+
+// ReadZipStorage reads the zip archive in r, of size bytes, into a ZipStorage
+func ReadZipStorage(r io.ReaderAt, size int64) (*ZipStorage, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	z := NewZipStorage()
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		data, err := readZipEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from zip: %w", entry.Name, err)
+		}
+		// Keep modification times, so unchanged files can reuse cached hashes
+		if err := z.writeFile(entry.Name, data, entry.Modified); err != nil {
+			return nil, err
+		}
+	}
+	return z, nil
+}
+
+func readZipEntry(entry *zip.File) ([]byte, error) {
+	r, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// Save writes the files in the storage to w as a zip archive. Packwiz's local state (see
+// LocalCacheFolder) isn't part of the pack, so it is left out.
+func (z *ZipStorage) Save(w io.Writer) error {
+	archive := zip.NewWriter(w)
+	for _, name := range z.Files() {
+		if name == LocalCacheFolder || strings.HasPrefix(name, LocalCacheFolder+"/") {
+			continue
+		}
+		z.mu.RLock()
+		file, ok := z.files[name]
+		z.mu.RUnlock()
+		if !ok {
+			continue
+		}
+
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: file.modTime}
+		f, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(file.data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
@@ -0,0 +1,59 @@
+package fileio
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZipStorage_RoundTrip(t *testing.T) {
+	resetViper(t)
+	st := NewZipStorage()
+	require.NoError(t, WriteAllTo(st, testPack(t), "."))
+	require.NoError(t, st.WriteFile(LocalCacheFolder+"/stat-cache.json", []byte("{}")))
+
+	var buf bytes.Buffer
+	require.NoError(t, st.Save(&buf))
+
+	read, err := ReadZipStorage(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"index.toml", "mods/balm.pw.toml", "pack.toml"}, read.Files(), "local state is left out")
+
+	loaded, err := LoadAllFrom(read, "pack.toml")
+	require.NoError(t, err)
+	assert.Contains(t, loaded.Mods, "balm")
+
+	packToml, err := LoadPackFileFrom(read, "pack.toml")
+	require.NoError(t, err)
+	result, _, err := CheckIndexFilesIn(read, &packToml, "pack.toml")
+	require.NoError(t, err)
+	assert.True(t, result.UpToDate(), "%+v", result)
+}
+
+func TestReadZipStorage(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	_, err := w.Create("config/")
+	require.NoError(t, err)
+	f, err := w.CreateHeader(&zip.FileHeader{Name: "config/a.cfg", Modified: modified})
+	require.NoError(t, err)
+	_, err = f.Write([]byte("a=1"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	st, err := ReadZipStorage(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"config/a.cfg"}, st.Files(), "folder entries aren't files")
+	info, err := fs.Stat(st, "config/a.cfg")
+	require.NoError(t, err)
+	assert.True(t, modified.Equal(info.ModTime()), "modification times are kept")
+
+	_, err = ReadZipStorage(bytes.NewReader([]byte("not a zip")), 9)
+	assert.Error(t, err)
+}
@@ -0,0 +1,139 @@
+package cmdadd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/leocov-dev/packwiz-nxt/cmd"
+	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+	"github.com/leocov-dev/packwiz-nxt/internal/commands/cmdurl"
+	"github.com/leocov-dev/packwiz-nxt/internal/shared"
+	"github.com/leocov-dev/packwiz-nxt/sources"
+)
+
+var fileFlag string
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add [URL|slug|search]...",
+	Short: "Add projects from CurseForge, Modrinth, GitHub, Codeberg or Hangar URLs, slugs, direct download links or a search",
+	Long: `Add one or more projects to the modpack, detecting the provider from each argument:
+
+  CurseForge project URLs      https://www.curseforge.com/minecraft/mc-mods/jei
+  Modrinth project URLs/slugs  https://modrinth.com/mod/sodium, sodium
+  GitHub repository URLs       https://github.com/owner/repo, owner/repo
+  Codeberg repository URLs     https://codeberg.org/owner/repo
+  Hangar project URLs          https://hangar.papermc.io/owner/project
+  Direct download links        https://example.com/some-mod.jar
+
+Anything else is used as a search term across all providers; quote search terms
+containing spaces so they are treated as a single argument.`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		shared.LoadPlugins()
+		entries := args
+		if fileFlag != "" {
+			fileEntries, err := readEntries(fileFlag)
+			if err != nil {
+				shared.Exitf("Failed to read %s: %v\n", fileFlag, err)
+			}
+			entries = append(entries, fileEntries...)
+		}
+
+		if len(entries) == 0 {
+			shared.Exitln("You must specify at least one project; by passing URLs, slugs or search terms, or with --file.")
+		}
+
+		packFile, packDir, err := shared.GetPackPaths()
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		fmt.Printf("Loading modpack %s\n", packFile)
+		pack, err := shared.LoadAll(packFile)
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		added := 0
+		var failed []string
+		for _, entry := range entries {
+			if err := addEntry(pack, entry); err != nil {
+				fmt.Printf("Failed to add %s: %v\n", entry, err)
+				failed = append(failed, entry)
+				continue
+			}
+			added++
+		}
+
+		if added > 0 {
+			if err := fileio.WriteAll(*pack, packDir); err != nil {
+				shared.Exitf("Failed to write pack file: %s\n", err)
+			}
+			fmt.Printf("Pack file written to %s\n", viper.GetString("pack-file"))
+		}
+
+		if len(failed) > 0 {
+			shared.Exitf("Failed to add %d of %d projects: %s\n", len(failed), len(entries), strings.Join(failed, ", "))
+		}
+	},
+}
+
+func init() {
+	cmd.Add(addCmd)
+
+	addCmd.Flags().StringVarP(&fileFlag, "file", "f", "", "A file listing projects to add, one per line (blank lines and lines starting with # are ignored)")
+}
+
+// readEntries reads one project identifier per line from path, skipping blank lines and comments
+func readEntries(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	return entries, scanner.Err()
+}
+
+func addEntry(pack *core.Pack, entry string) error {
+	target, err := sources.DetectProvider(entry)
+	if err != nil {
+		return err
+	}
+
+	req := shared.NewSourceRequest(*pack, map[string]string{
+		sources.MrOptionDatapackFolder: viper.GetString("datapack-folder"),
+	})
+
+	switch target.Provider {
+	case sources.ProviderURL:
+		return cmdurl.AddURL(pack, target.Input)
+	case sources.ProviderSearch:
+		return shared.SearchAndAdd(pack, core.DefaultRegistry.Sources(), entry, req)
+	}
+
+	err = shared.AddFromSource(pack, shared.GetSource(string(target.Provider)), entry, req)
+	if errors.Is(err, core.ErrProjectNotFound) && target.Provider == sources.ProviderModrinth && target.FromBareSlug {
+		// Not a Modrinth slug after all; treat it as a search term instead
+		fmt.Printf("%s is not a Modrinth project, searching instead...\n", entry)
+		return shared.SearchAndAdd(pack, core.DefaultRegistry.Sources(), entry, req)
+	}
+	return err
+}
@@ -23,7 +23,7 @@ var detectCmd = &cobra.Command{
 			shared.Exitln(err)
 		}
 
-		pack, err := fileio.LoadAll(packFile)
+		pack, err := shared.LoadAll(packFile)
 		if err != nil {
 			shared.Exitln(err)
 		}
@@ -16,8 +16,8 @@ import (
 	"github.com/leocov-dev/packwiz-nxt/sources"
 )
 
-var modListPlainItemTemplate = template.Must(template.New("plainItem").Parse("<li>{{.Name}}</li>\r\n"))
-var modListLinkItemTemplate = template.Must(template.New("linkItem").Parse("<li><a href=\"https://www.curseforge.com/projects/{{.ProjectID}}\">{{.Name}}</a></li>\r\n"))
+var modListPlainItemTemplate = template.Must(template.New("plainItem").Parse("<li>{{.Name}}{{with .Version}} ({{.}}){{end}}</li>\r\n"))
+var modListLinkItemTemplate = template.Must(template.New("linkItem").Parse("<li><a href=\"https://www.curseforge.com/projects/{{.ProjectID}}\">{{.Name}}</a>{{with .Version}} ({{.}}){{end}}</li>\r\n"))
 
 func init() {
 	curseforgeCmd.AddCommand(exportCmd)
@@ -34,6 +34,7 @@ var exportCmd = &cobra.Command{
 	Short: "Export the current modpack into a .zip for curseforge",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		shared.LoadPlugins()
 		side := core.ModSide(viper.GetString("curseforge.export.side"))
 		if side != core.UniversalSide && side != core.ServerSide && side != core.ClientSide {
 			shared.Exitf("Invalid side %q, must be one of client, server, or both (default)\n", side)
@@ -45,7 +46,7 @@ var exportCmd = &cobra.Command{
 			shared.Exitln(err)
 		}
 
-		pack, err := fileio.LoadAll(packFile)
+		pack, err := shared.LoadAll(packFile)
 		if err != nil {
 			shared.Exitln(err)
 		}
@@ -63,6 +64,11 @@ var exportCmd = &cobra.Command{
 		}
 		mods = mods[:i]
 
+		mods, skipped := sources.FilterExportable(mods)
+		for _, err := range skipped {
+			fmt.Printf("Warning: %v\n", err)
+		}
+
 		var exportData sources.CfExportData
 		exportDataUnparsed, ok := pack.Export["curseforge"]
 		if ok {
@@ -80,10 +86,10 @@ var exportCmd = &cobra.Command{
 		cfFileRefs := make([]packinterop.AddonFileReference, 0, len(mods))
 		nonCfMods := make([]*core.Mod, 0)
 		for _, mod := range mods {
-			var p sources.CfUpdateData
-			err = mod.DecodeNamedModSourceData("curseforge", &p)
-			// If the mod has curseforge metadata, add it to cfFileRefs
-			if err == nil {
+			// If the mod has curseforge metadata (and isn't a datapack), add it to cfFileRefs
+			if !sources.CfExportAsOverride(pack, mod) {
+				var p sources.CfUpdateData
+				_ = mod.DecodeNamedModSourceData("curseforge", &p)
 				cfFileRefs = append(cfFileRefs, packinterop.AddonFileReference{
 					ProjectID:        p.ProjectID,
 					FileID:           p.FileID,
@@ -170,8 +176,9 @@ func createModList(zw *zip.Writer, mods []*core.Mod) error {
 		}
 		if err = modListLinkItemTemplate.Execute(w, struct {
 			Name      string
+			Version   string
 			ProjectID uint32
-		}{mod.Name, project.ProjectID}); err != nil {
+		}{mod.Name, mod.Version, project.ProjectID}); err != nil {
 			return err
 		}
 	}
@@ -5,12 +5,10 @@ import (
 	"fmt"
 	"github.com/leocov-dev/packwiz-nxt/core"
 	"github.com/leocov-dev/packwiz-nxt/sources"
+	"strconv"
 	"strings"
 
-	"github.com/sahilm/fuzzy"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
-	"gopkg.in/dixonwille/wmenu.v4"
 
 	"github.com/leocov-dev/packwiz-nxt/fileio"
 	"github.com/leocov-dev/packwiz-nxt/internal/shared"
@@ -43,241 +41,62 @@ var installCmd = &cobra.Command{
 			shared.Exitln(err)
 		}
 
-		pack, err := fileio.LoadAll(packFile)
+		pack, err := shared.LoadAll(packFile)
 		if err != nil {
 			shared.Exitln(err)
 		}
 
 		// ---
-		mcVersions, err := pack.GetSupportedMCVersions()
-		if err != nil {
-			shared.Exitln(err)
-		}
-		primaryMCVersion, err := pack.GetMCVersion()
-		if err != nil {
-			shared.Exitln(err)
+		if (len(args) == 0 || len(args[0]) == 0) && addonIDFlag == 0 {
+			shared.Exitln("You must specify a project; with the ID flags, or by passing a URL, slug or search term directly.")
 		}
 
-		// ---
-		category := categoryFlag
-		var modID, fileID uint32
-		var slug string
-
-		// If mod/file IDs are provided in command line, use those
-		if fileIDFlag != 0 {
-			fileID = fileIDFlag
+		options := map[string]string{
+			sources.CfOptionCategory: categoryFlag,
 		}
-		if addonIDFlag != 0 {
-			modID = addonIDFlag
+		if fileIDFlag != 0 {
+			options[sources.CfOptionFileID] = strconv.FormatUint(uint64(fileIDFlag), 10)
 		}
 
-		if (len(args) == 0 || len(args[0]) == 0) && modID == 0 {
-			shared.Exitln("You must specify a project; with the ID flags, or by passing a URL, slug or search term directly.")
-		}
-		if modID == 0 && len(args) == 1 {
-			parsedCategory, parsedSlug, parsedFileID, err := sources.CurseforgeParseUrl(args[0])
+		src := shared.GetSource("curseforge")
+		req := shared.NewSourceRequest(*pack, options)
+
+		// If the mod ID is provided in command line, use that
+		var identifier string
+		var isBareSlug bool
+		if addonIDFlag != 0 {
+			identifier = strconv.FormatUint(uint64(addonIDFlag), 10)
+		} else if len(args) == 1 {
+			_, parsedSlug, _, err := sources.CurseforgeParseUrl(args[0])
 			if err != nil {
 				shared.Exitf("Failed to parse URL: %v\n", err)
 			}
-
-			if parsedCategory != "" {
-				category = parsedCategory
-			}
 			if parsedSlug != "" {
-				slug = parsedSlug
-			}
-			if parsedFileID != 0 {
-				fileID = parsedFileID
+				identifier = args[0]
+				isBareSlug = parsedSlug == args[0]
 			}
 		}
 
-		modInfoObtained := false
-		var modInfoData sources.CfModInfo
-
-		if modID == 0 {
-			var cancelled bool
-			if slug == "" {
-				searchTerm := strings.Join(args, " ")
-				cancelled, modInfoData = SearchCurseforgeInternal(searchTerm, false, category, mcVersions, sources.CfGetSearchLoaderType(*pack))
-			} else {
-				cancelled, modInfoData = SearchCurseforgeInternal(slug, true, category, mcVersions, sources.CfGetSearchLoaderType(*pack))
-			}
-			if cancelled {
-				return
+		if identifier != "" {
+			err = shared.AddFromSource(pack, src, identifier, req)
+			if errors.Is(err, core.ErrProjectNotFound) && isBareSlug {
+				// Not a slug after all, try to search for it instead
+				err = shared.SearchAndAdd(pack, []core.Source{src}, args[0], req)
 			}
-			modID = modInfoData.ID
-			modInfoObtained = true
-		}
-
-		if modID == 0 {
-			shared.Exitln("No projects found!")
+		} else {
+			err = shared.SearchAndAdd(pack, []core.Source{src}, strings.Join(args, " "), req)
 		}
-
-		if !modInfoObtained {
-			modInfoData, err = sources.GetCurseforgeClient().GetModInfo(modID)
-			if err != nil {
-				shared.Exitf("Failed to get project info: %v\n", err)
-			}
+		if errors.Is(err, shared.ErrSelectionCancelled) {
+			fmt.Println("Cancelled!")
+			return
 		}
-
-		fileInfoData, err := sources.GetLatestFile(modInfoData, mcVersions, fileID, pack.GetCompatibleLoaders())
 		if err != nil {
-			shared.Exitf("Failed to get file for project: %v\n", err)
-		}
-
-		var missingDependencies []*core.Mod
-		if len(fileInfoData.Dependencies) > 0 {
-
-			missingDependencies, err = sources.CurseforgeFindMissingDependencies(*pack, fileInfoData, primaryMCVersion)
-			if err != nil {
-				shared.Exitln(err)
-			}
-
-			if len(missingDependencies) > 0 {
-				fmt.Println("Dependencies found:")
-				for _, v := range missingDependencies {
-					fmt.Println(v.Slug)
-				}
-
-				if !shared.PromptYesNo("Would you like to add them? [Y/n]: ") {
-					// if NO is chosen then we'll nil the slice to prevent installing
-					missingDependencies = nil
-				}
-			}
-		}
-
-		mainMod, err := sources.CurseforgeNewMod(modInfoData, fileInfoData, false)
-		if err != nil {
-			shared.Exitln(err)
-		}
-
-		newMods := append(missingDependencies, mainMod)
-
-		if len(newMods) == 0 {
-			shared.Exitln("no mods were installed")
-		}
-
-		for _, mod := range newMods {
-			pack.SetMod(mod)
+			shared.Exitf("Failed to add project: %v\n", err)
 		}
 
 		err = fileio.WriteAll(*pack, packDir)
 		if err != nil {
 			shared.Exitln(err)
 		}
-
-		fmt.Printf("Project \"%s\" successfully added! (%s)\n", modInfoData.Name, fileInfoData.FileName)
 	},
 }
-
-// Used to implement interface for fuzzy matching
-type ModResultsList []sources.CfModInfo
-
-func (r ModResultsList) String(i int) string {
-	return r[i].Name
-}
-
-func (r ModResultsList) Len() int {
-	return len(r)
-}
-
-func SearchCurseforgeInternal(
-	searchTerm string,
-	isSlug bool,
-	category string,
-	mcVersions []string,
-	searchLoaderType sources.ModloaderType,
-) (bool, sources.CfModInfo) {
-	if isSlug {
-		fmt.Println("Looking up CurseForge slug...")
-	} else {
-		fmt.Println("Searching CurseForge...")
-	}
-
-	var categoryID, classID uint32
-
-	if category == "mc-mods" {
-		classID = 6
-	}
-
-	if classID == 0 && category != "" {
-		var err error
-		categoryID, classID, err = sources.CurseforgeCategoryLookup(category)
-		if err != nil {
-			shared.Exitln(err)
-		}
-	}
-
-	// If there are more than one acceptable version, we shouldn't filter by game version at all (as we can't filter by multiple)
-	filterGameVersion := ""
-	if len(mcVersions) == 1 {
-		filterGameVersion = sources.GetCurseforgeVersion(mcVersions[0])
-	}
-	var search, slug string
-	if isSlug {
-		slug = searchTerm
-	} else {
-		search = searchTerm
-	}
-	results, err := sources.GetCurseforgeClient().GetSearch(search, slug, classID, categoryID, filterGameVersion, searchLoaderType)
-	if err != nil {
-		shared.Exitf("Failed to search for project: %v\n", err)
-	}
-	if len(results) == 0 {
-		shared.Exitln("No projects found!")
-		return false, sources.CfModInfo{}
-	} else if len(results) == 1 {
-		return false, results[0]
-	} else {
-		// Fuzzy search on results list
-		fuzzySearchResults := fuzzy.FindFrom(searchTerm, ModResultsList(results))
-
-		if viper.GetBool("non-interactive") {
-			if len(fuzzySearchResults) > 0 {
-				return false, results[fuzzySearchResults[0].Index]
-			}
-			return false, results[0]
-		}
-
-		menu := wmenu.NewMenu("Choose a number:")
-
-		menu.Option("Cancel", nil, false, nil)
-		if len(fuzzySearchResults) == 0 {
-			for i, v := range results {
-				menu.Option(v.Name+" ("+v.Summary+")", v, i == 0, nil)
-			}
-		} else {
-			for i, v := range fuzzySearchResults {
-				menu.Option(results[v.Index].Name+" ("+results[v.Index].Summary+")", results[v.Index], i == 0, nil)
-			}
-		}
-
-		var modInfoData sources.CfModInfo
-		var cancelled bool
-		menu.Action(func(menuRes []wmenu.Opt) error {
-			if len(menuRes) != 1 || menuRes[0].Value == nil {
-				fmt.Println("Cancelled!")
-				cancelled = true
-				return nil
-			}
-
-			// Must assign into the outer modInfoData with "=", not ":=" - the latter would
-			// shadow it with a new variable scoped to this closure.
-			var ok bool
-			modInfoData, ok = menuRes[0].Value.(sources.CfModInfo)
-			if !ok {
-				return errors.New("error converting interface from wmenu")
-			}
-			return nil
-		})
-		err = menu.Run()
-		if err != nil {
-			shared.Exitln(err)
-		}
-
-		if cancelled {
-			return true, sources.CfModInfo{}
-		}
-		return false, modInfoData
-	}
-}
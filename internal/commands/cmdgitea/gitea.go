@@ -0,0 +1,16 @@
+package cmdgitea
+
+import (
+	"github.com/leocov-dev/packwiz-nxt/cmd"
+	"github.com/spf13/cobra"
+)
+
+var giteaCmd = &cobra.Command{
+	Use:     "gitea",
+	Aliases: []string{"forgejo", "codeberg"},
+	Short:   "Manage projects released on Gitea or Forgejo servers, such as Codeberg",
+}
+
+func init() {
+	cmd.Add(giteaCmd)
+}
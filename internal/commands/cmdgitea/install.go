@@ -0,0 +1,61 @@
+package cmdgitea
+
+import (
+	"fmt"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+	"github.com/leocov-dev/packwiz-nxt/internal/shared"
+	"github.com/leocov-dev/packwiz-nxt/sources"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var hostFlag string
+var branchFlag string
+var regexFlag string
+
+func init() {
+	giteaCmd.AddCommand(installCmd)
+
+	installCmd.Flags().StringVar(&hostFlag, "host", "codeberg.org", "The Gitea/Forgejo server to use for owner/repo slugs")
+	installCmd.Flags().StringVar(&branchFlag, "branch", "", "The repository branch to retrieve releases for")
+	installCmd.Flags().StringVar(&regexFlag, "regex", "", "The regular expression to match releases against")
+}
+
+// installCmd represents the install command
+var installCmd = &cobra.Command{
+	Use:     "add [URL|slug]",
+	Short:   "Add a project from a Gitea/Forgejo repository URL or slug",
+	Aliases: []string{"install", "get"},
+	Args:    cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 || len(args[0]) == 0 {
+			shared.Exitln("You must specify a repository URL or owner/repo slug.")
+		}
+
+		packFile, packDir, err := shared.GetPackPaths()
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		pack, err := shared.LoadAll(packFile)
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		req := shared.NewSourceRequest(*pack, map[string]string{
+			sources.GiteaOptionHost: hostFlag,
+			sources.GhOptionBranch:  branchFlag,
+			sources.GhOptionRegex:   regexFlag,
+		})
+		err = shared.AddFromSource(pack, shared.GetSource("gitea"), args[0], req)
+		if err != nil {
+			shared.Exitf("Failed to add project: %s\n", err)
+		}
+
+		err = fileio.WriteAll(*pack, packDir)
+		if err != nil {
+			shared.Exitf("Failed to write pack file: %s\n", err)
+		}
+		fmt.Printf("Pack file written to %s\n", viper.GetString("pack-file"))
+	},
+}
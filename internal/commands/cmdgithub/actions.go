@@ -0,0 +1,74 @@
+package cmdgithub
+
+import (
+	"fmt"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+	"github.com/leocov-dev/packwiz-nxt/internal/shared"
+	"github.com/leocov-dev/packwiz-nxt/sources"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var actionsWorkflowFlag string
+var actionsBranchFlag string
+var actionsArtifactFlag string
+var actionsRegexFlag string
+
+func init() {
+	githubCmd.AddCommand(actionsCmd)
+	actionsCmd.AddCommand(actionsInstallCmd)
+
+	actionsInstallCmd.Flags().StringVar(&actionsWorkflowFlag, "workflow", "", "The workflow file name (e.g. build.yml) or ID to track")
+	actionsInstallCmd.Flags().StringVar(&actionsBranchFlag, "branch", "", "The branch to track workflow runs on")
+	actionsInstallCmd.Flags().StringVar(&actionsArtifactFlag, "artifact", "", "The regular expression to match artifact names against, if runs upload more than one")
+	actionsInstallCmd.Flags().StringVar(&actionsRegexFlag, "regex", "", "The regular expression to match the file inside the artifact against")
+	_ = actionsInstallCmd.MarkFlagRequired("workflow")
+}
+
+var actionsCmd = &cobra.Command{
+	Use:   "actions",
+	Short: "Manage development builds uploaded as GitHub Actions artifacts",
+}
+
+var actionsInstallCmd = &cobra.Command{
+	Use:   "add [URL|slug]",
+	Short: "Add the latest successful build of a GitHub Actions workflow",
+	Long: `Add the latest successful build of a GitHub Actions workflow, and update it to newer runs.
+
+Downloading artifacts requires a GitHub token, even for public repositories. Artifacts are
+development builds, and are left out of exported Modrinth and CurseForge packs.`,
+	Aliases: []string{"install", "get"},
+	Args:    cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 || len(args[0]) == 0 {
+			shared.Exitln("You must specify a GitHub repository URL.")
+		}
+
+		packFile, packDir, err := shared.GetPackPaths()
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		pack, err := shared.LoadAll(packFile)
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		req := shared.NewSourceRequest(*pack, map[string]string{
+			sources.GhaOptionWorkflow: actionsWorkflowFlag,
+			sources.GhOptionBranch:    actionsBranchFlag,
+			sources.GhaOptionArtifact: actionsArtifactFlag,
+			sources.GhOptionRegex:     actionsRegexFlag,
+		})
+		err = shared.AddFromSource(pack, shared.GetSource("github-actions"), args[0], req)
+		if err != nil {
+			shared.Exitf("Failed to add project: %s\n", err)
+		}
+
+		err = fileio.WriteAll(*pack, packDir)
+		if err != nil {
+			shared.Exitf("Failed to write pack file: %s\n", err)
+		}
+		fmt.Printf("Pack file written to %s\n", viper.GetString("pack-file"))
+	},
+}
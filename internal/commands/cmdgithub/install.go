@@ -2,23 +2,32 @@ package cmdgithub
 
 import (
 	"fmt"
-	"github.com/leocov-dev/packwiz-nxt/core"
 	"github.com/leocov-dev/packwiz-nxt/fileio"
 	"github.com/leocov-dev/packwiz-nxt/internal/shared"
 	"github.com/leocov-dev/packwiz-nxt/sources"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"path/filepath"
+	"strconv"
 )
 
 var branchFlag string
 var regexFlag string
+var prereleasesFlag bool
+var draftsFlag bool
+var tagConstraintFlag string
+var tagRegexFlag string
+var strategyFlag string
 
 func init() {
 	githubCmd.AddCommand(installCmd)
 
 	installCmd.Flags().StringVar(&branchFlag, "branch", "", "The GitHub repository branch to retrieve releases for")
 	installCmd.Flags().StringVar(&regexFlag, "regex", "", "The regular expression to match releases against")
+	installCmd.Flags().BoolVar(&prereleasesFlag, "prereleases", false, "Include prereleases when installing and updating")
+	installCmd.Flags().BoolVar(&draftsFlag, "drafts", false, "Include draft releases when installing and updating (requires push access)")
+	installCmd.Flags().StringVar(&tagConstraintFlag, "tag-constraint", "", "Restrict release tags by version, e.g. \">=2.0,<3\" (a leading \"v\" is ignored)")
+	installCmd.Flags().StringVar(&tagRegexFlag, "tag-regex", "", "The regular expression release tags must match")
+	installCmd.Flags().StringVar(&strategyFlag, "strategy", "", "How to choose the latest release: \""+sources.GhStrategyLatest+"\" (most recently published, the default) or \""+sources.GhStrategySemver+"\" (highest tag version)")
 }
 
 // installCmd represents the install command
@@ -28,51 +37,42 @@ var installCmd = &cobra.Command{
 	Aliases: []string{"install", "get"},
 	Args:    cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		pack, err := fileio.LoadPackFile(viper.GetString("pack-file"))
-		if err != nil {
-			shared.Exitln(err)
-		}
-
 		if len(args) == 0 || len(args[0]) == 0 {
 			shared.Exitln("You must specify a GitHub repository URL.")
 		}
 
-		modType := viper.GetString("meta-folder")
-		if modType == "" {
-			modType = "mods"
+		packFile, packDir, err := shared.GetPackPaths()
+		if err != nil {
+			shared.Exitln(err)
 		}
 
-		slugOrUrl := args[0]
-
-		mod, err := sources.GitHubNewMod(
-			args[0],
-			branchFlag,
-			regexFlag,
-			modType,
-		)
+		pack, err := shared.LoadAll(packFile)
 		if err != nil {
-			shared.Exitf("Failed to add project: %s\n", err)
+			shared.Exitln(err)
 		}
 
-		modMeta := mod.ToModMeta()
-
-		var path string
-
-		path = modMeta.SetMetaPath(filepath.Join(viper.GetString("meta-folder-base"), modMeta.GetMetaRelativePath()))
-
-		err = writeMod(pack, modMeta, path)
+		// If the file already exists, this will overwrite it!!!
+		// TODO: Should this be improved?
+		// Current strategy is to go ahead and do stuff without asking, with the assumption that you are using
+		// VCS anyway.
+		req := shared.NewSourceRequest(*pack, map[string]string{
+			sources.GhOptionBranch:        branchFlag,
+			sources.GhOptionRegex:         regexFlag,
+			sources.GhOptionPrereleases:   strconv.FormatBool(prereleasesFlag),
+			sources.GhOptionDrafts:        strconv.FormatBool(draftsFlag),
+			sources.GhOptionTagConstraint: tagConstraintFlag,
+			sources.GhOptionTagRegex:      tagRegexFlag,
+			sources.GhOptionStrategy:      strategyFlag,
+		})
+		err = shared.AddFromSource(pack, shared.GetSource("github"), args[0], req)
 		if err != nil {
 			shared.Exitf("Failed to add project: %s\n", err)
 		}
 
-		fmt.Printf("Project \"%s\" successfully added! (%s)\n", slugOrUrl, mod.FileName)
+		err = fileio.WriteAll(*pack, packDir)
+		if err != nil {
+			shared.Exitf("Failed to write pack file: %s\n", err)
+		}
+		fmt.Printf("Pack file written to %s\n", viper.GetString("pack-file"))
 	},
 }
-
-func writeMod(pack core.PackToml, modMeta core.ModToml, path string) error {
-	// If the file already exists, this will overwrite it!!!
-	// TODO: Should this be improved?
-	// Current strategy is to go ahead and do stuff without asking, with the assumption that you are using
-	// VCS anyway.
-	return fileio.WriteModAndUpdateIndex(&pack, &modMeta, path)
-}
@@ -0,0 +1,15 @@
+package cmdhangar
+
+import (
+	"github.com/leocov-dev/packwiz-nxt/cmd"
+	"github.com/spf13/cobra"
+)
+
+var hangarCmd = &cobra.Command{
+	Use:   "hangar",
+	Short: "Manage Paper plugins from Hangar",
+}
+
+func init() {
+	cmd.Add(hangarCmd)
+}
@@ -0,0 +1,75 @@
+package cmdhangar
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+	"github.com/leocov-dev/packwiz-nxt/internal/shared"
+	"github.com/leocov-dev/packwiz-nxt/sources"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var channelFlag string
+
+func init() {
+	hangarCmd.AddCommand(installCmd)
+
+	installCmd.Flags().StringVar(&channelFlag, "channel", "", "The release channel to install and update versions from (e.g. Release)")
+}
+
+// installCmd represents the install command
+var installCmd = &cobra.Command{
+	Use:   "add [URL|slug|search]",
+	Short: "Add a plugin from a Hangar project URL, slug or search",
+	Long: `Add a plugin from a Hangar project URL, slug or search, along with its required dependencies.
+
+The pack must have a server platform (paper or purpur) in pack.toml; plugins are added
+to the plugins folder as server-side files.`,
+	Aliases: []string{"install", "get"},
+	Args:    cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 || len(args[0]) == 0 {
+			shared.Exitln("You must specify a Hangar project URL, slug or search term.")
+		}
+
+		packFile, packDir, err := shared.GetPackPaths()
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		pack, err := shared.LoadAll(packFile)
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		req := shared.NewSourceRequest(*pack, map[string]string{
+			sources.HangarOptionChannel: channelFlag,
+		})
+		src := shared.GetSource("hangar")
+		query := strings.Join(args, " ")
+		if _, err := sources.HangarParseSlugOrUrl(query); err == nil {
+			err = shared.AddFromSource(pack, src, query, req)
+			if err != nil && strings.HasPrefix(query, "http") {
+				shared.Exitf("Failed to add project: %s\n", err)
+			} else if err != nil {
+				// Not a Hangar slug after all; treat it as a search term instead
+				fmt.Printf("%s is not a Hangar project, searching instead...\n", query)
+				err = shared.SearchAndAdd(pack, []core.Source{src}, query, req)
+			}
+		} else {
+			err = shared.SearchAndAdd(pack, []core.Source{src}, query, req)
+		}
+		if err != nil {
+			shared.Exitf("Failed to add project: %s\n", err)
+		}
+
+		err = fileio.WriteAll(*pack, packDir)
+		if err != nil {
+			shared.Exitf("Failed to write pack file: %s\n", err)
+		}
+		fmt.Printf("Pack file written to %s\n", viper.GetString("pack-file"))
+	},
+}
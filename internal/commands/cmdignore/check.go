@@ -0,0 +1,83 @@
+package cmdignore
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+	"github.com/leocov-dev/packwiz-nxt/internal/shared"
+)
+
+// checkCmd represents the ignore check command
+var checkCmd = &cobra.Command{
+	Use:   "check [path...]",
+	Short: "Show whether files are in the index, and which ignore rule excludes them",
+	Long: `Show whether each path is in the index, and if refreshing the index leaves it out, why:
+which rule of .packwizignore (or of the built-in rules that apply to every pack) matched it or a
+folder it is in, and on which line. Paths are relative to the current folder.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		packFile, _, err := shared.GetPackPaths()
+		if err != nil {
+			shared.Exitln(err)
+		}
+		pack, err := fileio.LoadPackFile(packFile)
+		if err != nil {
+			if os.IsNotExist(err) {
+				shared.Exitln("No pack.toml file found, run 'packwiz init' to create one!")
+			}
+			shared.Exitf("Error loading pack: %s\n", err)
+		}
+		index, err := fileio.LoadPackIndexFile(&pack)
+		if err != nil {
+			shared.Exitf("Error loading index: %s\n", err)
+		}
+
+		checks, err := fileio.CheckIgnored(&index, packFile, args)
+		if err != nil {
+			shared.Exitln(err)
+		}
+		for _, check := range checks {
+			fmt.Printf("%s: %s\n", check.Path, describeIgnoreCheck(check))
+		}
+	},
+}
+
+func describeIgnoreCheck(check fileio.IgnoreCheck) string {
+	status := "not indexed"
+	if check.Indexed {
+		status = "indexed"
+	}
+
+	if !check.Excluded {
+		switch {
+		case !check.Exists && check.Indexed:
+			return status + ", but doesn't exist (run 'packwiz refresh' to remove it)"
+		case !check.Exists:
+			return status + ", doesn't exist"
+		case !check.Indexed && !check.IsDir:
+			return status + ", not ignored (run 'packwiz refresh' to add it)"
+		}
+		return status + ", not ignored"
+	}
+
+	var why string
+	if check.Rule != nil {
+		why = "ignored by " + check.Rule.String()
+	} else {
+		why = "excluded as " + check.Reason
+	}
+	if check.MatchedPath != check.IndexPath {
+		why += fmt.Sprintf(" (matched folder %s)", check.MatchedPath)
+	}
+	if check.Indexed {
+		return status + ", but " + why + " (run 'packwiz refresh' to remove it)"
+	}
+	return status + ", " + why
+}
+
+func init() {
+	ignoreCmd.AddCommand(checkCmd)
+}
@@ -0,0 +1,16 @@
+package cmdignore
+
+import (
+	"github.com/leocov-dev/packwiz-nxt/cmd"
+	"github.com/spf13/cobra"
+)
+
+// ignoreCmd represents the base command when called without any subcommands
+var ignoreCmd = &cobra.Command{
+	Use:   "ignore",
+	Short: "Inspect which files .packwizignore leaves out of the index",
+}
+
+func init() {
+	cmd.Add(ignoreCmd)
+}
@@ -0,0 +1,55 @@
+package cmdmaven
+
+import (
+	"fmt"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+	"github.com/leocov-dev/packwiz-nxt/internal/shared"
+	"github.com/leocov-dev/packwiz-nxt/sources"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var repositoryFlag string
+var versionConstraintFlag string
+
+func init() {
+	mavenCmd.AddCommand(installCmd)
+
+	installCmd.Flags().StringVar(&repositoryFlag, "repository", "", "The URL of the Maven repository, e.g. https://maven.shedaniel.me/")
+	installCmd.Flags().StringVar(&versionConstraintFlag, "version-constraint", "", "Only install and update to versions matching this constraint, e.g. \"11\" or \">=11.1,<12\"")
+	_ = installCmd.MarkFlagRequired("repository")
+}
+
+// installCmd represents the install command
+var installCmd = &cobra.Command{
+	Use:     "add [group:artifact[:version[:classifier]]]",
+	Short:   "Add an artifact from a Maven repository",
+	Aliases: []string{"install", "get"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		packFile, packDir, err := shared.GetPackPaths()
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		pack, err := shared.LoadAll(packFile)
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		req := shared.NewSourceRequest(*pack, map[string]string{
+			sources.MvnOptionRepository:        repositoryFlag,
+			sources.MvnOptionVersionConstraint: versionConstraintFlag,
+		})
+		err = shared.AddFromSource(pack, shared.GetSource("maven"), args[0], req)
+		if err != nil {
+			shared.Exitf("Failed to add project: %s\n", err)
+		}
+
+		err = fileio.WriteAll(*pack, packDir)
+		if err != nil {
+			shared.Exitf("Failed to write pack file: %s\n", err)
+		}
+		fmt.Printf("Pack file written to %s\n", viper.GetString("pack-file"))
+	},
+}
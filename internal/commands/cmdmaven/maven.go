@@ -0,0 +1,16 @@
+package cmdmaven
+
+import (
+	"github.com/leocov-dev/packwiz-nxt/cmd"
+	"github.com/spf13/cobra"
+)
+
+var mavenCmd = &cobra.Command{
+	Use:     "maven",
+	Aliases: []string{"mvn"},
+	Short:   "Manage projects published to Maven repositories",
+}
+
+func init() {
+	cmd.Add(mavenCmd)
+}
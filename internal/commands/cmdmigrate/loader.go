@@ -29,7 +29,7 @@ var loaderCommand = &cobra.Command{
 			return
 		}
 
-		var currentLoaders = modpack.GetLoaders()
+		var currentLoaders = migratableLoaders(modpack)
 		// Do some sanity checks on the current loader slice
 		if len(currentLoaders) == 0 {
 			shared.Exitln("No loader is currently set in your pack.toml!")
@@ -111,7 +111,7 @@ func init() {
 // can also be called directly (e.g. from `packwiz migrate minecraft`) without going through cobra's
 // Run closure.
 func updateLoaderToLatest(modpack core.PackToml) {
-	currentLoaders := modpack.GetLoaders()
+	currentLoaders := migratableLoaders(modpack)
 	// Do some sanity checks on the current loader slice
 	if len(currentLoaders) == 0 {
 		shared.Exitln("No loader is currently set in your pack.toml!")
@@ -140,6 +140,18 @@ func updateLoaderToLatest(modpack core.PackToml) {
 	}
 }
 
+// migratableLoaders returns the mod loaders of the pack, or its server platform if it has no mod
+// loader. The mod loader of a hybrid pack (e.g. Fabric and Paper) is migrated, not the platform.
+func migratableLoaders(modpack core.PackToml) []string {
+	if loaders := modpack.GetLoaders(); len(loaders) > 0 {
+		return loaders
+	}
+	if platform, ok := modpack.GetServerPlatform(); ok {
+		return []string{platform}
+	}
+	return nil
+}
+
 func getVersionsForLoader(loader, mcVersion string) ([]string, string, core.ModLoaderComponent) {
 	gottenLoader, ok := core.ModLoaders[loader]
 	if !ok {
@@ -64,11 +64,12 @@ var minecraftCommand = &cobra.Command{
 				shared.Exitln(err)
 			}
 			fmt.Println("Loading modpack...")
-			fullPack, err := fileio.LoadAll(packFile)
+			fullPack, err := shared.LoadAll(packFile)
 			if err != nil {
 				shared.Exitln(err)
 			}
 			fmt.Println("Checking for updates...")
+			shared.LoadPlugins()
 			if err := core.UpdateAllMods(core.DefaultRegistry, *fullPack); err != nil {
 				shared.Exitln(err)
 			}
@@ -19,13 +19,14 @@ var exportCmd = &cobra.Command{
 	Short: "Export the current modpack into a .mrpack for Modrinth",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		shared.LoadPlugins()
 		fmt.Println("Loading modpack...")
 		packFile, _, err := shared.GetPackPaths()
 		if err != nil {
 			shared.Exitln(err)
 		}
 
-		pack, err := fileio.LoadAll(packFile)
+		pack, err := shared.LoadAll(packFile)
 		if err != nil {
 			shared.Exitln(err)
 		}
@@ -35,11 +36,15 @@ var exportCmd = &cobra.Command{
 			fileName = pack.GetExportName() + ".mrpack"
 		}
 
-		mods := pack.GetModsList()
-
-		fmt.Printf("Retrieving %v external files...\n", len(mods))
+		mods, skipped := sources.FilterExportable(pack.GetModsList())
+		for _, err := range skipped {
+			fmt.Printf("Warning: %v\n", err)
+		}
 
 		restrictDomains := viper.GetBool("modrinth.export.restrictDomains")
+		fromMetadata, toDownload := sources.SplitModrinthExport(mods, restrictDomains)
+
+		fmt.Printf("Retrieving %v external files...\n", len(toDownload))
 
 		for _, mod := range mods {
 			if !sources.CanBeIncludedDirectly(mod, restrictDomains) {
@@ -48,7 +53,7 @@ var exportCmd = &cobra.Command{
 			}
 		}
 
-		session, err := fileio.CreateDownloadSession(nil, mods, []string{"sha1", "sha512", "length-bytes"})
+		session, err := fileio.CreateDownloadSession(nil, toDownload, []string{"sha1", "sha512", "length-bytes"})
 		if err != nil {
 			shared.Exitf("Error retrieving external files: %v\n", err)
 		}
@@ -61,7 +66,7 @@ var exportCmd = &cobra.Command{
 				return fmt.Errorf("Failed to add overrides folder: %w", err)
 			}
 
-			manifest, err := sources.BuildModrinthManifest(cmd.Context(), *pack, session, restrictDomains, func(dl fileio.CompletedDownload, dir string) {
+			manifest, err := sources.BuildModrinthManifest(cmd.Context(), *pack, fromMetadata, session, restrictDomains, func(dl fileio.CompletedDownload, dir string) {
 				_ = shared.AddToZip(dl, exp, dir)
 			})
 			if err != nil {
@@ -1,7 +1,6 @@
 package cmdmodrinth
 
 import (
-	modrinthApi "codeberg.org/jmansfield/go-modrinth/modrinth"
 	"errors"
 	"fmt"
 	"github.com/leocov-dev/packwiz-nxt/core"
@@ -10,7 +9,6 @@ import (
 	"github.com/leocov-dev/packwiz-nxt/sources"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"gopkg.in/dixonwille/wmenu.v4"
 	"strings"
 )
 
@@ -42,93 +40,54 @@ var installCmd = &cobra.Command{
 		}
 
 		fmt.Printf("Loading modpack %s\n", packFile)
-		pack, err := fileio.LoadAll(packFile)
+		pack, err := shared.LoadAll(packFile)
 		if err != nil {
 			shared.Exitln(err)
 		}
 
 		// ---
-		var projectSlug, version, versionID, optionalFilenameMatch string
-
-		// If project/version IDs/version file name is provided in command line, use those
-		if projectIDFlag != "" {
-			projectSlug = projectIDFlag
-			if len(args) != 0 {
-				shared.Exitln("--project-id cannot be used with a separately specified URL/slug/search term")
-			}
-		}
-		if versionIDFlag != "" {
-			versionID = versionIDFlag
-			if len(args) != 0 {
-				shared.Exitln("--version-id cannot be used with a separately specified URL/slug/search term")
-			}
+		if projectIDFlag != "" && len(args) != 0 {
+			shared.Exitln("--project-id cannot be used with a separately specified URL/slug/search term")
 		}
-		if versionFilenameFlag != "" {
-			optionalFilenameMatch = versionFilenameFlag
+		if versionIDFlag != "" && len(args) != 0 {
+			shared.Exitln("--version-id cannot be used with a separately specified URL/slug/search term")
 		}
-
-		if (len(args) == 0 || len(args[0]) == 0) && projectSlug == "" {
+		if (len(args) == 0 || len(args[0]) == 0) && projectIDFlag == "" && versionIDFlag == "" {
 			shared.Exitln("You must specify a project; with the ID flags, or by passing a URL, slug or search term directly.")
 		}
 
-		if projectSlug == "" && versionID == "" && len(args) == 1 {
-			if parsedSlug := sources.ParseAsModrinthSlug(args[0]); parsedSlug != "" {
-				projectSlug = parsedSlug
-			}
-			if parsedVersion := sources.ParseAsModrinthVersion(args[0]); parsedVersion != "" {
-				version = parsedVersion
-			}
-			if parsedVersionID := sources.ParseAsModrinthVersionID(args[0]); parsedVersionID != "" {
-				versionID = parsedVersionID
-			}
-			if parsedFilename := sources.ParseAsModrinthFilename(args[0]); parsedFilename != "" {
-				optionalFilenameMatch = parsedFilename
-			}
-		}
-
-		// Got version ID; install using this ID
-		if versionID != "" {
-			err = installVersionById(versionID, optionalFilenameMatch, pack)
+		src := shared.GetSource("modrinth")
+		req := shared.NewSourceRequest(*pack, map[string]string{
+			sources.MrOptionVersionID:      versionIDFlag,
+			sources.MrOptionFilename:       versionFilenameFlag,
+			sources.MrOptionDatapackFolder: viper.GetString("datapack-folder"),
+		})
+
+		identifier := projectIDFlag
+		var isBareSlug bool
+		if identifier == "" && len(args) == 1 {
+			var slug, version, versionID, filename string
+			isBareSlug, err = sources.ParseModrinthSlugOrUrl(args[0], &slug, &version, &versionID, &filename)
 			if err != nil {
-				shared.Exitf("Failed to add project: %s\n", err)
+				shared.Exitf("Failed to parse URL: %v\n", err)
+			}
+			if slug != "" || versionID != "" {
+				identifier = args[0]
 			}
-			return
 		}
 
-		// Look up project ID
-		if projectSlug != "" {
-			// Modrinth transparently handles slugs/project IDs in their API; we don't have to detect which one it is.
-			var project *modrinthApi.Project
-			project, err = sources.GetModrinthClient().Projects.Get(projectSlug)
-			if err == nil {
-				var versionData *modrinthApi.Version
-				if version == "" {
-					versionData, err = sources.ModrinthGetLatestVersion(*project.ID, *project.Title, *pack, viper.GetString("datapack-folder"))
-					if err != nil {
-						shared.Exitf("failed to get latest version: %v", err)
-					}
-				} else {
-					versionData, err = sources.ResolveModrinthVersion(project, version)
-					if err != nil {
-						shared.Exitf("Failed to add project: %s\n", err)
-					}
-					return
-				}
-
-				err = installVersion(project, versionData, optionalFilenameMatch, pack)
-				if err != nil {
-					shared.Exitf("Failed to add project: %s\n", err)
-				}
-				return
+		if identifier != "" || versionIDFlag != "" {
+			err = shared.AddFromSource(pack, src, identifier, req)
+			if errors.Is(err, core.ErrProjectNotFound) && isBareSlug {
+				// Not a slug/project ID after all, try to search for it instead
+				err = shared.SearchAndAdd(pack, []core.Source{src}, args[0], req)
 			}
-		} else if len(args) > 0 {
+		} else {
 			// Arguments weren't a valid slug/project ID, try to search for it instead
 			// (if it was not parsed as a URL)
-			err = installViaSearch(strings.Join(args, " "), optionalFilenameMatch, pack)
-			if err != nil {
-				shared.Exitf("Failed to add project: %s\n", err)
-			}
-		} else {
+			err = shared.SearchAndAdd(pack, []core.Source{src}, strings.Join(args, " "), req)
+		}
+		if err != nil {
 			shared.Exitf("Failed to add project: %s\n", err)
 		}
 
@@ -139,110 +98,3 @@ var installCmd = &cobra.Command{
 		fmt.Printf("Pack file written to %s\n", viper.GetString("pack-file"))
 	},
 }
-
-func installVersionById(versionId string, optionalFilenameMatch string, pack *core.Pack) error {
-	project, version, err := sources.ModrinthProjectFromVersionID(versionId)
-	if err != nil {
-		return fmt.Errorf("failed to fetch project for versionId %s: %v", versionId, err)
-	}
-
-	return installVersion(project, version, optionalFilenameMatch, pack)
-}
-
-func installViaSearch(query string, optionalFilenameMatch string, pack *core.Pack) error {
-	mcVersions, err := pack.GetSupportedMCVersions()
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Searching Modrinth...")
-
-	projects, err := sources.ModrinthSearchForProjects(query, mcVersions)
-	if err != nil {
-		return err
-	}
-
-	if viper.GetBool("non-interactive") || (len(projects) == 1 && optionalFilenameMatch != "") {
-		// Install the first project found
-		return installProject(projects[0], optionalFilenameMatch, pack)
-	}
-
-	// Create a menu for the user to choose the correct project
-	menu := wmenu.NewMenu("Choose a number:")
-	menu.Option("Cancel", nil, false, nil)
-	for i, v := range projects {
-		// Should be non-nil (Title is a required field)
-		menu.Option(*v.Title, v, i == 0, nil)
-	}
-
-	menu.Action(func(menuRes []wmenu.Opt) error {
-		if len(menuRes) != 1 || menuRes[0].Value == nil {
-			return errors.New("project selection cancelled")
-		}
-
-		// Get the selected project
-		selectedProject, ok := menuRes[0].Value.(*modrinthApi.Project)
-		if !ok {
-			return errors.New("error converting interface from wmenu")
-		}
-
-		return installProject(selectedProject, optionalFilenameMatch, pack)
-	})
-
-	return menu.Run()
-}
-
-func installProject(project *modrinthApi.Project, optionalFilenameMatch string, pack *core.Pack) error {
-	latestVersion, err := sources.ModrinthGetLatestVersion(*project.ID, *project.Title, *pack, viper.GetString("datapack-folder"))
-	if err != nil {
-		return fmt.Errorf("failed to get latest version: %v", err)
-	}
-
-	return installVersion(project, latestVersion, optionalFilenameMatch, pack)
-}
-
-func installVersion(project *modrinthApi.Project, version *modrinthApi.Version, optionalFilenameMatch string, pack *core.Pack) error {
-	if len(version.Files) == 0 {
-		return errors.New("version doesn't have any files attached")
-	}
-
-	var missingDependencies []*core.Mod
-	if len(version.Dependencies) > 0 {
-
-		missingDependencies, err := sources.ModrinthFindMissingDependencies(version, *pack, viper.GetString("datapack-folder"))
-		if err != nil {
-			return err
-		}
-
-		if len(missingDependencies) > 0 {
-			fmt.Println("Dependencies found:")
-			for _, v := range missingDependencies {
-				fmt.Println(v.Slug)
-			}
-
-			if !shared.PromptYesNo("Would you like to add them? [Y/n]: ") {
-				// if NO is chosen then we'll nil the slice to prevent installing
-				missingDependencies = nil
-			}
-		}
-
-	}
-
-	mainMod, err := sources.ModrinthNewMod(project, version, viper.GetString("meta-folder"), pack.GetCompatibleLoaders(), optionalFilenameMatch)
-	if err != nil {
-		return err
-	}
-
-	newMods := append(missingDependencies, mainMod)
-
-	if len(newMods) == 0 {
-		return errors.New("no mods were installed")
-	}
-
-	for _, mod := range newMods {
-		pack.SetMod(mod)
-	}
-
-	fmt.Printf("Project \"%s\" successfully added! (%s)\n", *project.Title, newMods[len(newMods)-1].Slug)
-	return nil
-}
@@ -0,0 +1,73 @@
+package cmdsettings
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+	"github.com/leocov-dev/packwiz-nxt/internal/shared"
+)
+
+var datapackFolderCommand = &cobra.Command{
+	Use:   "datapack-folder [folder]",
+	Short: "Show or set the folder datapacks are installed into, e.g. config/paxi/datapacks",
+	Long: `Show or set the folder Modrinth and CurseForge datapacks are installed into.
+
+Datapacks outside of a world's datapacks folder (such as world/datapacks on a server) need
+a datapack loader mod to be loaded, e.g. config/paxi/datapacks for Paxi or
+config/openloader/data for Open Loader. Use --unset to remove the setting.`,
+	Aliases: []string{"df"},
+	Args:    cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		modpack, err := fileio.LoadPackFile(viper.GetString("pack-file"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				shared.Exitln("No pack.toml file found, run 'packwiz init' to create one!")
+			}
+			shared.Exitf("Error loading pack: %s\n", err)
+		}
+
+		if len(args) == 0 && !flagUnset {
+			folder := modpack.GetDatapackFolder()
+			if folder == "" {
+				fmt.Println("No datapack folder is set")
+			} else {
+				fmt.Println(folder)
+			}
+			return
+		}
+
+		folder := ""
+		if !flagUnset {
+			folder = args[0]
+		}
+		modpack.SetDatapackFolder(folder)
+
+		packWriter := fileio.NewPackWriter()
+		if err := packWriter.Write(&modpack); err != nil {
+			shared.Exitf("Error writing pack: %s\n", err)
+		}
+
+		folder = modpack.GetDatapackFolder()
+		if folder == "" {
+			fmt.Println("Removed the datapack folder setting")
+			return
+		}
+		fmt.Printf("Set the datapack folder to %s\n", folder)
+		if !core.IsWorldDatapackFolder(folder) {
+			fmt.Println("Datapacks in this folder need a datapack loader mod such as Paxi or Open Loader; see 'packwiz check'")
+		}
+	},
+}
+
+var flagUnset bool
+
+func init() {
+	settingsCmd.AddCommand(datapackFolderCommand)
+
+	datapackFolderCommand.Flags().BoolVar(&flagUnset, "unset", false, "Remove the datapack folder setting")
+}
@@ -0,0 +1,71 @@
+package cmdsettings
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+	"github.com/leocov-dev/packwiz-nxt/internal/shared"
+)
+
+var minReleaseAgeCommand = &cobra.Command{
+	Use:   "min-release-age [age]",
+	Short: "Show or set the minimum age of versions mods are updated to, e.g. 72h or 3d",
+	Long: `Show or set the minimum age of versions mods are updated to.
+
+Modrinth versions and CurseForge files published more recently than this are skipped by
+'packwiz update', which reports them as held back. Ages are Go durations such as 72h, or a
+number of days such as 3d. A mod can override it with min-release-age in its .pw.toml ("0"
+exempts it). Use --unset to remove the setting.`,
+	Aliases: []string{"mra"},
+	Args:    cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		modpack, err := fileio.LoadPackFile(viper.GetString("pack-file"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				shared.Exitln("No pack.toml file found, run 'packwiz init' to create one!")
+			}
+			shared.Exitf("Error loading pack: %s\n", err)
+		}
+
+		if len(args) == 0 && !flagUnsetMinReleaseAge {
+			age := modpack.GetMinReleaseAge()
+			if age == "" {
+				fmt.Println("No minimum release age is set")
+			} else {
+				fmt.Println(age)
+			}
+			return
+		}
+
+		age := ""
+		if !flagUnsetMinReleaseAge {
+			age = args[0]
+		}
+		if err := modpack.SetMinReleaseAge(age); err != nil {
+			shared.Exitln(err)
+		}
+
+		packWriter := fileio.NewPackWriter()
+		if err := packWriter.Write(&modpack); err != nil {
+			shared.Exitf("Error writing pack: %s\n", err)
+		}
+
+		if age == "" {
+			fmt.Println("Removed the minimum release age setting")
+			return
+		}
+		fmt.Printf("Set the minimum release age to %s\n", modpack.GetMinReleaseAge())
+	},
+}
+
+var flagUnsetMinReleaseAge bool
+
+func init() {
+	settingsCmd.AddCommand(minReleaseAgeCommand)
+
+	minReleaseAgeCommand.Flags().BoolVar(&flagUnsetMinReleaseAge, "unset", false, "Remove the minimum release age setting")
+}
@@ -1,79 +1,84 @@
 package cmdurl
 
 import (
-	"context"
 	"fmt"
 	"github.com/leocov-dev/packwiz-nxt/core"
 	"github.com/leocov-dev/packwiz-nxt/fileio"
 	"github.com/leocov-dev/packwiz-nxt/internal/shared"
+	"github.com/leocov-dev/packwiz-nxt/sources"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"net/url"
-	"path"
 	"path/filepath"
-	"strings"
 )
 
 var installCmd = &cobra.Command{
-	Use:     "add [name] [url]",
-	Short:   "Add an external file from a direct download link, for sites that are not directly supported by packwiz",
+	Use:   "add [name] [url]",
+	Short: "Add an external file from a direct download link, for sites that are not directly supported by packwiz",
+	Long: `Add an external file from a direct download link, for sites that are not directly supported by packwiz.
+
+By default the URL is treated as a stable "latest" link: packwiz update re-downloads it when its
+ETag, Last-Modified or Content-Length headers change. Alternatively, --template gives a download URL
+containing {version}, and --versions-url a page (e.g. a directory listing, matched with --version-regex)
+or JSON document (read at --version-path) listing the available versions. The URL argument is then
+not needed.`,
 	Aliases: []string{"install", "get"},
-	Args:    cobra.ExactArgs(2),
+	Args:    cobra.RangeArgs(1, 2),
 	Run: func(cmd *cobra.Command, args []string) {
 		pack, err := fileio.LoadPackFile(viper.GetString("pack-file"))
 		if err != nil {
 			shared.Exitln(err)
 		}
 
-		dl, err := url.Parse(args[1])
-		if err != nil {
-			shared.Exitln("Failed to parse URL:", err)
+		template := sources.UrlTemplate{
+			Template:     templateFlag,
+			VersionsURL:  versionsUrlFlag,
+			VersionPath:  versionPathFlag,
+			VersionRegex: versionRegexFlag,
 		}
-		if dl.Scheme != "https" && dl.Scheme != "http" {
-			shared.Exitln("Unsupported URL scheme:", dl.Scheme)
+		var rawURL string
+		if len(args) > 1 {
+			rawURL = args[1]
 		}
-
-		// TODO: consider using colors for these warnings but those can have issues on windows
-		force, err := cmd.Flags().GetBool("force")
-		if !force && err == nil {
-			var msg string
-			// TODO: update when github command is added
-			// TODO: make this generic?
-			//if dl.Host == "www.github.com" || dl.Host == "github.com" {
-			//	msg = "github add " + args[1]
-			//}
-			if strings.HasSuffix(dl.Host, "modrinth.com") {
-				msg = "modrinth add " + args[1]
+		if template.Template == "" {
+			if rawURL == "" {
+				shared.Exitln("You must specify a URL, or a URL template with --template.")
 			}
-			if strings.HasSuffix(dl.Host, "curseforge.com") || strings.HasSuffix(dl.Host, "forgecdn.net") {
-				msg = "curseforge add " + args[1]
+			dl, err := url.Parse(rawURL)
+			if err != nil {
+				shared.Exitln("Failed to parse URL:", err)
 			}
-			if msg != "" {
-				shared.Exitln("Consider using packwiz", msg, "instead; if you know what you are doing use --force to add this file without update metadata.")
+			if dl.Scheme != "https" && dl.Scheme != "http" {
+				shared.Exitln("Unsupported URL scheme:", dl.Scheme)
+			}
+
+			// TODO: consider using colors for these warnings but those can have issues on windows
+			force, err := cmd.Flags().GetBool("force")
+			if !force && err == nil {
+				if target, err := sources.DetectProvider(rawURL); err != nil || target.Provider != sources.ProviderURL {
+					shared.Exitln("Consider using packwiz add with the project's page URL instead; if you know what you are doing use --force to add this file anyway.")
+				}
 			}
 		}
 
-		hash, err := getHash(cmd.Context(), args[1])
+		folder := viper.GetString("meta-folder")
+		if folder == "" {
+			folder = "mods"
+		}
+
+		mod, err := sources.UrlNewMod(args[0], rawURL, template, folder)
 		if err != nil {
-			shared.Exitln("Failed to retrieve SHA256 hash for file", err)
+			shared.Exitln("Failed to add file:", err)
 		}
 
-		filename := path.Base(dl.Path)
 		modMeta := core.ModToml{
-			Name:     args[0],
-			FileName: filename,
-			Side:     core.UniversalSide,
-			Download: core.ModDownload{
-				URL:        args[1],
-				HashFormat: "sha256",
-				Hash:       hash,
-			},
+			Name:     mod.Name,
+			FileName: mod.FileName,
+			Side:     mod.Side,
+			Download: mod.Download,
+			Update:   mod.Update,
 		}
 
-		folder := viper.GetString("meta-folder")
-		if folder == "" {
-			folder = "mods"
-		}
 		destPathName, err := cmd.Flags().GetString("meta-name")
 		if err != nil {
 			shared.Exitln(err)
@@ -88,46 +93,40 @@ var installCmd = &cobra.Command{
 		if err != nil {
 			shared.Exitln(err)
 		}
-		fmt.Printf("Successfully added %s (%s) from: %s\n", args[0], destPath, args[1])
+		fmt.Printf("Successfully added %s (%s) from: %s\n", args[0], destPath, mod.Download.URL)
 	}}
 
-// getHash retrieves the SHA256 hash of the file at the given URL by downloading it through the
-// shared fileio download/cache machinery (the same DownloadSession used by the other providers),
-// rather than hand-rolling an HTTP fetch-and-hash.
-func getHash(ctx context.Context, url string) (string, error) {
-	dlMod := &core.Mod{
-		Download: core.ModDownload{
-			URL:  url,
-			Mode: core.ModeURL,
-		},
+// AddURL adds the file at rawURL to pack as an external file, named after the file name in
+// the URL and updated whenever the file at the URL changes. The pack is not written to disk.
+func AddURL(pack *core.Pack, rawURL string) error {
+	folder := viper.GetString("meta-folder")
+	if folder == "" {
+		folder = "mods"
 	}
 
-	session, err := fileio.CreateDownloadSession(nil, []*core.Mod{dlMod}, []string{"sha256"})
+	mod, err := sources.UrlNewMod("", rawURL, sources.UrlTemplate{}, folder)
 	if err != nil {
-		return "", err
-	}
-
-	var hash string
-	for dl := range session.StartDownloads(ctx) {
-		if dl.File != nil {
-			_ = dl.File.Close()
-		}
-		if dl.Error != nil {
-			return "", dl.Error
-		}
-		hash = dl.Hashes["sha256"]
+		return err
 	}
+	pack.SetMod(mod)
 
-	if err := session.SaveIndex(); err != nil {
-		return "", err
-	}
-
-	return hash, nil
+	fmt.Printf("Successfully added %s from: %s\n", mod.Name, rawURL)
+	shared.CheckAddedMod(pack, mod)
+	return nil
 }
 
+var templateFlag string
+var versionsUrlFlag string
+var versionPathFlag string
+var versionRegexFlag string
+
 func init() {
 	urlCmd.AddCommand(installCmd)
 
-	installCmd.Flags().Bool("force", false, "Add a file even if the download URL is supported by packwiz in an alternative command (which may support dependencies and updates)")
+	installCmd.Flags().Bool("force", false, "Add a file even if the download URL is supported by packwiz in an alternative command (which may support dependencies)")
 	installCmd.Flags().String("meta-name", "", "Filename to use for the created metadata file (defaults to a name generated from the name you supply)")
+	installCmd.Flags().StringVar(&templateFlag, "template", "", "A download URL containing {version}, to update by version instead of re-checking a stable URL")
+	installCmd.Flags().StringVar(&versionsUrlFlag, "versions-url", "", "The page or JSON document listing available versions, used with --template")
+	installCmd.Flags().StringVar(&versionPathFlag, "version-path", "", "The dot-separated path to the versions in a JSON --versions-url, e.g. \"versions.id\"")
+	installCmd.Flags().StringVar(&versionRegexFlag, "version-regex", "", "A regular expression whose first group captures versions from a --versions-url page (defaults to matching the template's file name)")
 }
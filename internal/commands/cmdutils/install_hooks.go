@@ -0,0 +1,114 @@
+package cmdutils
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/leocov-dev/packwiz-nxt/internal/shared"
+)
+
+// hookMarker identifies pre-commit hooks written by install-hooks, which can be replaced without --force
+const hookMarker = "# Installed by 'packwiz utils install-hooks'"
+
+// installHooksCmd represents the install-hooks command
+var installHooksCmd = &cobra.Command{
+	Use:   "install-hooks",
+	Short: "Install a git pre-commit hook that stops commits while the pack's index is out of date",
+	Long: `Install a git pre-commit hook in the repository containing the pack, which runs
+'packwiz refresh --check' so that commits are rejected while index.toml or the index hash in
+pack.toml is out of date. Run 'packwiz refresh' to update them, or commit with --no-verify to
+skip the check.
+
+An existing pre-commit hook that wasn't installed by packwiz is only replaced with --force.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		packFile, packDir, err := shared.GetPackPaths()
+		if err != nil {
+			shared.Exitln(err)
+		}
+		if _, err := os.Stat(packFile); err != nil {
+			shared.Exitf("Error loading pack: %s\n", err)
+		}
+
+		topLevel, err := gitRevParse(packDir, "--show-toplevel")
+		if err != nil {
+			shared.Exitf("Error finding the git repository containing the pack: %s\n", err)
+		}
+		hooksDir, err := gitRevParse(packDir, "--git-path", "hooks")
+		if err != nil {
+			shared.Exitf("Error finding the git hooks folder: %s\n", err)
+		}
+		if !filepath.IsAbs(hooksDir) {
+			hooksDir = filepath.Join(packDir, hooksDir)
+		}
+
+		// Hooks run from the top level of the repository
+		packFile, err = filepath.EvalSymlinks(packFile)
+		if err != nil {
+			shared.Exitln(err)
+		}
+		relPackFile, err := filepath.Rel(topLevel, packFile)
+		if err != nil {
+			shared.Exitln(err)
+		}
+
+		hookPath := filepath.Join(hooksDir, "pre-commit")
+		if existing, err := os.ReadFile(hookPath); err == nil {
+			if !strings.Contains(string(existing), hookMarker) && !viper.GetBool("utils.install-hooks.force") {
+				shared.Exitf("A pre-commit hook already exists at %s; use --force to replace it\n", hookPath)
+			}
+		} else if !os.IsNotExist(err) {
+			shared.Exitf("Error reading existing hook: %s\n", err)
+		}
+
+		hook := fmt.Sprintf("#!/bin/sh\n%s: stops commits while the pack's index is out of date\nexec %s --pack-file %s refresh --check\n",
+			hookMarker, shellQuote(viper.GetString("utils.install-hooks.command")), shellQuote(filepath.ToSlash(relPackFile)))
+
+		if err := os.MkdirAll(hooksDir, 0755); err != nil {
+			shared.Exitf("Error creating hooks folder: %s\n", err)
+		}
+		if err := os.WriteFile(hookPath, []byte(hook), 0755); err != nil {
+			shared.Exitf("Error writing hook: %s\n", err)
+		}
+		// WriteFile doesn't change the mode of an existing file
+		if err := os.Chmod(hookPath, 0755); err != nil {
+			shared.Exitf("Error making hook executable: %s\n", err)
+		}
+
+		fmt.Printf("Installed pre-commit hook at %s\n", hookPath)
+	},
+}
+
+// gitRevParse runs git rev-parse with args in dir, returning its trimmed output
+func gitRevParse(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"rev-parse"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// shellQuote quotes s as a single POSIX shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func init() {
+	utilsCmd.AddCommand(installHooksCmd)
+
+	installHooksCmd.Flags().Bool("force", false, "Replace an existing pre-commit hook that wasn't installed by packwiz")
+	_ = viper.BindPFlag("utils.install-hooks.force", installHooksCmd.Flags().Lookup("force"))
+	installHooksCmd.Flags().String("command", "packwiz", "The command the hook runs packwiz with")
+	_ = viper.BindPFlag("utils.install-hooks.command", installHooksCmd.Flags().Lookup("command"))
+}
@@ -0,0 +1,81 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+)
+
+// CheckModFiles downloads (or reads from the cache) the files of the given mods that are
+// inspected by core.ModFileChecks, and returns the issues found in them. Files that can't be
+// downloaded are skipped with a warning.
+func CheckModFiles(ctx context.Context, pack *core.Pack, mods []*core.Mod) ([]core.PackIssue, error) {
+	toCheck := core.ModsToCheckFiles(mods)
+	if len(toCheck) == 0 {
+		return nil, nil
+	}
+
+	session, err := fileio.CreateDownloadSession(nil, toCheck, []string{})
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []core.PackIssue
+	for dl := range session.StartDownloads(ctx) {
+		if dl.Error != nil {
+			fmt.Printf("Warning: couldn't check %s (%s): %v\n", dl.Mod.Name, dl.Mod.FileName, dl.Error)
+			continue
+		}
+		info, err := dl.File.Stat()
+		if err == nil {
+			issues = append(issues, core.CheckModFile(pack, dl.Mod, dl.File, info.Size())...)
+		} else {
+			fmt.Printf("Warning: couldn't check %s (%s): %v\n", dl.Mod.Name, dl.Mod.FileName, err)
+		}
+		_ = dl.File.Close()
+	}
+
+	return issues, session.SaveIndex()
+}
+
+// PrintIssues prints the issues found by core.CheckPack or CheckModFiles as warnings
+func PrintIssues(issues []core.PackIssue) {
+	for _, issue := range issues {
+		fmt.Printf("Warning %s\n", issue)
+	}
+}
+
+// CheckAddedMod warns about problems that mod causes now it is added to pack, and offers to
+// install a shader loader if it is a shader pack and the pack has none.
+func CheckAddedMod(pack *core.Pack, mod *core.Mod) {
+	PrintIssues(core.IssuesFor(core.CheckPack(pack), mod.Slug))
+
+	fileIssues, err := CheckModFiles(context.Background(), pack, []*core.Mod{mod})
+	if err != nil {
+		fmt.Printf("Warning: couldn't check %s: %v\n", mod.Name, err)
+	}
+	PrintIssues(fileIssues)
+
+	if !core.IsShaderPack(mod) {
+		return
+	}
+	if _, ok := pack.FindShaderLoader(core.ShaderFormatOptifine); ok {
+		return
+	}
+	loader, ok := pack.SuggestShaderLoader(core.ShaderFormatIris)
+	if !ok {
+		return
+	}
+	src, ok := core.GetSource("modrinth")
+	if !ok {
+		return
+	}
+	if !PromptYesNo(fmt.Sprintf("Would you like to add %s to load shader packs? [Y/n]: ", loader.Name)) {
+		return
+	}
+	if err := AddFromSource(pack, src, loader.InstallSlug, core.SourceRequest{Pack: *pack}); err != nil {
+		fmt.Printf("Failed to add %s: %v\n", loader.Name, err)
+	}
+}
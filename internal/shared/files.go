@@ -1,8 +1,14 @@
 package shared
 
 import (
-	"github.com/spf13/viper"
+	"errors"
+	"fmt"
 	"path/filepath"
+
+	"github.com/spf13/viper"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
 )
 
 func GetPackPaths() (string, string, error) {
@@ -15,3 +21,57 @@ func GetPackPaths() (string, string, error) {
 
 	return packFile, packDir, nil
 }
+
+// LoadAll loads the pack at packFile with fileio.LoadAll. With --strict, its hashes are
+// verified first: mismatches are listed, and the user is offered to fix them by refreshing the
+// index. In non-interactive mode mismatches are an error, as they may be edits that shouldn't
+// be accepted unseen.
+func LoadAll(packFile string) (*core.Pack, error) {
+	if !viper.GetBool("strict") {
+		return fileio.LoadAll(packFile)
+	}
+
+	pack, err := fileio.LoadAllWith(packFile, fileio.LoadOptions{Strict: true})
+	var mismatchErr *fileio.HashMismatchError
+	if !errors.As(err, &mismatchErr) {
+		return pack, err
+	}
+	if viper.GetBool("non-interactive") {
+		return nil, fmt.Errorf("%w\nrun 'packwiz refresh' to update them", mismatchErr)
+	}
+	fmt.Println(mismatchErr)
+	if !PromptYesNo("Refresh the index to fix them? [Y/n]: ") {
+		return nil, errors.New("the pack doesn't match its recorded hashes; run 'packwiz refresh' to update them")
+	}
+	if err := refreshIndex(packFile); err != nil {
+		return nil, err
+	}
+	fmt.Println("Index refreshed!")
+	return fileio.LoadAllWith(packFile, fileio.LoadOptions{Strict: true})
+}
+
+// refreshIndex refreshes the index of the pack at packFile, as packwiz refresh does
+func refreshIndex(packFile string) error {
+	pack, err := fileio.LoadPackFile(packFile)
+	if err != nil {
+		return err
+	}
+	index, err := fileio.LoadPackIndexFile(&pack)
+	if err != nil {
+		return err
+	}
+
+	skipped, err := fileio.RefreshIndexFiles(&index, packFile, nil)
+	if err != nil {
+		return err
+	}
+	for _, err := range skipped {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	if err := fileio.WriteIndex(&index); err != nil {
+		return err
+	}
+	pack.RefreshIndexHash(index)
+	return fileio.NewPackWriter().Write(&pack)
+}
@@ -0,0 +1,43 @@
+package shared
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/viper"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/sources"
+)
+
+var (
+	pluginsOnce sync.Once
+	pluginsMu   sync.Mutex
+	plugins     []*sources.Plugin
+)
+
+// LoadPlugins starts the provider plugins in the plugin path and registers them on
+// core.DefaultRegistry, the first time it is called. Commands that use providers from the
+// registry (add, update, export) call it, so other commands don't start plugins at all.
+func LoadPlugins() {
+	pluginsOnce.Do(func() {
+		loaded, err := sources.LoadPlugins(core.DefaultRegistry, filepath.SplitList(viper.GetString("plugins.path")))
+		if err != nil {
+			fmt.Printf("Warning: failed to load plugins: %v\n", err)
+		}
+		pluginsMu.Lock()
+		plugins = loaded
+		pluginsMu.Unlock()
+	})
+}
+
+// ClosePlugins closes the plugins started by LoadPlugins
+func ClosePlugins() {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	for _, p := range plugins {
+		_ = p.Close()
+	}
+	plugins = nil
+}
@@ -0,0 +1,141 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/viper"
+	"gopkg.in/dixonwille/wmenu.v4"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/sources"
+)
+
+// ErrSelectionCancelled is returned by SearchAndAdd when the user cancels the search menu
+var ErrSelectionCancelled = errors.New("project selection cancelled")
+
+// NewSourceRequest builds a core.SourceRequest for adding to pack, using the
+// --meta-folder flag and the given provider-specific options.
+func NewSourceRequest(pack core.Pack, options map[string]string) core.SourceRequest {
+	return core.SourceRequest{
+		Pack:       pack,
+		MetaFolder: viper.GetString("meta-folder"),
+		Options:    options,
+	}
+}
+
+// GetSource looks up a Source on core.DefaultRegistry, exiting if it isn't registered.
+func GetSource(name string) core.Source {
+	src, ok := core.GetSource(name)
+	if !ok {
+		Exitf("No source registered for %s\n", name)
+	}
+	return src
+}
+
+// AddFromSource resolves identifier with src and adds the project, and (after prompting)
+// any missing dependencies, to pack. The pack is not written to disk.
+func AddFromSource(pack *core.Pack, src core.Source, identifier string, req core.SourceRequest) error {
+	project, err := src.ResolveProject(identifier, req)
+	if err != nil {
+		return err
+	}
+	return AddSourceProject(pack, src, project, req)
+}
+
+// AddSourceProject adds an already resolved or searched project, and (after prompting) any
+// missing dependencies, to pack. The pack is not written to disk.
+func AddSourceProject(pack *core.Pack, src core.Source, project core.SourceProject, req core.SourceRequest) error {
+	mainMod, version, err := core.NewModFromSourceProject(src, project, req)
+	if err != nil {
+		return err
+	}
+
+	missingDependencies, err := src.FindMissingDependencies(version, req)
+	if err != nil {
+		return err
+	}
+
+	if len(missingDependencies) > 0 {
+		fmt.Println("Dependencies found:")
+		for _, v := range missingDependencies {
+			fmt.Println(v.Slug)
+		}
+
+		if !PromptYesNo("Would you like to add them? [Y/n]: ") {
+			// if NO is chosen then we'll nil the slice to prevent installing
+			missingDependencies = nil
+		}
+	}
+
+	for _, mod := range append(missingDependencies, mainMod) {
+		pack.SetMod(mod)
+	}
+
+	fmt.Printf("Project \"%s\" successfully added! (%s)\n", mainMod.Name, mainMod.FileName)
+	CheckAddedMod(pack, mainMod)
+	return nil
+}
+
+// SearchAndAdd searches srcs for query, asks the user to choose from the ranked results
+// (or picks the best match in non-interactive mode) and adds it to pack with
+// AddSourceProject.
+func SearchAndAdd(pack *core.Pack, srcs []core.Source, query string, req core.SourceRequest) error {
+	fmt.Printf("Searching for %s...\n", query)
+
+	results, err := sources.SearchSources(srcs, query, req)
+	if err != nil {
+		if len(results) == 0 {
+			return err
+		}
+		fmt.Printf("Warning: some sources could not be searched: %v\n", err)
+	}
+	if len(results) == 0 {
+		return errors.New("no projects found")
+	}
+
+	bySource := make(map[string]core.Source, len(srcs))
+	for _, src := range srcs {
+		bySource[src.GetName()] = src
+	}
+	add := func(project core.SourceProject) error {
+		src, ok := bySource[project.Source]
+		if !ok {
+			return fmt.Errorf("unknown source %s", project.Source)
+		}
+		return AddSourceProject(pack, src, project, req)
+	}
+
+	if viper.GetBool("non-interactive") || len(results) == 1 {
+		return add(results[0])
+	}
+
+	showSource := len(srcs) > 1
+	menu := wmenu.NewMenu("Choose a number:")
+	menu.Option("Cancel", nil, false, nil)
+	for i, v := range results {
+		label := v.Name
+		if showSource {
+			label += " [" + v.Source + "]"
+		}
+		if v.Summary != "" {
+			label += " (" + v.Summary + ")"
+		}
+		menu.Option(label, v, i == 0, nil)
+	}
+
+	menu.Action(func(menuRes []wmenu.Opt) error {
+		if len(menuRes) != 1 || menuRes[0].Value == nil {
+			return ErrSelectionCancelled
+		}
+
+		selected, ok := menuRes[0].Value.(core.SourceProject)
+		if !ok {
+			return errors.New("error converting interface from wmenu")
+		}
+
+		return add(selected)
+	})
+
+	return menu.Run()
+}
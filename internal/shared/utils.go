@@ -21,10 +21,12 @@ func GetRawForgeVersion(version string) string {
 
 func Exitf(format string, a ...interface{}) {
 	fmt.Printf(format, a...)
+	ClosePlugins()
 	os.Exit(1)
 }
 
 func Exitln(a ...interface{}) {
 	fmt.Println(a...)
+	ClosePlugins()
 	os.Exit(1)
 }
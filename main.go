@@ -3,8 +3,13 @@ package main
 import (
 	"github.com/leocov-dev/packwiz-nxt/cmd"
 	"github.com/leocov-dev/packwiz-nxt/config"
+	_ "github.com/leocov-dev/packwiz-nxt/internal/commands/cmdadd"
 	_ "github.com/leocov-dev/packwiz-nxt/internal/commands/cmdcurseforge"
+	_ "github.com/leocov-dev/packwiz-nxt/internal/commands/cmdgitea"
 	_ "github.com/leocov-dev/packwiz-nxt/internal/commands/cmdgithub"
+	_ "github.com/leocov-dev/packwiz-nxt/internal/commands/cmdhangar"
+	_ "github.com/leocov-dev/packwiz-nxt/internal/commands/cmdignore"
+	_ "github.com/leocov-dev/packwiz-nxt/internal/commands/cmdmaven"
 	_ "github.com/leocov-dev/packwiz-nxt/internal/commands/cmdmigrate"
 	_ "github.com/leocov-dev/packwiz-nxt/internal/commands/cmdmodrinth"
 	_ "github.com/leocov-dev/packwiz-nxt/internal/commands/cmdsettings"
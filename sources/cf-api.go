@@ -162,6 +162,7 @@ type CfModInfo struct {
 	GameID                 uint32          `json:"gameId"`
 	PrimaryCategoryID      uint32          `json:"primaryCategoryId"`
 	ClassID                uint32          `json:"classId"`
+	DownloadCount          float64         `json:"downloadCount"`
 	LatestFiles            []CfModFileInfo `json:"latestFiles"`
 	GameVersionLatestFiles []struct {
 		// TODO: check how twitch launcher chooses which one to use, when you are on beta/alpha channel?!
@@ -280,6 +281,34 @@ func (i CfModFileInfo) GetBestHash() (hash string, hashFormat string) {
 	return
 }
 
+// GetHashes returns every hash of the file, keyed by hash format
+func (i CfModFileInfo) GetHashes() map[string]string {
+	hashes := map[string]string{"murmur2": strconv.FormatUint(uint64(i.Fingerprint), 10)}
+	for _, v := range i.Hashes {
+		switch v.Algorithm {
+		case hashAlgoSHA1:
+			hashes["sha1"] = v.Value
+		case hashAlgoMD5:
+			hashes["md5"] = v.Value
+		}
+	}
+	return hashes
+}
+
+// cfFileDownload returns the download of a CurseForge file, recording all of its hashes and
+// its size
+func cfFileDownload(fileInfo CfModFileInfo) core.ModDownload {
+	hash, hashFormat := fileInfo.GetBestHash()
+	download := core.ModDownload{
+		HashFormat: hashFormat,
+		Hash:       hash,
+		Mode:       core.ModeCF,
+		Size:       fileInfo.Length,
+	}
+	download.SetHashes(fileInfo.GetHashes())
+	return download
+}
+
 func (c *cfApiClient) GetFileInfo(modID uint32, fileID uint32) (CfModFileInfo, error) {
 	var infoRes struct {
 		Data CfModFileInfo `json:"data"`
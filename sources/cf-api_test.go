@@ -90,6 +90,25 @@ func TestCfModFileInfo_GetBestHash(t *testing.T) {
 	})
 }
 
+func TestCfFileDownload(t *testing.T) {
+	info := CfModFileInfo{
+		Fingerprint: 12345,
+		Length:      4096,
+		Hashes: []cfFileInfoHash{
+			{Algorithm: hashAlgoSHA1, Value: "sha1hash"},
+			{Algorithm: hashAlgoMD5, Value: "md5hash"},
+		},
+	}
+	assert.Equal(t, map[string]string{"sha1": "sha1hash", "md5": "md5hash", "murmur2": "12345"}, info.GetHashes())
+
+	download := cfFileDownload(info)
+	assert.Equal(t, core.ModeCF, download.Mode)
+	assert.Equal(t, "sha1", download.HashFormat)
+	assert.Equal(t, "sha1hash", download.Hash)
+	assert.Equal(t, map[string]string{"md5": "md5hash", "murmur2": "12345"}, download.Hashes)
+	assert.Equal(t, uint64(4096), download.Size)
+}
+
 func TestCfApiClient_makeGet(t *testing.T) {
 	withTestCfApiKey(t)
 
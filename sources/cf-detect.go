@@ -113,7 +113,7 @@ func CurseforgeDetectMods(dir string) (*CfDetectResult, error) {
 
 	fmt.Println("Creating metadata files...")
 	for _, v := range res.ExactMatches {
-		mod, err := CurseforgeNewMod(modInfosMap[v.ID], v.File, false)
+		mod, err := CurseforgeNewMod(modInfosMap[v.ID], v.File, "", false)
 		if err != nil {
 			return nil, err
 		}
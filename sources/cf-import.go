@@ -1,6 +1,7 @@
 package sources
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 
@@ -115,8 +116,11 @@ func CurseforgeImportPack(packImport packinterop.ImportPackMetadata, packFile st
 			continue
 		}
 
-		mod, err := CurseforgeNewMod(modInfoValue, modFileInfoValue, v.OptionalDisabled)
-		if err != nil {
+		mod, err := CurseforgeNewMod(modInfoValue, modFileInfoValue, pack.GetDatapackFolder(), v.OptionalDisabled)
+		if errors.Is(err, errNoDatapackFolder) {
+			fmt.Printf("Skipped datapack \"%s\": %v\n", modInfoValue.Name, err)
+			continue
+		} else if err != nil {
 			return pack, nil, fmt.Errorf("Failed to save project \"%s\": %w", modInfoValue.Name, err)
 		}
 
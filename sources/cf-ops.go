@@ -98,7 +98,7 @@ func CurseforgeFindMissingDependencies(
 		}
 	}
 
-	mods, err := CreateCurseforgeDependencies(depsInstallable)
+	mods, err := CreateCurseforgeDependencies(depsInstallable, pack.GetDatapackFolder())
 	if err != nil {
 		return nil, err
 	}
@@ -167,11 +167,11 @@ func buildInstalledIdList(pack core.Pack) ([]uint32, error) {
 	return installedIDList, nil
 }
 
-func CreateCurseforgeDependencies(depsInstallable []CfInstallableDep) ([]*core.Mod, error) {
+func CreateCurseforgeDependencies(depsInstallable []CfInstallableDep, datapackFolder string) ([]*core.Mod, error) {
 	var mods []*core.Mod
 
 	for _, v := range depsInstallable {
-		mod, err := CurseforgeNewMod(v.CfModInfo, v.FileInfo, false)
+		mod, err := CurseforgeNewMod(v.CfModInfo, v.FileInfo, datapackFolder, false)
 		if err != nil {
 			return nil, err
 		}
@@ -200,9 +200,15 @@ func CurseforgeModInfoFromID(
 	return modInfo, fileInfo, nil
 }
 
-func CurseforgeNewMod(modInfo CfModInfo, fileInfo CfModFileInfo, optionalDisabled bool) (*core.Mod, error) {
+// CurseforgeNewMod creates the metadata for a CurseForge file. Datapacks are installed into
+// datapackFolder, and fail with an error if it is empty.
+func CurseforgeNewMod(modInfo CfModInfo, fileInfo CfModFileInfo, datapackFolder string, optionalDisabled bool) (*core.Mod, error) {
 	updateMap := make(core.ModUpdate)
-	var err error
+
+	modType, err := cfModFolder(modInfo, datapackFolder)
+	if err != nil {
+		return nil, err
+	}
 
 	updateMap["curseforge"], err = CfUpdateData{
 		ProjectID: modInfo.ID,
@@ -212,8 +218,6 @@ func CurseforgeNewMod(modInfo CfModInfo, fileInfo CfModFileInfo, optionalDisable
 		return nil, err
 	}
 
-	hash, hashFormat := fileInfo.GetBestHash()
-
 	var optional *core.ModOption
 	if optionalDisabled {
 		optional = &core.ModOption{
@@ -222,23 +226,21 @@ func CurseforgeNewMod(modInfo CfModInfo, fileInfo CfModFileInfo, optionalDisable
 		}
 	}
 
-	return core.NewMod(
+	mod := core.NewMod(
 		modInfo.Slug,
 		modInfo.Name,
 		fileInfo.FileName,
 		core.UniversalSide,
-		GetCfModType(modInfo.GameID, modInfo.ClassID, modInfo.PrimaryCategoryID),
+		modType,
 		"",
 		false,
 		false,
 		updateMap,
-		core.ModDownload{
-			HashFormat: hashFormat,
-			Hash:       hash,
-			Mode:       core.ModeCF,
-		},
+		cfFileDownload(fileInfo),
 		optional,
-	), nil
+	)
+	mod.Version = fileInfo.FriendlyName
+	return mod, nil
 }
 
 func CurseforgeModInfoFromSlug(
@@ -55,17 +55,19 @@ func TestCurseforgeNewMod(t *testing.T) {
 		PrimaryCategoryID: 0,
 	}
 	fileInfo := CfModFileInfo{
-		ID:          6789,
-		FileName:    "jei-1.20.1.jar",
-		Fingerprint: 111,
+		ID:           6789,
+		FileName:     "jei-1.20.1.jar",
+		FriendlyName: "JEI 15.2.0",
+		Fingerprint:  111,
 	}
 
 	t.Run("builds a mod with expected fields", func(t *testing.T) {
-		mod, err := CurseforgeNewMod(modInfo, fileInfo, false)
+		mod, err := CurseforgeNewMod(modInfo, fileInfo, "", false)
 		require.NoError(t, err)
 		assert.Equal(t, "jei", mod.Slug)
 		assert.Equal(t, "JEI", mod.Name)
 		assert.Equal(t, "jei-1.20.1.jar", mod.FileName)
+		assert.Equal(t, "JEI 15.2.0", mod.Version)
 		assert.Equal(t, "both", string(mod.Side))
 		assert.Equal(t, "mods", mod.ModType)
 		assert.Equal(t, "111", mod.Download.Hash)
@@ -74,12 +76,24 @@ func TestCurseforgeNewMod(t *testing.T) {
 	})
 
 	t.Run("optionalDisabled true sets Option", func(t *testing.T) {
-		mod, err := CurseforgeNewMod(modInfo, fileInfo, true)
+		mod, err := CurseforgeNewMod(modInfo, fileInfo, "", true)
 		require.NoError(t, err)
 		require.NotNil(t, mod.Option)
 		assert.True(t, mod.Option.Optional)
 		assert.False(t, mod.Option.Default)
 	})
+
+	t.Run("datapacks are installed into the datapack folder", func(t *testing.T) {
+		datapackInfo := modInfo
+		datapackInfo.ClassID = cfClassDatapacks
+
+		mod, err := CurseforgeNewMod(datapackInfo, fileInfo, "config/paxi/datapacks", false)
+		require.NoError(t, err)
+		assert.Equal(t, "config/paxi/datapacks", mod.ModType)
+
+		_, err = CurseforgeNewMod(datapackInfo, fileInfo, "", false)
+		assert.ErrorIs(t, err, errNoDatapackFolder)
+	})
 }
 
 func TestCreateCurseforgeDependencies(t *testing.T) {
@@ -94,7 +108,7 @@ func TestCreateCurseforgeDependencies(t *testing.T) {
 		},
 	}
 
-	mods, err := CreateCurseforgeDependencies(deps)
+	mods, err := CreateCurseforgeDependencies(deps, "")
 	require.NoError(t, err)
 	require.Len(t, mods, 2)
 	assert.Equal(t, "a", mods[0].Slug)
@@ -0,0 +1,314 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/leocov-dev/packwiz-nxt/config"
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// Options understood by the CurseForge Source, in core.SourceRequest.Options
+const (
+	// CfOptionCategory restricts slug lookups and searches to a category (slug, as stored in URLs)
+	CfOptionCategory = "category"
+	// CfOptionFileID installs a specific CurseForge file ID
+	CfOptionFileID = "file-id"
+)
+
+// cfSourceProject is the CurseForge-specific data stored in core.SourceProject.Data
+type cfSourceProject struct {
+	Info CfModInfo
+	// FileID is set when the identifier referenced a specific file
+	FileID uint32
+}
+
+// cfSourceVersion is the CurseForge-specific data stored in core.SourceVersion.Data
+type cfSourceVersion struct {
+	Info   CfModInfo
+	FileID uint32
+	// File may be nil for candidates only known from the project's file index
+	File *CfModFileInfo
+}
+
+type cfSource struct{}
+
+func (s cfSource) GetName() string {
+	return "curseforge"
+}
+
+// cfCategoryFilter maps a category slug to the class and category IDs used by the search API
+func cfCategoryFilter(category string) (categoryID uint32, classID uint32, err error) {
+	if category == "mc-mods" {
+		return 0, 6, nil
+	}
+	if category != "" {
+		return CurseforgeCategoryLookup(category)
+	}
+	return 0, 0, nil
+}
+
+func (s cfSource) Search(query string, req core.SourceRequest) ([]core.SourceProject, error) {
+	if _, err := config.DecodeCfApiKey(); err != nil {
+		return nil, fmt.Errorf("%w: %v", core.ErrSourceUnavailable, err)
+	}
+
+	mcVersions, err := req.Pack.GetSupportedMCVersions()
+	if err != nil {
+		return nil, err
+	}
+	categoryID, classID, err := cfCategoryFilter(req.Option(CfOptionCategory))
+	if err != nil {
+		return nil, err
+	}
+
+	return searchCurseforge(query, mcVersions, classID, categoryID, CfGetSearchLoaderType(req.Pack))
+}
+
+func (s cfSource) ResolveProject(identifier string, req core.SourceRequest) (core.SourceProject, error) {
+	data := cfSourceProject{}
+	if fileID := req.Option(CfOptionFileID); fileID != "" {
+		parsed, err := strconv.ParseUint(fileID, 10, 32)
+		if err != nil {
+			return core.SourceProject{}, fmt.Errorf("invalid file ID %s: %w", fileID, err)
+		}
+		data.FileID = uint32(parsed)
+	}
+
+	if modID, err := strconv.ParseUint(identifier, 10, 32); err == nil {
+		info, err := GetCurseforgeClient().GetModInfo(uint32(modID))
+		if err != nil {
+			return core.SourceProject{}, fmt.Errorf("failed to get project info: %w", err)
+		}
+		data.Info = info
+		return cfToSourceProject(data), nil
+	}
+
+	category, slug, fileID, err := CurseforgeParseUrl(identifier)
+	if err != nil {
+		return core.SourceProject{}, fmt.Errorf("failed to parse URL: %w", err)
+	}
+	if slug == "" {
+		return core.SourceProject{}, fmt.Errorf("not a CurseForge URL, slug or project ID: %s", identifier)
+	}
+	if category == "" {
+		category = req.Option(CfOptionCategory)
+	}
+	if fileID != 0 {
+		data.FileID = fileID
+	}
+
+	categoryID, classID, err := cfCategoryFilter(category)
+	if err != nil {
+		return core.SourceProject{}, err
+	}
+	results, err := GetCurseforgeClient().GetSearch("", slug, classID, categoryID, "", ModloaderTypeAny)
+	if err != nil {
+		return core.SourceProject{}, fmt.Errorf("failed to look up project: %w", err)
+	}
+	if len(results) == 0 {
+		return core.SourceProject{}, fmt.Errorf("%w: no CurseForge project with slug %s", core.ErrProjectNotFound, slug)
+	}
+
+	data.Info = results[0]
+	return cfToSourceProject(data), nil
+}
+
+func cfToSourceProject(data cfSourceProject) core.SourceProject {
+	return core.SourceProject{
+		Source:    "curseforge",
+		ID:        strconv.FormatUint(uint64(data.Info.ID), 10),
+		Slug:      data.Info.Slug,
+		Name:      data.Info.Name,
+		Summary:   data.Info.Summary,
+		Downloads: uint64(data.Info.DownloadCount),
+		Data:      data,
+	}
+}
+
+func cfSourceProjectData(project core.SourceProject) (cfSourceProject, error) {
+	data, ok := project.Data.(cfSourceProject)
+	if !ok {
+		return cfSourceProject{}, errors.New("not a CurseForge project")
+	}
+	return data, nil
+}
+
+func (s cfSource) ListVersions(project core.SourceProject, req core.SourceRequest) ([]core.SourceVersion, error) {
+	data, err := cfSourceProjectData(project)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.FileID != 0 {
+		// Explicitly requested files are installed even if they don't match the pack's versions/loaders
+		file, err := GetCurseforgeClient().GetFileInfo(data.Info.ID, data.FileID)
+		if err != nil {
+			return nil, err
+		}
+		return []core.SourceVersion{cfToSourceVersion(data.Info, file.ID, file.FileName, &file)}, nil
+	}
+
+	mcVersions, err := req.Pack.GetSupportedMCVersions()
+	if err != nil {
+		return nil, err
+	}
+	cfMcVersions := GetCurseforgeVersions(mcVersions)
+	packLoaders := cfPackLoadersFor(data.Info, req.Pack.GetCompatibleLoaders())
+
+	var versions []core.SourceVersion
+	seen := make(map[uint32]bool)
+	for _, v := range data.Info.LatestFiles {
+		_, loaderValid := CfFilterFileInfoLoaderIndex(packLoaders, v)
+		if core.HighestSliceIndex(mcVersions, v.GameVersions) < 0 || !loaderValid || seen[v.ID] {
+			continue
+		}
+		file := v
+		versions = append(versions, cfToSourceVersion(data.Info, v.ID, v.FileName, &file))
+		seen[v.ID] = true
+	}
+	for _, v := range data.Info.GameVersionLatestFiles {
+		_, loaderValid := CfFilterLoaderTypeIndex(packLoaders, v.Modloader)
+		if slices.Index(cfMcVersions, v.GameVersion) < 0 || !loaderValid || seen[v.ID] {
+			continue
+		}
+		versions = append(versions, cfToSourceVersion(data.Info, v.ID, v.Name, nil))
+		seen[v.ID] = true
+	}
+
+	if len(versions) == 0 {
+		return nil, errors.New("mod not available for the configured Minecraft version(s) (use the 'packwiz settings acceptable-versions' command to accept more) or loader")
+	}
+	return versions, nil
+}
+
+func cfToSourceVersion(info CfModInfo, fileID uint32, fileName string, file *CfModFileInfo) core.SourceVersion {
+	version := core.SourceVersion{
+		ID:       strconv.FormatUint(uint64(fileID), 10),
+		Version:  fileName,
+		FileName: fileName,
+		Data: cfSourceVersion{
+			Info:   info,
+			FileID: fileID,
+			File:   file,
+		},
+	}
+	if file != nil && file.FriendlyName != "" {
+		version.Version = file.FriendlyName
+	}
+	return version
+}
+
+func (s cfSource) SelectVersion(project core.SourceProject, versions []core.SourceVersion, req core.SourceRequest) (core.SourceVersion, error) {
+	data, err := cfSourceProjectData(project)
+	if err != nil {
+		return core.SourceVersion{}, err
+	}
+
+	candidates := make(map[uint32]int, len(versions))
+	for i, v := range versions {
+		versionData, ok := v.Data.(cfSourceVersion)
+		if !ok {
+			return core.SourceVersion{}, errors.New("not a CurseForge version")
+		}
+		candidates[versionData.FileID] = i
+	}
+
+	var fileID uint32
+	if len(versions) == 1 {
+		fileID = versions[0].Data.(cfSourceVersion).FileID
+	} else {
+		mcVersions, err := req.Pack.GetSupportedMCVersions()
+		if err != nil {
+			return core.SourceVersion{}, err
+		}
+
+		// Only consider the given candidates when picking the latest file
+		filtered := data.Info
+		filtered.LatestFiles = nil
+		for _, v := range data.Info.LatestFiles {
+			if _, ok := candidates[v.ID]; ok {
+				filtered.LatestFiles = append(filtered.LatestFiles, v)
+			}
+		}
+		filtered.GameVersionLatestFiles = nil
+		for _, v := range data.Info.GameVersionLatestFiles {
+			if _, ok := candidates[v.ID]; ok {
+				filtered.GameVersionLatestFiles = append(filtered.GameVersionLatestFiles, v)
+			}
+		}
+
+		fileID, _, _ = CfFindLatestFile(filtered, mcVersions, req.Pack.GetCompatibleLoaders())
+	}
+
+	i, ok := candidates[fileID]
+	if !ok {
+		return core.SourceVersion{}, errors.New("mod not available for the configured Minecraft version(s) (use the 'packwiz settings acceptable-versions' command to accept more) or loader")
+	}
+
+	selected := versions[i]
+	selectedData := selected.Data.(cfSourceVersion)
+	if selectedData.File == nil {
+		// Candidates from the file index don't include the full file info
+		file, err := GetCurseforgeClient().GetFileInfo(data.Info.ID, fileID)
+		if err != nil {
+			return core.SourceVersion{}, err
+		}
+		selected = cfToSourceVersion(data.Info, file.ID, file.FileName, &file)
+	}
+	return selected, nil
+}
+
+func cfSourceVersionFile(version core.SourceVersion) (cfSourceVersion, error) {
+	data, ok := version.Data.(cfSourceVersion)
+	if !ok {
+		return cfSourceVersion{}, errors.New("not a CurseForge version")
+	}
+	if data.File == nil {
+		file, err := GetCurseforgeClient().GetFileInfo(data.Info.ID, data.FileID)
+		if err != nil {
+			return cfSourceVersion{}, err
+		}
+		data.File = &file
+	}
+	return data, nil
+}
+
+func (s cfSource) NewMod(_ core.SourceProject, version core.SourceVersion, req core.SourceRequest) (*core.Mod, error) {
+	data, err := cfSourceVersionFile(version)
+	if err != nil {
+		return nil, err
+	}
+
+	datapackFolder := req.Pack.GetDatapackFolder()
+	if req.MetaFolder != "" {
+		datapackFolder = req.MetaFolder
+	}
+	mod, err := CurseforgeNewMod(data.Info, *data.File, datapackFolder, false)
+	if err != nil {
+		return nil, err
+	}
+	if req.MetaFolder != "" {
+		mod.ModType = req.MetaFolder
+	}
+	return mod, nil
+}
+
+func (s cfSource) FindMissingDependencies(version core.SourceVersion, req core.SourceRequest) ([]*core.Mod, error) {
+	data, err := cfSourceVersionFile(version)
+	if err != nil {
+		return nil, err
+	}
+	if len(data.File.Dependencies) == 0 {
+		return nil, nil
+	}
+
+	primaryMCVersion, err := req.Pack.GetMCVersion()
+	if err != nil {
+		return nil, err
+	}
+	return CurseforgeFindMissingDependencies(req.Pack, *data.File, primaryMCVersion)
+}
@@ -0,0 +1,86 @@
+package sources
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/config"
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+const cfSourceTestInfo = `{"id":238222,"name":"Just Enough Items","slug":"jei","summary":"items","downloadCount":1000,"latestFiles":[` +
+	`{"id":2,"fileName":"jei-new.jar","gameVersions":["1.20.1","Fabric"]},` +
+	`{"id":3,"fileName":"jei-other.jar","gameVersions":["1.19.2","Fabric"]}]}`
+
+func TestCfSource(t *testing.T) {
+	withCfClient(t, newTestHTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/mods/238222":
+			_, _ = w.Write([]byte(`{"data":` + cfSourceTestInfo + `}`))
+		case "/v1/mods/search":
+			if r.URL.Query().Get("slug") == "missing" {
+				_, _ = w.Write([]byte(`{"data":[]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[` + cfSourceTestInfo + `]}`))
+		case "/v1/mods/238222/files/2":
+			_, _ = w.Write([]byte(`{"data":{"id":2,"modId":238222,"fileName":"jei-new.jar","gameVersions":["1.20.1","Fabric"]}}`))
+		case "/v1/mods/238222/files/3":
+			_, _ = w.Write([]byte(`{"data":{"id":3,"modId":238222,"fileName":"jei-other.jar","gameVersions":["1.19.2","Fabric"]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})))
+
+	src := cfSource{}
+	req := core.SourceRequest{Pack: core.Pack{Versions: map[string]string{"minecraft": "1.20.1", "fabric": "0.15.0"}}}
+
+	t.Run("resolves a project ID and installs a compatible file", func(t *testing.T) {
+		mod, version, err := core.NewModFromSource(src, "238222", req)
+		require.NoError(t, err)
+		assert.Equal(t, "2", version.ID)
+		assert.Equal(t, "jei-new.jar", mod.FileName)
+		assert.Equal(t, "jei", mod.Slug)
+		assert.Equal(t, uint32(238222), mod.Update["curseforge"]["project-id"])
+		assert.Equal(t, uint32(2), mod.Update["curseforge"]["file-id"])
+	})
+
+	t.Run("resolves a URL slug", func(t *testing.T) {
+		project, err := src.ResolveProject("https://www.curseforge.com/minecraft/mc-mods/jei", req)
+		require.NoError(t, err)
+		assert.Equal(t, "238222", project.ID)
+		assert.Equal(t, "Just Enough Items", project.Name)
+		assert.Equal(t, uint64(1000), project.Downloads)
+	})
+
+	t.Run("unknown slugs aren't found", func(t *testing.T) {
+		_, err := src.ResolveProject("missing", req)
+		assert.ErrorIs(t, err, core.ErrProjectNotFound)
+	})
+
+	t.Run("file ID option installs that file regardless of versions", func(t *testing.T) {
+		fileReq := req
+		fileReq.Options = map[string]string{CfOptionFileID: "3"}
+		mod, _, err := core.NewModFromSource(src, "238222", fileReq)
+		require.NoError(t, err)
+		assert.Equal(t, "jei-other.jar", mod.FileName)
+	})
+
+	t.Run("meta folder overrides the mod type", func(t *testing.T) {
+		folderReq := req
+		folderReq.MetaFolder = "extra"
+		mod, _, err := core.NewModFromSource(src, "238222", folderReq)
+		require.NoError(t, err)
+		assert.Equal(t, "extra", mod.ModType)
+	})
+
+	t.Run("search is unavailable without an API key", func(t *testing.T) {
+		config.SetCurseforgeApiKey("")
+		_, err := src.Search("jei", req)
+		assert.True(t, errors.Is(err, core.ErrSourceUnavailable))
+	})
+}
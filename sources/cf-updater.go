@@ -18,12 +18,13 @@ func init() {
 	RegisterCurseforge(core.DefaultRegistry)
 }
 
-// RegisterCurseforge registers the CurseForge Updater/MetaDownloader on reg. Library
+// RegisterCurseforge registers the CurseForge Updater/MetaDownloader/Source on reg. Library
 // consumers building an isolated *core.Registry (instead of relying on
 // core.DefaultRegistry) should call this - or sources.RegisterAll - explicitly.
 func RegisterCurseforge(reg *core.Registry) {
 	reg.AddUpdater(CfUpdater{})
 	reg.AddMetaDownloader("curseforge", CfDownloader{})
+	reg.AddSource(cfSource{})
 }
 
 var snapshotVersionRegex = regexp.MustCompile(`(?:Snapshot )?(\d+)w0?(0|[1-9]\d*)([a-z])`)
@@ -144,6 +145,14 @@ func CurseforgeParseUrl(url string) (category string, slug string, fileID uint32
 	return
 }
 
+// cfClassDatapacks is the class of Minecraft datapack projects, which are installed into the
+// pack's datapack folder rather than a fixed folder
+const cfClassDatapacks = 6945
+
+// cfLoaderAgnosticClasses are the classes of Minecraft projects whose files don't depend on a
+// mod loader, so aren't filtered by the pack's loaders
+var cfLoaderAgnosticClasses = []uint32{12, 17, cfClassDatapacks}
+
 var defaultFolders = map[uint32]map[uint32]string{
 	minecraftGameId: { // Minecraft
 		5:  "plugins", // Bukkit Plugins
@@ -164,6 +173,27 @@ func GetCfModType(gameID, classID, categoryID uint32) string {
 	return "unknown"
 }
 
+// cfModFolder returns the folder a project is installed into: datapacks go into datapackFolder,
+// and fail if it is empty
+func cfModFolder(modInfo CfModInfo, datapackFolder string) (string, error) {
+	if modInfo.GameID == minecraftGameId && modInfo.ClassID == cfClassDatapacks {
+		if datapackFolder == "" {
+			return "", errNoDatapackFolder
+		}
+		return datapackFolder, nil
+	}
+	return GetCfModType(modInfo.GameID, modInfo.ClassID, modInfo.PrimaryCategoryID), nil
+}
+
+// cfPackLoadersFor returns the loaders the files of a project are filtered by: none for
+// loader-agnostic projects such as datapacks and resource packs
+func cfPackLoadersFor(modInfo CfModInfo, packLoaders []string) []string {
+	if modInfo.GameID == minecraftGameId && slices.Contains(cfLoaderAgnosticClasses, modInfo.ClassID) {
+		return nil
+	}
+	return packLoaders
+}
+
 func CfGetSearchLoaderType(pack core.Pack) ModloaderType {
 	dependencies := pack.Versions
 
@@ -256,6 +286,7 @@ func CfFindLatestFile(modInfoData CfModInfo, mcVersions []string, packLoaders []
 	cfMcVersions := GetCurseforgeVersions(mcVersions)
 	bestMcVer := -1
 	bestLoaderType := ModloaderTypeAny
+	packLoaders = cfPackLoadersFor(modInfoData, packLoaders)
 
 	// For snapshots, curseforge doesn't put them in GameVersionLatestFiles
 	for _, v := range modInfoData.LatestFiles {
@@ -287,6 +318,130 @@ func CfFindLatestFile(modInfoData CfModInfo, mcVersions []string, packLoaders []
 	return
 }
 
+// cfFilterAcceptedFiles returns a copy of modInfoData without the latest files that the mod's
+// version constraint doesn't allow, compared by file name. Only the latest files of each
+// Minecraft version and loader are known, so older files allowed by the constraint can't
+// be found.
+func cfFilterAcceptedFiles(modInfoData CfModInfo, mod *core.Mod, mcVersions []string) (CfModInfo, error) {
+	latestFiles, err := core.FilterAcceptedVersions(mod, mcVersions, modInfoData.LatestFiles, func(f CfModFileInfo) string {
+		return f.FileName
+	})
+	if err != nil {
+		return CfModInfo{}, err
+	}
+	filtered := modInfoData
+	filtered.LatestFiles = latestFiles
+	filtered.GameVersionLatestFiles = nil
+	for _, v := range modInfoData.GameVersionLatestFiles {
+		ok, err := mod.AcceptsVersion(v.Name, mcVersions)
+		if err != nil {
+			return CfModInfo{}, err
+		}
+		if ok {
+			filtered.GameVersionLatestFiles = append(filtered.GameVersionLatestFiles, v)
+		}
+	}
+	return filtered, nil
+}
+
+// cfWithoutFile returns a copy of modInfoData without the latest file with the given ID
+func cfWithoutFile(modInfoData CfModInfo, fileID uint32) CfModInfo {
+	filtered := modInfoData
+	filtered.LatestFiles = nil
+	for _, v := range modInfoData.LatestFiles {
+		if v.ID != fileID {
+			filtered.LatestFiles = append(filtered.LatestFiles, v)
+		}
+	}
+	filtered.GameVersionLatestFiles = nil
+	for _, v := range modInfoData.GameVersionLatestFiles {
+		if v.ID != fileID {
+			filtered.GameVersionLatestFiles = append(filtered.GameVersionLatestFiles, v)
+		}
+	}
+	return filtered
+}
+
+// cfFileInfo returns the info of a file of the project, from its latest files if it is one of
+// them, or from the API otherwise
+func cfFileInfo(modInfoData CfModInfo, fileID uint32) (*CfModFileInfo, error) {
+	for _, v := range modInfoData.LatestFiles {
+		if v.ID == fileID {
+			return &v, nil
+		}
+	}
+	info, err := GetCurseforgeClient().GetFileInfo(modInfoData.ID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// cfSelectFile finds the latest file of a project allowed by the mod's version constraint and
+// published before the minimum release age, without downgrading from the installed file
+// (installedID) if newer files are too recent. The returned check records the newer files that were held
+// back; the returned state has a zero file ID if no file is allowed.
+func cfSelectFile(modInfoData CfModInfo, installedID uint32, mod *core.Mod, pack core.Pack, mcVersions []string, packLoaders []string) (cachedStateStore, string, core.UpdateCheck, error) {
+	var check core.UpdateCheck
+	fileID, fileInfoData, fileName := CfFindLatestFile(modInfoData, mcVersions, packLoaders)
+	if fileID == 0 {
+		return cachedStateStore{}, "", check, nil
+	}
+
+	ok, err := mod.AcceptsVersion(fileName, mcVersions)
+	if err != nil {
+		return cachedStateStore{}, "", check, err
+	}
+	if !ok {
+		check.HeldBack = fileName
+		modInfoData, err = cfFilterAcceptedFiles(modInfoData, mod, mcVersions)
+		if err != nil {
+			return cachedStateStore{}, "", check, err
+		}
+		fileID, fileInfoData, fileName = CfFindLatestFile(modInfoData, mcVersions, packLoaders)
+	}
+
+	minAge, err := pack.MinReleaseAgeFor(mod)
+	if err != nil {
+		return cachedStateStore{}, "", check, err
+	}
+	allFiles := modInfoData
+	var installedInfo *CfModFileInfo
+	// Files from GameVersionLatestFiles have no date, so are looked up before being compared
+	for minAge > 0 && fileID != 0 && fileID != installedID {
+		if fileInfoData == nil {
+			info, err := GetCurseforgeClient().GetFileInfo(modInfoData.ID, fileID)
+			if err != nil {
+				return cachedStateStore{}, "", check, err
+			}
+			fileInfoData = &info
+		}
+		// Once a newer file has been held back, the remaining ones may be older than the
+		// installed file, which is kept rather than downgraded
+		if check.TooRecent != "" && installedID != 0 {
+			if installedInfo == nil {
+				installedInfo, err = cfFileInfo(allFiles, installedID)
+				if err != nil {
+					return cachedStateStore{}, "", check, err
+				}
+			}
+			if !fileInfoData.Date.After(installedInfo.Date) {
+				fileID, fileInfoData, fileName = installedID, installedInfo, installedInfo.FileName
+				break
+			}
+		}
+		if core.ReleaseOldEnough(fileInfoData.Date, minAge) {
+			break
+		}
+		if check.TooRecent == "" {
+			check.TooRecent = fileName
+		}
+		modInfoData = cfWithoutFile(modInfoData, fileID)
+		fileID, fileInfoData, fileName = CfFindLatestFile(modInfoData, mcVersions, packLoaders)
+	}
+	return cachedStateStore{modInfoData, fileID, fileInfoData}, fileName, check, nil
+}
+
 type CfUpdateData struct {
 	ProjectID uint32 `mapstructure:"project-id"`
 	FileID    uint32 `mapstructure:"file-id"`
@@ -361,19 +516,23 @@ func (u CfUpdater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateC
 		}
 		project := projects[i]
 
-		fileID, fileInfoData, fileName := CfFindLatestFile(modInfos[i], mcVersions, packLoaders)
-		if fileID != project.FileID && fileID != 0 {
+		state, fileName, check, err := cfSelectFile(modInfos[i], project.FileID, m, pack, mcVersions, packLoaders)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: err}
+			continue
+		}
+		if state.fileID != project.FileID && state.fileID != 0 {
 			// Update (or downgrade, if changing to an older version) available!
-			results[i] = core.UpdateCheck{
-				UpdateAvailable: true,
-				UpdateString:    m.FileName + " -> " + fileName,
-				CachedState:     cachedStateStore{modInfos[i], fileID, fileInfoData},
+			check.UpdateAvailable = true
+			newVersion := ""
+			if state.fileInfo != nil {
+				newVersion = state.fileInfo.FriendlyName
 			}
-		} else {
-			// Could not find a file, too old, or up to date: no update available
-			results[i] = core.UpdateCheck{UpdateAvailable: false}
-			continue
+			check.UpdateString = m.DescribeUpdate(newVersion, fileName)
+			check.CachedState = state
 		}
+		// Otherwise, could not find a file, too old, or up to date: no update available
+		results[i] = check
 	}
 	return results, nil
 }
@@ -395,13 +554,9 @@ func (u CfUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
 		}
 
 		m.FileName = fileInfoData.FileName
+		m.Version = fileInfoData.FriendlyName
 		m.Name = modState.Name
-		hash, hashFormat := fileInfoData.GetBestHash()
-		m.Download = core.ModDownload{
-			HashFormat: hashFormat,
-			Hash:       hash,
-			Mode:       core.ModeCF,
-		}
+		m.Download = cfFileDownload(fileInfoData)
 
 		m.Update["curseforge"]["project-id"] = modState.ID
 		m.Update["curseforge"]["file-id"] = fileInfoData.ID
@@ -1,9 +1,12 @@
 package sources
 
 import (
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/leocov-dev/packwiz-nxt/core"
 )
@@ -235,6 +238,23 @@ func TestCfFindLatestFile(t *testing.T) {
 		assert.Equal(t, "quilt.jar", fileName)
 	})
 
+	t.Run("datapacks aren't filtered by the pack's loaders", func(t *testing.T) {
+		modInfo := CfModInfo{
+			GameID:  minecraftGameId,
+			ClassID: cfClassDatapacks,
+			LatestFiles: []CfModFileInfo{
+				{ID: 4, FileName: "datapack.zip", GameVersions: []string{"1.20"}},
+			},
+		}
+		fileID, _, fileName := CfFindLatestFile(modInfo, []string{"1.20"}, []string{"fabric"})
+		assert.Equal(t, uint32(4), fileID)
+		assert.Equal(t, "datapack.zip", fileName)
+
+		modInfo.ClassID = 6
+		fileID, _, _ = CfFindLatestFile(modInfo, []string{"1.20"}, []string{"fabric"})
+		assert.Equal(t, uint32(0), fileID, "mods without a matching loader are still filtered")
+	})
+
 	t.Run("falls back to GameVersionLatestFiles when LatestFiles is empty", func(t *testing.T) {
 		modInfo := CfModInfo{
 			GameVersionLatestFiles: []struct {
@@ -265,3 +285,91 @@ func TestCfFindLatestFile(t *testing.T) {
 		assert.Equal(t, "", fileName)
 	})
 }
+
+func TestCfFilterAcceptedFiles(t *testing.T) {
+	modInfo := CfModInfo{
+		LatestFiles: []CfModFileInfo{
+			{ID: 1, FileName: "mod-1.20.1-4.2.1.jar", GameVersions: []string{"1.20.1"}},
+			{ID: 2, FileName: "mod-1.20.1-5.0.0.jar", GameVersions: []string{"1.20.1"}},
+		},
+		GameVersionLatestFiles: []struct {
+			GameVersion string        `json:"gameVersion"`
+			ID          uint32        `json:"fileId"`
+			Name        string        `json:"filename"`
+			FileType    fileType      `json:"releaseType"`
+			Modloader   ModloaderType `json:"modLoader"`
+		}{
+			{ID: 2, Name: "mod-1.20.1-5.0.0.jar", GameVersion: "1.20.1", Modloader: ModloaderTypeAny},
+		},
+	}
+	mcVersions := []string{"1.20.1"}
+
+	mod := &core.Mod{Name: "Test Mod", VersionConstraint: "<5"}
+	filtered, err := cfFilterAcceptedFiles(modInfo, mod, mcVersions)
+	require.NoError(t, err)
+	assert.Len(t, modInfo.LatestFiles, 2, "the original mod info is left unchanged")
+	assert.Empty(t, filtered.GameVersionLatestFiles)
+
+	fileID, _, fileName := CfFindLatestFile(filtered, mcVersions, nil)
+	assert.Equal(t, uint32(1), fileID)
+	assert.Equal(t, "mod-1.20.1-4.2.1.jar", fileName)
+
+	mod.VersionConstraint = ">="
+	_, err = cfFilterAcceptedFiles(modInfo, mod, mcVersions)
+	assert.Error(t, err)
+}
+
+func TestCfSelectFile(t *testing.T) {
+	modInfo := CfModInfo{
+		ID: 42,
+		LatestFiles: []CfModFileInfo{
+			{ID: 1, FileName: "mod-1.20.1-4.2.1.jar", GameVersions: []string{"1.20.1"}, Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{ID: 2, FileName: "mod-1.20.1-4.3.0.jar", GameVersions: []string{"1.20.1"}, Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+			{ID: 3, FileName: "mod-1.20.1-5.0.0.jar", GameVersions: []string{"1.20.1"}, Date: time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	mcVersions := []string{"1.20.1"}
+	pack := core.Pack{
+		Versions: map[string]string{"minecraft": "1.20.1"},
+		Options:  map[string]interface{}{core.MinReleaseAgeOption: "3d"},
+	}
+
+	t.Run("recent files are held back", func(t *testing.T) {
+		state, fileName, check, err := cfSelectFile(modInfo, 1, &core.Mod{Name: "Test"}, pack, mcVersions, nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2), state.fileID)
+		assert.Equal(t, "mod-1.20.1-4.3.0.jar", fileName)
+		assert.Equal(t, "mod-1.20.1-5.0.0.jar", check.TooRecent)
+		assert.Empty(t, check.HeldBack)
+	})
+
+	t.Run("recent installed files aren't downgraded", func(t *testing.T) {
+		state, _, _, err := cfSelectFile(modInfo, 3, &core.Mod{Name: "Test"}, pack, mcVersions, nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(3), state.fileID)
+	})
+
+	t.Run("superseded installed files aren't downgraded", func(t *testing.T) {
+		// The installed file (4) was superseded by a too-recent file (3), so is no longer one of
+		// the latest files; the remaining ones (1, 2) are older than it
+		withCfClient(t, newTestHTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/mods/42/files/4", r.URL.Path)
+			_, _ = w.Write([]byte(`{"data":{"id":4,"fileName":"mod-1.20.1-4.4.0.jar","fileDate":"2024-03-01T00:00:00Z"}}`))
+		})))
+
+		state, fileName, check, err := cfSelectFile(modInfo, 4, &core.Mod{Name: "Test"}, pack, mcVersions, nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(4), state.fileID)
+		assert.Equal(t, "mod-1.20.1-4.4.0.jar", fileName)
+		assert.Equal(t, "mod-1.20.1-5.0.0.jar", check.TooRecent)
+	})
+
+	t.Run("constraint and release age are reported separately", func(t *testing.T) {
+		mod := &core.Mod{Name: "Test", VersionConstraint: "~4.2", MinReleaseAge: "0"}
+		state, _, check, err := cfSelectFile(modInfo, 1, mod, pack, mcVersions, nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1), state.fileID)
+		assert.Equal(t, "mod-1.20.1-5.0.0.jar", check.HeldBack)
+		assert.Empty(t, check.TooRecent)
+	})
+}
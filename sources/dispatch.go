@@ -0,0 +1,132 @@
+package sources
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Provider identifies which installer an argument to `packwiz add` should be routed to.
+type Provider string
+
+const (
+	ProviderCurseforge Provider = "curseforge"
+	ProviderModrinth   Provider = "modrinth"
+	ProviderGithub     Provider = "github"
+	ProviderGitea      Provider = "gitea"
+	ProviderHangar     Provider = "hangar"
+	ProviderURL        Provider = "url"
+	// ProviderSearch means the argument wasn't recognised as a URL or identifier,
+	// and should be used as a free text search term across all providers.
+	ProviderSearch Provider = "search"
+)
+
+// DetectedTarget is the result of classifying a single `packwiz add` argument.
+// Only the fields relevant to Provider are populated.
+type DetectedTarget struct {
+	Provider Provider
+	Input    string
+
+	// Slug is the project slug (CurseForge/Modrinth/Hangar) or the owner/repo pair (GitHub/Gitea)
+	Slug string
+	// Category is the CurseForge category parsed from the URL, if any
+	Category string
+	// FileID is the CurseForge file ID parsed from the URL, if any
+	FileID uint32
+	// Version, VersionID and Filename are parsed from Modrinth URLs, if present
+	Version   string
+	VersionID string
+	Filename  string
+	// FromBareSlug is true when the input was a bare identifier rather than a URL,
+	// in which case it may not exist on the provider it was routed to
+	FromBareSlug bool
+}
+
+// ghSlugRegex matches a bare GitHub "owner/repo" identifier
+var ghSlugRegex = regexp.MustCompile(`^[a-zA-Z0-9-]+/[a-zA-Z0-9._-]+$`)
+
+// DetectProvider classifies input as a CurseForge URL, Modrinth URL or slug, GitHub
+// repository URL or owner/repo slug, Codeberg repository URL, Hangar project URL, or a plain
+// download URL.
+// Anything else is treated as a free text search term. Repositories on other Gitea/Forgejo
+// hosts can't be told apart from download URLs, so they must be added with `packwiz gitea add`.
+func DetectProvider(input string) (DetectedTarget, error) {
+	input = strings.TrimSpace(input)
+	target := DetectedTarget{Input: input}
+
+	if input == "" {
+		return target, errors.New("empty project identifier")
+	}
+
+	if strings.ContainsAny(input, " \t") {
+		target.Provider = ProviderSearch
+		return target, nil
+	}
+
+	if u, err := url.Parse(input); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		host := strings.ToLower(u.Hostname())
+		switch {
+		case host == "curseforge.com" || strings.HasSuffix(host, ".curseforge.com"):
+			category, slug, fileID, err := CurseforgeParseUrl(input)
+			if err != nil {
+				return target, err
+			}
+			if slug == "" {
+				return target, errors.New("unrecognised CurseForge URL: " + input)
+			}
+			target.Provider = ProviderCurseforge
+			target.Category = category
+			target.Slug = slug
+			target.FileID = fileID
+		case host == "forgecdn.net" || strings.HasSuffix(host, ".forgecdn.net"):
+			return target, errors.New("CurseForge CDN links can't be used to add a project; use the project page URL instead")
+		case host == "modrinth.com" || strings.HasSuffix(host, ".modrinth.com"):
+			_, err := ParseModrinthSlugOrUrl(input, &target.Slug, &target.Version, &target.VersionID, &target.Filename)
+			if err != nil {
+				return target, err
+			}
+			if target.Slug == "" && target.VersionID == "" {
+				return target, errors.New("unrecognised Modrinth URL: " + input)
+			}
+			target.Provider = ProviderModrinth
+		case host == "github.com" || host == "www.github.com":
+			matches := GithubRegex.FindStringSubmatch(input)
+			if len(matches) != 2 {
+				return target, errors.New("unrecognised GitHub URL, expected a repository URL: " + input)
+			}
+			target.Provider = ProviderGithub
+			target.Slug = matches[1]
+		case host == "codeberg.org" && ghSlugRegex.MatchString(strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")):
+			target.Provider = ProviderGitea
+			target.Slug = strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+		case host == "hangar.papermc.io":
+			slug, err := HangarParseSlugOrUrl(input)
+			if err != nil {
+				return target, errors.New("unrecognised Hangar URL, expected a project URL: " + input)
+			}
+			target.Provider = ProviderHangar
+			target.Slug = slug
+		default:
+			target.Provider = ProviderURL
+		}
+		return target, nil
+	}
+
+	if ghSlugRegex.MatchString(input) {
+		target.Provider = ProviderGithub
+		target.Slug = input
+		target.FromBareSlug = true
+		return target, nil
+	}
+
+	if slug := ParseAsModrinthSlug(input); slug != "" {
+		target.Provider = ProviderModrinth
+		target.Slug = slug
+		target.FromBareSlug = true
+		return target, nil
+	}
+
+	target.Provider = ProviderSearch
+	return target, nil
+}
@@ -0,0 +1,116 @@
+package sources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectProvider(t *testing.T) {
+	t.Run("CurseForge project URL with file ID", func(t *testing.T) {
+		target, err := DetectProvider("https://www.curseforge.com/minecraft/mc-mods/jei/files/4593548")
+		require.NoError(t, err)
+		assert.Equal(t, ProviderCurseforge, target.Provider)
+		assert.Equal(t, "mc-mods", target.Category)
+		assert.Equal(t, "jei", target.Slug)
+		assert.Equal(t, uint32(4593548), target.FileID)
+	})
+
+	t.Run("CurseForge CDN URL is rejected", func(t *testing.T) {
+		_, err := DetectProvider("https://edge.forgecdn.net/files/4593/548/jei.jar")
+		assert.Error(t, err)
+	})
+
+	t.Run("Modrinth project URL with version", func(t *testing.T) {
+		target, err := DetectProvider("https://modrinth.com/mod/sodium/version/mc1.20.1-0.5.3")
+		require.NoError(t, err)
+		assert.Equal(t, ProviderModrinth, target.Provider)
+		assert.Equal(t, "sodium", target.Slug)
+		assert.Equal(t, "mc1.20.1-0.5.3", target.Version)
+		assert.False(t, target.FromBareSlug)
+	})
+
+	t.Run("Modrinth CDN URL", func(t *testing.T) {
+		target, err := DetectProvider("https://cdn.modrinth.com/data/AANobbMI/versions/abc123/sodium.jar")
+		require.NoError(t, err)
+		assert.Equal(t, ProviderModrinth, target.Provider)
+		assert.Equal(t, "abc123", target.VersionID)
+		assert.Equal(t, "sodium.jar", target.Filename)
+	})
+
+	t.Run("Modrinth URL with unknown category errors", func(t *testing.T) {
+		_, err := DetectProvider("https://modrinth.com/widget/sodium")
+		assert.Error(t, err)
+	})
+
+	t.Run("GitHub repository URL", func(t *testing.T) {
+		target, err := DetectProvider("https://github.com/CaffeineMC/sodium/releases")
+		require.NoError(t, err)
+		assert.Equal(t, ProviderGithub, target.Provider)
+		assert.Equal(t, "CaffeineMC/sodium", target.Slug)
+	})
+
+	t.Run("GitHub owner/repo slug", func(t *testing.T) {
+		target, err := DetectProvider("CaffeineMC/sodium")
+		require.NoError(t, err)
+		assert.Equal(t, ProviderGithub, target.Provider)
+		assert.Equal(t, "CaffeineMC/sodium", target.Slug)
+		assert.True(t, target.FromBareSlug)
+	})
+
+	t.Run("Codeberg repository URL", func(t *testing.T) {
+		target, err := DetectProvider("https://codeberg.org/jmansfield/go-modrinth")
+		require.NoError(t, err)
+		assert.Equal(t, ProviderGitea, target.Provider)
+		assert.Equal(t, "jmansfield/go-modrinth", target.Slug)
+	})
+
+	t.Run("Codeberg download URL", func(t *testing.T) {
+		target, err := DetectProvider("https://codeberg.org/owner/repo/releases/download/v1.0/mod.jar")
+		require.NoError(t, err)
+		assert.Equal(t, ProviderURL, target.Provider)
+	})
+
+	t.Run("Hangar project URL", func(t *testing.T) {
+		target, err := DetectProvider("https://hangar.papermc.io/ViaVersion/ViaBackwards")
+		require.NoError(t, err)
+		assert.Equal(t, ProviderHangar, target.Provider)
+		assert.Equal(t, "ViaBackwards", target.Slug)
+
+		_, err = DetectProvider("https://hangar.papermc.io/ViaVersion")
+		assert.Error(t, err)
+	})
+
+	t.Run("plain download URL", func(t *testing.T) {
+		target, err := DetectProvider("https://example.com/files/some-mod-1.0.jar")
+		require.NoError(t, err)
+		assert.Equal(t, ProviderURL, target.Provider)
+		assert.Equal(t, "https://example.com/files/some-mod-1.0.jar", target.Input)
+	})
+
+	t.Run("bare slug is routed to Modrinth", func(t *testing.T) {
+		target, err := DetectProvider("sodium")
+		require.NoError(t, err)
+		assert.Equal(t, ProviderModrinth, target.Provider)
+		assert.Equal(t, "sodium", target.Slug)
+		assert.True(t, target.FromBareSlug)
+	})
+
+	t.Run("free text is a search", func(t *testing.T) {
+		target, err := DetectProvider("just enough items")
+		require.NoError(t, err)
+		assert.Equal(t, ProviderSearch, target.Provider)
+	})
+
+	t.Run("short term is a search", func(t *testing.T) {
+		target, err := DetectProvider("ae")
+		require.NoError(t, err)
+		assert.Equal(t, ProviderSearch, target.Provider)
+	})
+
+	t.Run("empty input errors", func(t *testing.T) {
+		_, err := DetectProvider("  ")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,49 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// ErrNotExportable is wrapped by CheckExportable's errors
+var ErrNotExportable = errors.New("cannot be exported")
+
+// CheckExportable returns an error wrapping ErrNotExportable if mod must be left out of
+// exported Modrinth and CurseForge packs, even as an override. This is the case for
+// GitHub Actions artifacts: they are development builds that need a GitHub token to
+// download, and usually may not be redistributed.
+func CheckExportable(mod *core.Mod) error {
+	if mod.Download.Mode == core.ModeGitHubActions {
+		return fmt.Errorf("%s is a GitHub Actions artifact: %w", mod.Name, ErrNotExportable)
+	}
+	return nil
+}
+
+// FilterExportable returns the mods that pass CheckExportable, and an error for each mod
+// that doesn't
+func FilterExportable(mods []*core.Mod) ([]*core.Mod, []error) {
+	exportable := make([]*core.Mod, 0, len(mods))
+	var skipped []error
+	for _, mod := range mods {
+		if err := CheckExportable(mod); err != nil {
+			skipped = append(skipped, err)
+			continue
+		}
+		exportable = append(exportable, mod)
+	}
+	return exportable, skipped
+}
+
+// CfExportAsOverride reports whether mod is stored in the overrides of an exported CurseForge
+// pack instead of being referenced in its manifest. This is the case for files without
+// CurseForge metadata, and for datapacks: the CurseForge launcher installs manifest files into
+// a folder picked by project type, rather than the pack's datapack folder.
+func CfExportAsOverride(pack *core.Pack, mod *core.Mod) bool {
+	var data CfUpdateData
+	if err := mod.DecodeNamedModSourceData("curseforge", &data); err != nil {
+		return true
+	}
+	return pack.IsDatapack(mod)
+}
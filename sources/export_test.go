@@ -0,0 +1,38 @@
+package sources
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func TestFilterExportable(t *testing.T) {
+	release := &core.Mod{Name: "Release", Download: core.ModDownload{URL: "https://example.com/mod.jar"}}
+	curseforge := &core.Mod{Name: "CurseForge", Download: core.ModDownload{Mode: core.ModeCF}}
+	artifact := &core.Mod{Name: "Nightly", Download: core.ModDownload{Mode: core.ModeGitHubActions}}
+
+	assert.NoError(t, CheckExportable(release))
+	assert.True(t, errors.Is(CheckExportable(artifact), ErrNotExportable))
+
+	exportable, skipped := FilterExportable([]*core.Mod{release, artifact, curseforge})
+	assert.Equal(t, []*core.Mod{release, curseforge}, exportable)
+	if assert.Len(t, skipped, 1) {
+		assert.ErrorContains(t, skipped[0], "Nightly")
+	}
+}
+
+func TestCfExportAsOverride(t *testing.T) {
+	pack := &core.Pack{Options: map[string]interface{}{core.DatapackFolderOption: "config/paxi/datapacks"}}
+	cfUpdate := core.ModUpdate{"curseforge": core.ModSourceData{"project-id": 1, "file-id": 2}}
+
+	mod := &core.Mod{Name: "Mod", ModType: "mods", Update: cfUpdate}
+	datapack := &core.Mod{Name: "Datapack", ModType: "config/paxi/datapacks", Update: cfUpdate}
+	external := &core.Mod{Name: "External", ModType: "mods", Download: core.ModDownload{URL: "https://example.com/mod.jar"}}
+
+	assert.False(t, CfExportAsOverride(pack, mod))
+	assert.True(t, CfExportAsOverride(pack, datapack))
+	assert.True(t, CfExportAsOverride(pack, external))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/leocov-dev/packwiz-nxt/config"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/leocov-dev/packwiz-nxt/core"
@@ -98,3 +99,24 @@ func (c *ghApiClient) getReleases(slug string) (*http.Response, error) {
 
 	return resp, nil
 }
+
+// getWorkflowRuns lists the successful runs of a workflow (given by its file name or ID),
+// newest first, restricted to those on branch if it is non-empty
+func (c *ghApiClient) getWorkflowRuns(slug string, workflow string, branch string) (*http.Response, error) {
+	query := url.Values{}
+	query.Set("status", "success")
+	if branch != "" {
+		query.Set("branch", branch)
+	}
+	return c.makeGet("https://" + ghApiServer + "/repos/" + slug + "/actions/workflows/" + url.PathEscape(workflow) + "/runs?" + query.Encode())
+}
+
+func (c *ghApiClient) getRunArtifacts(slug string, runID int64) (*http.Response, error) {
+	return c.makeGet(fmt.Sprintf("https://%s/repos/%s/actions/runs/%d/artifacts", ghApiServer, slug, runID))
+}
+
+// downloadArtifact downloads the zip archive of an artifact. GitHub requires a token for
+// this, even for public repositories.
+func (c *ghApiClient) downloadArtifact(slug string, artifactID int64) (*http.Response, error) {
+	return c.makeGet(fmt.Sprintf("https://%s/repos/%s/actions/artifacts/%d/zip", ghApiServer, slug, artifactID))
+}
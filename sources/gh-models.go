@@ -1,9 +1,9 @@
 package sources
 
 import (
-	"io"
+	"strings"
 
-	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
 )
 
 type Repo struct {
@@ -18,6 +18,8 @@ type Release struct {
 	TargetCommitish string  `json:"target_commitish"` // The branch of the release
 	Name            string  `json:"name"`
 	CreatedAt       string  `json:"created_at"`
+	Prerelease      bool    `json:"prerelease"`
+	Draft           bool    `json:"draft"`
 	Assets          []Asset `json:"assets"`
 }
 
@@ -25,28 +27,50 @@ type Asset struct {
 	URL                string `json:"url"`
 	BrowserDownloadURL string `json:"browser_download_url"`
 	Name               string `json:"name"`
+	Size               uint64 `json:"size"`
+	// Digest is "<algorithm>:<hash>", published by GitHub for assets uploaded since mid 2025
+	Digest string `json:"digest"`
 }
 
+// WorkflowRun is a GitHub Actions workflow run
+type WorkflowRun struct {
+	ID         int64  `json:"id"`
+	RunNumber  int    `json:"run_number"`
+	HeadBranch string `json:"head_branch"`
+	HeadSha    string `json:"head_sha"`
+	Conclusion string `json:"conclusion"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type workflowRunList struct {
+	WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+}
+
+// Artifact is a file archive uploaded by a GitHub Actions workflow run
+type Artifact struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	SizeInBytes int64  `json:"size_in_bytes"`
+	Expired     bool   `json:"expired"`
+}
+
+type artifactList struct {
+	Artifacts []Artifact `json:"artifacts"`
+}
+
+// getSha256 returns the asset's sha256 hash, using the digest published by GitHub if
+// there is one. Otherwise the asset is downloaded into the shared download cache, so that
+// exporting the pack later doesn't download it again.
 func (u Asset) getSha256() (string, error) {
-	// TODO potentionally cache downloads to speed things up and avoid getting ratelimited by github!
-	mainHasher, err := core.GetHashImpl("sha256")
-	if err != nil {
-		return "", err
+	if hash, ok := strings.CutPrefix(u.Digest, "sha256:"); ok && hash != "" {
+		return strings.ToLower(hash), nil
 	}
 
 	resp, err := ghDefaultClient.makeGet(u.BrowserDownloadURL)
 	if err != nil {
 		return "", err
 	}
-
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	mainHasher.Write(body)
-
-	return mainHasher.String(), nil
+	return fileio.StoreInCache(resp.Body)
 }
@@ -6,14 +6,37 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/dlclark/regexp2"
+	"github.com/unascribed/FlexVer/go/flexver"
 
 	"github.com/leocov-dev/packwiz-nxt/core"
 )
 
 var GithubRegex = regexp.MustCompile(`^https?://(?:www\.)?github\.com/([^/]+/[^/]+)`)
 
+// ghDefaultAssetRegex matches potential release assets when no regex is specified.
+// It will match any asset with a name that does *not* end with:
+// - "-api.jar"
+// - "-dev.jar"
+// - "-dev-preshadow.jar"
+// - "-sources.jar"
+// In most cases, this will only match one asset.
+// TODO: Hopefully.
+const ghDefaultAssetRegex = `^.+(?<!-api|-dev|-dev-preshadow|-sources)\.jar$`
+
+// ghSlugFromUrl extracts the owner/repo slug from a GitHub repository URL, or returns
+// slugOrUrl unchanged if it isn't one.
+func ghSlugFromUrl(slugOrUrl string) string {
+	matches := GithubRegex.FindStringSubmatch(slugOrUrl)
+	if len(matches) == 2 {
+		return matches[1]
+	}
+	return slugOrUrl
+}
+
 func fetchRepo(slug string) (Repo, error) {
 	var repo Repo
 
@@ -41,37 +64,122 @@ func fetchRepo(slug string) (Repo, error) {
 	return repo, nil
 }
 
-func GitHubNewMod(slugOrUrl, branch, regex, modType string) (*core.Mod, error) {
-	var slug string
+// Release selection strategies, see ghReleaseFilter
+const (
+	// GhStrategyLatest selects the most recently published release
+	GhStrategyLatest = "latest"
+	// GhStrategySemver selects the release with the highest version tag, ordered by FlexVer
+	GhStrategySemver = "semver"
+)
 
-	// Check if the argument is a valid GitHub repository URL; if so, extract the slug from the URL.
-	// Otherwise, interpret the argument as a slug directly.
-	matches := GithubRegex.FindStringSubmatch(slugOrUrl)
-	if len(matches) == 2 {
-		slug = matches[1]
-	} else {
-		slug = slugOrUrl
+// ghReleaseFilter restricts which releases of a repository are installed or updated to,
+// and how the latest of them is chosen. The zero value accepts every published,
+// non-prerelease release from any branch, newest first.
+type ghReleaseFilter struct {
+	// Branch restricts releases to those targeting a branch
+	Branch string
+	// Prereleases and Drafts include releases marked as such
+	Prereleases bool
+	Drafts      bool
+	// TagConstraint restricts release tags (without a leading "v"), see core.VersionSatisfies
+	TagConstraint string
+	// TagRegex is a regular expression release tags must match
+	TagRegex string
+	// Strategy is GhStrategyLatest (the default) or GhStrategySemver
+	Strategy string
+}
+
+// apply returns the releases passing the filter, best first. releases must be ordered
+// newest first, as returned by the API. It returns an error if there are none left.
+func (f ghReleaseFilter) apply(releases []Release, slug string) ([]Release, error) {
+	var tagExpr *regexp2.Regexp
+	if f.TagRegex != "" {
+		var err error
+		tagExpr, err = regexp2.Compile(f.TagRegex, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag regex %q: %w", f.TagRegex, err)
+		}
+	}
+	if f.Strategy != "" && f.Strategy != GhStrategyLatest && f.Strategy != GhStrategySemver {
+		return nil, fmt.Errorf("unknown release strategy %q, expected %s or %s", f.Strategy, GhStrategyLatest, GhStrategySemver)
+	}
+
+	var filtered []Release
+	for _, r := range releases {
+		if f.Branch != "" && r.TargetCommitish != f.Branch {
+			continue
+		}
+		if (r.Prerelease && !f.Prereleases) || (r.Draft && !f.Drafts) {
+			continue
+		}
+		if tagExpr != nil {
+			if ok, _ := tagExpr.MatchString(r.TagName); !ok {
+				continue
+			}
+		}
+		ok, err := core.VersionSatisfies(ghTagVersion(r.TagName), f.TagConstraint)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			filtered = append(filtered, r)
+		}
 	}
 
-	repo, err := fetchRepo(slug)
+	if len(filtered) == 0 {
+		if f.Branch != "" {
+			return nil, fmt.Errorf("failed to find release for branch %v", f.Branch)
+		}
+		return nil, fmt.Errorf("no matching releases for %s", slug)
+	}
 
-	if err != nil {
-		return nil, err
+	if f.Strategy == GhStrategySemver {
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return flexver.Compare(ghTagVersion(filtered[i].TagName), ghTagVersion(filtered[j].TagName)) > 0
+		})
+	}
+	return filtered, nil
+}
+
+// isNewer returns true if candidate (as chosen by apply) should replace the installed
+// release tagged installedTag. releases is every release, newest first. This keeps mods
+// from being "updated" to an older release when the installed one is no longer accepted,
+// e.g. a prerelease installed before prereleases were excluded.
+func (f ghReleaseFilter) isNewer(candidate Release, installedTag string, releases []Release) bool {
+	if candidate.TagName == installedTag {
+		return false
+	}
+	if f.Strategy == GhStrategySemver {
+		return flexver.Compare(ghTagVersion(candidate.TagName), ghTagVersion(installedTag)) > 0
+	}
+	for _, r := range releases {
+		if r.TagName == candidate.TagName {
+			return true
+		}
+		if r.TagName == installedTag {
+			return false
+		}
 	}
+	return true
+}
+
+// ghTagVersion strips the "v" prefix commonly used in release tags
+func ghTagVersion(tag string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(tag, "v"), "V")
+}
 
-	if regex == "" {
-		// Regex to match potential release assets against.
-		// The default will match any asset with a name that does *not* end with:
-		// - "-api.jar"
-		// - "-dev.jar"
-		// - "-dev-preshadow.jar"
-		// - "-sources.jar"
-		// In most cases, this will only match one asset.
-		// TODO: Hopefully.
-		regex = `^.+(?<!-api|-dev|-dev-preshadow|-sources)\.jar$`
+// GitHubNewMod creates a Mod from the latest release of a GitHub repository (from branch,
+// if it isn't empty). slugOrUrl is a repository URL or an owner/repo slug.
+func GitHubNewMod(slugOrUrl, branch, regex, modType string) (*core.Mod, error) {
+	// Check if the argument is a valid GitHub repository URL; if so, extract the slug from the URL.
+	// Otherwise, interpret the argument as a slug directly.
+	repo, err := fetchRepo(ghSlugFromUrl(slugOrUrl))
+
+	if err != nil {
+		return nil, err
 	}
 
-	mod, err := installMod(repo, branch, regex, modType)
+	mod, err := installMod(repo, ghReleaseFilter{Branch: branch}, regex, modType)
 	if err != nil {
 		return nil, err
 	}
@@ -79,48 +187,54 @@ func GitHubNewMod(slugOrUrl, branch, regex, modType string) (*core.Mod, error) {
 	return mod, nil
 }
 
-func installMod(repo Repo, branch, regex, modType string) (*core.Mod, error) {
-	latestRelease, err := getLatestRelease(repo.FullName, branch)
+func installMod(repo Repo, filter ghReleaseFilter, regex, modType string) (*core.Mod, error) {
+	latestRelease, err := getLatestRelease(repo.FullName, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get latest release: %v", err)
 	}
 
-	return installRelease(repo, latestRelease, regex, modType)
+	return installRelease(repo, latestRelease, filter, regex, modType)
+}
+
+func getLatestRelease(slug string, filter ghReleaseFilter) (Release, error) {
+	releases, err := getReleases(slug, filter)
+	if err != nil {
+		return Release{}, err
+	}
+	return releases[0], nil
+}
+
+// getReleases fetches the releases of a repository passing filter, best first. It
+// returns an error if there are none.
+func getReleases(slug string, filter ghReleaseFilter) ([]Release, error) {
+	releases, err := fetchReleases(slug)
+	if err != nil {
+		return nil, err
+	}
+	return filter.apply(releases, slug)
 }
 
-func getLatestRelease(slug string, branch string) (Release, error) {
+// fetchReleases fetches every release of a repository visible to us, newest first
+func fetchReleases(slug string) ([]Release, error) {
 	var releases []Release
 
 	resp, err := ghDefaultClient.getReleases(slug)
 	if err != nil {
-		return Release{}, err
+		return nil, err
 	}
 
 	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return Release{}, err
+		return nil, err
 	}
 
 	err = json.Unmarshal(body, &releases)
 	if err != nil {
-		return Release{}, err
-	}
-
-	if branch != "" {
-		for _, r := range releases {
-			if r.TargetCommitish == branch {
-				return r, nil
-			}
-		}
-		return Release{}, fmt.Errorf("failed to find release for branch %v", branch)
-	}
-
-	if len(releases) < 1 {
-		return Release{}, fmt.Errorf("no releases for %s", slug)
+		return nil, err
 	}
 
-	return releases[0], nil
+	return releases, nil
 }
 
 // selectReleaseAsset finds the single asset in assets whose name matches regex.
@@ -154,13 +268,21 @@ func selectReleaseAsset(assets []Asset, regex string) (Asset, error) {
 	return files[0], nil
 }
 
+// installRelease creates a Mod from release. The branch, asset regex and other filter
+// options are recorded only if the user set them, so that updates otherwise follow
+// releases from any branch and pick up changes to the defaults.
 func installRelease(
 	repo Repo,
 	release Release,
+	filter ghReleaseFilter,
 	regex string,
 	modType string,
 ) (*core.Mod, error) {
-	file, err := selectReleaseAsset(release.Assets, regex)
+	assetRegex := regex
+	if assetRegex == "" {
+		assetRegex = ghDefaultAssetRegex
+	}
+	file, err := selectReleaseAsset(release.Assets, assetRegex)
 	if err != nil {
 		return nil, err
 	}
@@ -171,10 +293,15 @@ func installRelease(
 	updateMap := make(core.ModUpdate)
 
 	updateMap["github"], err = ghUpdateData{
-		Slug:   repo.FullName,
-		Tag:    release.TagName,
-		Branch: release.TargetCommitish, // TODO: if no branch is specified by the user, we shouldn't record it - in order to remain branch-agnostic in getLatestRelease()
-		Regex:  regex,                   // TODO: ditto!
+		Slug:          repo.FullName,
+		Tag:           release.TagName,
+		Branch:        filter.Branch,
+		Regex:         regex,
+		Prereleases:   filter.Prereleases,
+		Drafts:        filter.Drafts,
+		TagConstraint: filter.TagConstraint,
+		TagRegex:      filter.TagRegex,
+		Strategy:      filter.Strategy,
 	}.ToMap()
 	if err != nil {
 		return nil, err
@@ -189,6 +316,7 @@ func installRelease(
 		URL:        file.BrowserDownloadURL,
 		HashFormat: "sha256",
 		Hash:       hash,
+		Size:       file.Size,
 	}
 
 	mod := core.NewMod(
@@ -204,6 +332,7 @@ func installRelease(
 		download,
 		nil,
 	)
+	mod.Version = release.TagName
 
 	return mod, nil
 }
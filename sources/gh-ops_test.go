@@ -6,29 +6,29 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-)
 
-const defaultGithubAssetRegex = `^.+(?<!-api|-dev|-dev-preshadow|-sources)\.jar$`
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
 
 func TestSelectReleaseAsset(t *testing.T) {
 	t.Run("no assets errors", func(t *testing.T) {
-		_, err := selectReleaseAsset(nil, defaultGithubAssetRegex)
+		_, err := selectReleaseAsset(nil, ghDefaultAssetRegex)
 		assert.Error(t, err)
 	})
 
 	t.Run("single asset matching regex is returned", func(t *testing.T) {
-		asset, err := selectReleaseAsset([]Asset{{Name: "mymod-1.0.jar"}}, defaultGithubAssetRegex)
+		asset, err := selectReleaseAsset([]Asset{{Name: "mymod-1.0.jar"}}, ghDefaultAssetRegex)
 		require.NoError(t, err)
 		assert.Equal(t, "mymod-1.0.jar", asset.Name)
 	})
 
 	t.Run("no asset matches regex errors", func(t *testing.T) {
-		_, err := selectReleaseAsset([]Asset{{Name: "README.md"}}, defaultGithubAssetRegex)
+		_, err := selectReleaseAsset([]Asset{{Name: "README.md"}}, ghDefaultAssetRegex)
 		assert.Error(t, err)
 	})
 
 	t.Run("multiple assets matching regex errors", func(t *testing.T) {
-		_, err := selectReleaseAsset([]Asset{{Name: "mymod-1.0.jar"}, {Name: "mymod-1.0-extra.jar"}}, defaultGithubAssetRegex)
+		_, err := selectReleaseAsset([]Asset{{Name: "mymod-1.0.jar"}, {Name: "mymod-1.0-extra.jar"}}, ghDefaultAssetRegex)
 		assert.Error(t, err)
 	})
 
@@ -40,7 +40,7 @@ func TestSelectReleaseAsset(t *testing.T) {
 			{Name: "mymod-1.0-dev-preshadow.jar"},
 			{Name: "mymod-1.0-api.jar"},
 		}
-		asset, err := selectReleaseAsset(assets, defaultGithubAssetRegex)
+		asset, err := selectReleaseAsset(assets, ghDefaultAssetRegex)
 		require.NoError(t, err)
 		assert.Equal(t, "mymod-1.0.jar", asset.Name)
 	})
@@ -108,7 +108,7 @@ func TestGetLatestRelease(t *testing.T) {
 		}))
 		withGhClient(t, httpClient)
 
-		release, err := getLatestRelease("owner/repo", "")
+		release, err := getLatestRelease("owner/repo", ghReleaseFilter{})
 		require.NoError(t, err)
 		assert.Equal(t, "v2.0", release.TagName)
 	})
@@ -121,7 +121,7 @@ func TestGetLatestRelease(t *testing.T) {
 		}))
 		withGhClient(t, httpClient)
 
-		release, err := getLatestRelease("owner/repo", "main")
+		release, err := getLatestRelease("owner/repo", ghReleaseFilter{Branch: "main"})
 		require.NoError(t, err)
 		assert.Equal(t, "v1.0", release.TagName)
 	})
@@ -134,7 +134,7 @@ func TestGetLatestRelease(t *testing.T) {
 		}))
 		withGhClient(t, httpClient)
 
-		_, err := getLatestRelease("owner/repo", "missing-branch")
+		_, err := getLatestRelease("owner/repo", ghReleaseFilter{Branch: "missing-branch"})
 		assert.Error(t, err)
 	})
 
@@ -146,7 +146,7 @@ func TestGetLatestRelease(t *testing.T) {
 		}))
 		withGhClient(t, httpClient)
 
-		_, err := getLatestRelease("owner/repo", "")
+		_, err := getLatestRelease("owner/repo", ghReleaseFilter{})
 		assert.Error(t, err)
 	})
 }
@@ -165,8 +165,90 @@ func TestInstallMod(t *testing.T) {
 	withGhClient(t, httpClient)
 
 	repo := Repo{Name: "repo", FullName: "owner/repo"}
-	mod, err := installMod(repo, "", defaultGithubAssetRegex, "mods")
-	require.NoError(t, err)
-	assert.Equal(t, "mod.jar", mod.FileName)
-	assert.NotEmpty(t, mod.Download.Hash)
+	t.Run("defaults are not recorded", func(t *testing.T) {
+		mod, err := installMod(repo, ghReleaseFilter{}, "", "mods")
+		require.NoError(t, err)
+		assert.Equal(t, "mod.jar", mod.FileName)
+		assert.Equal(t, "v1.0", mod.Version)
+		assert.NotEmpty(t, mod.Download.Hash)
+		assert.Equal(t, core.ModSourceData{"slug": "owner/repo", "tag": "v1.0"}, mod.Update["github"])
+	})
+
+	t.Run("filter options are recorded", func(t *testing.T) {
+		filter := ghReleaseFilter{Prereleases: true, TagConstraint: ">=1", Strategy: GhStrategySemver}
+		mod, err := installMod(repo, filter, `^mod\.jar$`, "mods")
+		require.NoError(t, err)
+		data := mod.Update["github"]
+		assert.Equal(t, `^mod\.jar$`, data["regex"])
+		assert.Equal(t, true, data["prereleases"])
+		assert.Equal(t, ">=1", data["tag-constraint"])
+		assert.Equal(t, GhStrategySemver, data["strategy"])
+		assert.NotContains(t, data, "branch")
+	})
+}
+
+func TestGhReleaseFilter(t *testing.T) {
+	releases := []Release{
+		{TagName: "v2.1.0-beta.1", Prerelease: true},
+		{TagName: "v2.0.0-draft", Draft: true},
+		{TagName: "v1.10.0", TargetCommitish: "1.x"},
+		{TagName: "v2.0.0"},
+		{TagName: "nightly-5"},
+	}
+	tags := func(rs []Release) []string {
+		var out []string
+		for _, r := range rs {
+			out = append(out, r.TagName)
+		}
+		return out
+	}
+
+	t.Run("zero value excludes prereleases and drafts", func(t *testing.T) {
+		got, err := ghReleaseFilter{}.apply(releases, "owner/repo")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v1.10.0", "v2.0.0", "nightly-5"}, tags(got))
+	})
+
+	t.Run("prereleases and drafts can be included", func(t *testing.T) {
+		got, err := ghReleaseFilter{Prereleases: true, Drafts: true}.apply(releases, "owner/repo")
+		require.NoError(t, err)
+		assert.Len(t, got, 5)
+	})
+
+	t.Run("semver strategy orders by tag version", func(t *testing.T) {
+		got, err := ghReleaseFilter{Strategy: GhStrategySemver, TagRegex: `^v\d`}.apply(releases, "owner/repo")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v2.0.0", "v1.10.0"}, tags(got))
+	})
+
+	t.Run("tag constraint", func(t *testing.T) {
+		got, err := ghReleaseFilter{TagConstraint: "1"}.apply(releases, "owner/repo")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v1.10.0"}, tags(got))
+	})
+
+	t.Run("nothing matching errors", func(t *testing.T) {
+		_, err := ghReleaseFilter{TagRegex: `^release-`}.apply(releases, "owner/repo")
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid options error", func(t *testing.T) {
+		_, err := ghReleaseFilter{TagRegex: `(`}.apply(releases, "owner/repo")
+		assert.Error(t, err)
+		_, err = ghReleaseFilter{Strategy: "oldest"}.apply(releases, "owner/repo")
+		assert.Error(t, err)
+	})
+
+	t.Run("isNewer", func(t *testing.T) {
+		latest := ghReleaseFilter{}
+		assert.False(t, latest.isNewer(releases[2], "v1.10.0", releases))
+		// The installed prerelease is newer than every accepted release
+		assert.False(t, latest.isNewer(releases[2], "v2.1.0-beta.1", releases))
+		assert.True(t, latest.isNewer(releases[3], "nightly-5", releases))
+		assert.True(t, latest.isNewer(releases[2], "deleted-tag", releases))
+
+		semver := ghReleaseFilter{Strategy: GhStrategySemver}
+		assert.True(t, semver.isNewer(releases[3], "v1.10.0", releases))
+		assert.False(t, semver.isNewer(releases[2], "v2.0.0", releases))
+	})
 }
@@ -0,0 +1,139 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// Options understood by the GitHub Source, in core.SourceRequest.Options
+const (
+	// GhOptionBranch restricts releases to those targeting a branch
+	GhOptionBranch = "branch"
+	// GhOptionRegex is the regular expression release assets are matched against
+	GhOptionRegex = "regex"
+	// GhOptionPrereleases includes prereleases if "true"
+	GhOptionPrereleases = "prereleases"
+	// GhOptionDrafts includes draft releases if "true" (only visible with push access)
+	GhOptionDrafts = "drafts"
+	// GhOptionTagConstraint restricts release tags by version, see core.VersionSatisfies
+	GhOptionTagConstraint = "tag-constraint"
+	// GhOptionTagRegex is a regular expression release tags must match
+	GhOptionTagRegex = "tag-regex"
+	// GhOptionStrategy is GhStrategyLatest or GhStrategySemver
+	GhOptionStrategy = "strategy"
+)
+
+// ghFilterFromRequest reads the ghReleaseFilter options from req
+func ghFilterFromRequest(req core.SourceRequest) (ghReleaseFilter, error) {
+	filter := ghReleaseFilter{
+		Branch:        req.Option(GhOptionBranch),
+		TagConstraint: req.Option(GhOptionTagConstraint),
+		TagRegex:      req.Option(GhOptionTagRegex),
+		Strategy:      req.Option(GhOptionStrategy),
+	}
+	for name, dest := range map[string]*bool{
+		GhOptionPrereleases: &filter.Prereleases,
+		GhOptionDrafts:      &filter.Drafts,
+	} {
+		if value := req.Option(name); value != "" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid value for %s: %q", name, value)
+			}
+			*dest = b
+		}
+	}
+	return filter, nil
+}
+
+// ghSourceVersion is the GitHub-specific data stored in core.SourceVersion.Data
+type ghSourceVersion struct {
+	Repo    Repo
+	Release Release
+}
+
+type ghSource struct{}
+
+func (s ghSource) GetName() string {
+	return "github"
+}
+
+func (s ghSource) Search(string, core.SourceRequest) ([]core.SourceProject, error) {
+	return nil, fmt.Errorf("%w: GitHub does not support searching for projects", core.ErrSourceUnavailable)
+}
+
+func (s ghSource) ResolveProject(identifier string, _ core.SourceRequest) (core.SourceProject, error) {
+	repo, err := fetchRepo(ghSlugFromUrl(identifier))
+	if err != nil {
+		return core.SourceProject{}, err
+	}
+
+	return core.SourceProject{
+		Source: "github",
+		ID:     repo.FullName,
+		Slug:   core.SlugifyName(repo.Name),
+		Name:   repo.Name,
+		Data:   repo,
+	}, nil
+}
+
+func (s ghSource) ListVersions(project core.SourceProject, req core.SourceRequest) ([]core.SourceVersion, error) {
+	repo, ok := project.Data.(Repo)
+	if !ok {
+		return nil, errors.New("not a GitHub project")
+	}
+
+	filter, err := ghFilterFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	releases, err := getReleases(repo.FullName, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get releases: %w", err)
+	}
+
+	versions := make([]core.SourceVersion, 0, len(releases))
+	for _, release := range releases {
+		versions = append(versions, core.SourceVersion{
+			ID:      release.TagName,
+			Version: release.TagName,
+			Data:    ghSourceVersion{Repo: repo, Release: release},
+		})
+	}
+	return versions, nil
+}
+
+func (s ghSource) SelectVersion(_ core.SourceProject, versions []core.SourceVersion, _ core.SourceRequest) (core.SourceVersion, error) {
+	// ListVersions orders releases best first, according to the strategy option
+	if len(versions) == 0 {
+		return core.SourceVersion{}, errors.New("no releases found")
+	}
+	return versions[0], nil
+}
+
+func (s ghSource) NewMod(_ core.SourceProject, version core.SourceVersion, req core.SourceRequest) (*core.Mod, error) {
+	data, ok := version.Data.(ghSourceVersion)
+	if !ok {
+		return nil, errors.New("not a GitHub release")
+	}
+
+	filter, err := ghFilterFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	modType := req.MetaFolder
+	if modType == "" {
+		modType = "mods"
+	}
+
+	return installRelease(data.Repo, data.Release, filter, req.Option(GhOptionRegex), modType)
+}
+
+func (s ghSource) FindMissingDependencies(core.SourceVersion, core.SourceRequest) ([]*core.Mod, error) {
+	// GitHub releases carry no dependency information
+	return nil, nil
+}
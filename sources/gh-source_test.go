@@ -0,0 +1,80 @@
+package sources
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func TestGhSource(t *testing.T) {
+	withGhClient(t, newTestHTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ratelimit-remaining", "999")
+		switch r.URL.Path {
+		case "/repos/owner/repo":
+			_, _ = w.Write([]byte(`{"id":1,"name":"repo","full_name":"owner/repo"}`))
+		case "/repos/owner/repo/releases":
+			_, _ = w.Write([]byte(`[{"tag_name":"v2.0","target_commitish":"dev","assets":[{"name":"mod-2.0.jar","browser_download_url":"https://example.com/mod-2.0.jar"}]},{"tag_name":"v1.0","target_commitish":"main","assets":[{"name":"mod-1.0.jar","browser_download_url":"https://example.com/mod-1.0.jar"}]}]`))
+		default:
+			_, _ = w.Write([]byte(`jar contents`))
+		}
+	})))
+
+	src := ghSource{}
+	req := core.SourceRequest{}
+
+	t.Run("search is unavailable", func(t *testing.T) {
+		_, err := src.Search("repo", req)
+		assert.True(t, errors.Is(err, core.ErrSourceUnavailable))
+	})
+
+	t.Run("installs the latest release from a URL", func(t *testing.T) {
+		mod, version, err := core.NewModFromSource(src, "https://github.com/owner/repo", req)
+		require.NoError(t, err)
+		assert.Equal(t, "v2.0", version.Version)
+		assert.Equal(t, "mod-2.0.jar", mod.FileName)
+		assert.Equal(t, "mods", mod.ModType)
+		assert.NotEmpty(t, mod.Download.Hash)
+	})
+
+	t.Run("branch option restricts releases", func(t *testing.T) {
+		branchReq := core.SourceRequest{
+			MetaFolder: "plugins",
+			Options:    map[string]string{GhOptionBranch: "main"},
+		}
+		mod, version, err := core.NewModFromSource(src, "owner/repo", branchReq)
+		require.NoError(t, err)
+		assert.Equal(t, "v1.0", version.Version)
+		assert.Equal(t, "mod-1.0.jar", mod.FileName)
+		assert.Equal(t, "plugins", mod.ModType)
+		assert.Equal(t, "main", mod.Update["github"]["branch"])
+	})
+
+	t.Run("filter options", func(t *testing.T) {
+		filterReq := core.SourceRequest{Options: map[string]string{
+			GhOptionTagConstraint: "<2",
+			GhOptionPrereleases:   "true",
+		}}
+		mod, version, err := core.NewModFromSource(src, "owner/repo", filterReq)
+		require.NoError(t, err)
+		assert.Equal(t, "v1.0", version.Version)
+		assert.Equal(t, "<2", mod.Update["github"]["tag-constraint"])
+		assert.Equal(t, true, mod.Update["github"]["prereleases"])
+		assert.NotContains(t, mod.Update["github"], "branch")
+
+		_, _, err = core.NewModFromSource(src, "owner/repo", core.SourceRequest{
+			Options: map[string]string{GhOptionDrafts: "maybe"},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("no dependencies", func(t *testing.T) {
+		deps, err := src.FindMissingDependencies(core.SourceVersion{}, req)
+		require.NoError(t, err)
+		assert.Empty(t, deps)
+	})
+}
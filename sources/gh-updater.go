@@ -12,18 +12,27 @@ func init() {
 	RegisterGithub(core.DefaultRegistry)
 }
 
-// RegisterGithub registers the GitHub Updater on reg. Library consumers building an
+// RegisterGithub registers the GitHub Updater/Source on reg. Library consumers building an
 // isolated *core.Registry (instead of relying on core.DefaultRegistry) should call this
 // - or sources.RegisterAll - explicitly.
 func RegisterGithub(reg *core.Registry) {
 	reg.AddUpdater(ghUpdater{})
+	reg.AddSource(ghSource{})
 }
 
 type ghUpdateData struct {
-	Slug   string `mapstructure:"slug"`
-	Tag    string `mapstructure:"tag"`
-	Branch string `mapstructure:"branch"`
-	Regex  string `mapstructure:"regex"`
+	Slug string `mapstructure:"slug"`
+	Tag  string `mapstructure:"tag"`
+	// Branch, if set, restricts releases to those targeting it
+	Branch string `mapstructure:"branch,omitempty"`
+	// Regex selects the release asset, ghDefaultAssetRegex if unset
+	Regex string `mapstructure:"regex,omitempty"`
+	// The remaining fields configure ghReleaseFilter
+	Prereleases   bool   `mapstructure:"prereleases,omitempty"`
+	Drafts        bool   `mapstructure:"drafts,omitempty"`
+	TagConstraint string `mapstructure:"tag-constraint,omitempty"`
+	TagRegex      string `mapstructure:"tag-regex,omitempty"`
+	Strategy      string `mapstructure:"strategy,omitempty"`
 }
 
 func (u ghUpdateData) ToMap() (map[string]interface{}, error) {
@@ -32,6 +41,24 @@ func (u ghUpdateData) ToMap() (map[string]interface{}, error) {
 	return newMap, err
 }
 
+func (u ghUpdateData) filter() ghReleaseFilter {
+	return ghReleaseFilter{
+		Branch:        u.Branch,
+		Prereleases:   u.Prereleases,
+		Drafts:        u.Drafts,
+		TagConstraint: u.TagConstraint,
+		TagRegex:      u.TagRegex,
+		Strategy:      u.Strategy,
+	}
+}
+
+func (u ghUpdateData) assetRegex() string {
+	if u.Regex == "" {
+		return ghDefaultAssetRegex
+	}
+	return u.Regex
+}
+
 type ghUpdater struct{}
 
 func (u ghUpdater) GetName() string {
@@ -50,8 +77,10 @@ type ghCachedStateStore struct {
 	Asset Asset
 }
 
-func (u ghUpdater) CheckUpdate(mods []*core.Mod, _ core.Pack) ([]core.UpdateCheck, error) {
+func (u ghUpdater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateCheck, error) {
 	results := make([]core.UpdateCheck, len(mods))
+	// Only used to skip Minecraft versions in tags, so packs without one are still updated
+	mcVersions, _ := pack.GetSupportedMCVersions()
 
 	for i, mod := range mods {
 
@@ -62,18 +91,40 @@ func (u ghUpdater) CheckUpdate(mods []*core.Mod, _ core.Pack) ([]core.UpdateChec
 			continue
 		}
 
-		newRelease, err := getLatestRelease(data.Slug, data.Branch)
+		filter := data.filter()
+		releases, err := fetchReleases(data.Slug)
 		if err != nil {
 			results[i] = core.UpdateCheck{Error: fmt.Errorf("failed to get latest release: %v", err)}
 			continue
 		}
+		candidates, err := filter.apply(releases, data.Slug)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: fmt.Errorf("failed to get latest release: %v", err)}
+			continue
+		}
+		heldBack := ""
+		accepted, err := core.FilterAcceptedVersions(mod, mcVersions, candidates, func(r Release) string {
+			return ghTagVersion(r.TagName)
+		})
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: err}
+			continue
+		}
+		if len(accepted) == 0 || accepted[0].TagName != candidates[0].TagName {
+			heldBack = candidates[0].TagName
+		}
+		if len(accepted) == 0 {
+			results[i] = core.UpdateCheck{UpdateAvailable: false, HeldBack: heldBack}
+			continue
+		}
+		newRelease := accepted[0]
 
-		if newRelease.TagName == data.Tag { // The latest release is the same as the installed one
-			results[i] = core.UpdateCheck{UpdateAvailable: false}
+		if !filter.isNewer(newRelease, data.Tag, releases) { // The installed release is already the latest
+			results[i] = core.UpdateCheck{UpdateAvailable: false, HeldBack: heldBack}
 			continue
 		}
 
-		newFile, err := selectReleaseAsset(newRelease.Assets, data.Regex)
+		newFile, err := selectReleaseAsset(newRelease.Assets, data.assetRegex())
 		if err != nil {
 			results[i] = core.UpdateCheck{Error: err}
 			continue
@@ -81,7 +132,8 @@ func (u ghUpdater) CheckUpdate(mods []*core.Mod, _ core.Pack) ([]core.UpdateChec
 
 		results[i] = core.UpdateCheck{
 			UpdateAvailable: true,
-			UpdateString:    mod.FileName + " -> " + newFile.Name,
+			UpdateString:    mod.DescribeUpdate(newRelease.TagName, newFile.Name),
+			HeldBack:        heldBack,
 			CachedState:     ghCachedStateStore{data.Slug, newRelease.TagName, newFile},
 		}
 	}
@@ -100,10 +152,12 @@ func (u ghUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
 		}
 
 		mod.FileName = file.Name
+		mod.Version = modState.Tag
 		mod.Download = core.ModDownload{
 			URL:        file.BrowserDownloadURL,
 			HashFormat: "sha256",
 			Hash:       hash,
+			Size:       file.Size,
 		}
 		mod.Update["github"]["tag"] = modState.Tag
 	}
@@ -8,15 +8,18 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
 )
 
 // withGhClient swaps the package-level GitHub client singleton for the
-// duration of the test, restoring the original afterward.
+// duration of the test, restoring the original afterward. The download cache, which
+// assets are hashed into, is pointed at a temporary directory.
 func withGhClient(t *testing.T, httpClient *http.Client) {
 	t.Helper()
 	original := ghDefaultClient
 	ghDefaultClient = *NewGithubClient(httpClient, core.NoopLogger{})
 	t.Cleanup(func() { ghDefaultClient = original })
+	withTestCache(t)
 }
 
 func ghTestMod(name, slug, tag string) *core.Mod {
@@ -66,6 +69,65 @@ func TestGhUpdater_CheckUpdate(t *testing.T) {
 		assert.False(t, results[0].UpdateAvailable)
 	})
 
+	t.Run("prereleases are skipped without downgrading", func(t *testing.T) {
+		httpClient := newTestHTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("x-ratelimit-remaining", "999")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`[{"tag_name":"v3.0-beta","prerelease":true,"assets":[{"name":"mod-v3.0-beta.jar"}]},{"tag_name":"v2.1-beta","prerelease":true,"assets":[{"name":"mod-v2.1-beta.jar"}]},{"tag_name":"v2.0","assets":[{"name":"mod-v2.0.jar"}]}]`))
+		}))
+		withGhClient(t, httpClient)
+
+		stable := ghTestMod("Stable", "foo/bar", "v1.0")
+		installedBeta := ghTestMod("Beta", "foo/bar", "v2.1-beta")
+		tracksBeta := ghTestMod("Tracks Betas", "foo/bar", "v2.1-beta")
+		tracksBeta.Update["github"]["prereleases"] = true
+
+		results, err := ghUpdater{}.CheckUpdate([]*core.Mod{stable, installedBeta, tracksBeta}, core.Pack{})
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Equal(t, "old.jar -> mod-v2.0.jar", results[0].UpdateString)
+		assert.False(t, results[1].UpdateAvailable)
+		assert.NoError(t, results[1].Error)
+		assert.Equal(t, "old.jar -> mod-v3.0-beta.jar", results[2].UpdateString)
+	})
+
+	t.Run("version constraint holds back newer releases", func(t *testing.T) {
+		httpClient := newTestHTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("x-ratelimit-remaining", "999")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`[{"tag_name":"v5.0","assets":[{"name":"mod-v5.0.jar"}]},{"tag_name":"v4.3","assets":[{"name":"mod-v4.3.jar"}]},{"tag_name":"v4.2","assets":[{"name":"mod-v4.2.jar"}]}]`))
+		}))
+		withGhClient(t, httpClient)
+
+		constrained := ghTestMod("Constrained", "foo/bar", "v4.2")
+		constrained.VersionConstraint = "<5"
+		upToDate := ghTestMod("Up To Date", "foo/bar", "v4.3")
+		upToDate.VersionConstraint = "<5"
+
+		results, err := ghUpdater{}.CheckUpdate([]*core.Mod{constrained, upToDate}, core.Pack{})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, "old.jar -> mod-v4.3.jar", results[0].UpdateString)
+		assert.Equal(t, "v5.0", results[0].HeldBack)
+		assert.False(t, results[1].UpdateAvailable)
+		assert.Equal(t, "v5.0", results[1].HeldBack)
+	})
+
+	t.Run("missing regex uses the default", func(t *testing.T) {
+		httpClient := newTestHTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("x-ratelimit-remaining", "999")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`[{"tag_name":"v2.0","assets":[{"name":"mod-v2.0.jar"},{"name":"mod-v2.0-sources.jar"}]}]`))
+		}))
+		withGhClient(t, httpClient)
+
+		mod := ghTestMod("Test Mod", "foo/bar", "v1.0")
+		delete(mod.Update["github"], "regex")
+		results, err := ghUpdater{}.CheckUpdate([]*core.Mod{mod}, core.Pack{})
+		require.NoError(t, err)
+		assert.Equal(t, "old.jar -> mod-v2.0.jar", results[0].UpdateString)
+	})
+
 	t.Run("decode failure is reported per-mod", func(t *testing.T) {
 		badMod := &core.Mod{Name: "Bad Mod", Update: core.ModUpdate{"github": nil}}
 		results, err := ghUpdater{}.CheckUpdate([]*core.Mod{badMod}, core.Pack{})
@@ -100,17 +162,31 @@ func TestGhUpdater_DoUpdate(t *testing.T) {
 }
 
 func TestAsset_getSha256(t *testing.T) {
-	// BrowserDownloadURL is caller-supplied (unlike the API host), so it can
-	// point directly at an httptest.Server without the redirect-transport helper.
-	httpClient := newTestHTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("x-ratelimit-remaining", "999")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`known content`))
-	}))
-	withGhClient(t, httpClient)
+	t.Run("published digest is used without downloading", func(t *testing.T) {
+		withGhClient(t, newTestHTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request to %s", r.URL)
+		})))
 
-	asset := Asset{BrowserDownloadURL: "https://example.com/file.jar"}
-	hash, err := asset.getSha256()
-	require.NoError(t, err)
-	assert.NotEmpty(t, hash)
+		asset := Asset{BrowserDownloadURL: "https://example.com/file.jar", Digest: "sha256:ABCDEF"}
+		hash, err := asset.getSha256()
+		require.NoError(t, err)
+		assert.Equal(t, "abcdef", hash)
+	})
+
+	t.Run("without a digest the asset is downloaded into the cache", func(t *testing.T) {
+		withGhClient(t, newTestHTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("x-ratelimit-remaining", "999")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`known content`))
+		})))
+
+		asset := Asset{BrowserDownloadURL: "https://example.com/file.jar", Digest: "sha512:ignored"}
+		hash, err := asset.getSha256()
+		require.NoError(t, err)
+		assert.Equal(t, "41277d8d0b0610e58f13bdc06b732c629a2fd3ff93c382f40af3f60cfe5e5c9e", hash)
+
+		index, err := fileio.OpenCacheIndex()
+		require.NoError(t, err)
+		assert.NotNil(t, index.GetHandleFromHash("sha256", hash))
+	})
 }
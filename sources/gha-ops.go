@@ -0,0 +1,290 @@
+package sources
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+
+	"github.com/dlclark/regexp2"
+
+	"github.com/leocov-dev/packwiz-nxt/config"
+	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+)
+
+// readGhJson decodes the JSON body of a GitHub API response into v
+func readGhJson(resp *http.Response, v any) error {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// ghaLatestRun returns the newest successful run of workflow, on branch if it is non-empty
+func ghaLatestRun(slug string, workflow string, branch string) (WorkflowRun, error) {
+	resp, err := ghDefaultClient.getWorkflowRuns(slug, workflow, branch)
+	if err != nil {
+		return WorkflowRun{}, err
+	}
+
+	var runs workflowRunList
+	if err := readGhJson(resp, &runs); err != nil {
+		return WorkflowRun{}, err
+	}
+
+	for _, run := range runs.WorkflowRuns {
+		if run.Conclusion == "success" {
+			return run, nil
+		}
+	}
+	if branch != "" {
+		return WorkflowRun{}, fmt.Errorf("no successful runs of workflow %s on branch %s", workflow, branch)
+	}
+	return WorkflowRun{}, fmt.Errorf("no successful runs of workflow %s", workflow)
+}
+
+// ghaRunVersion returns the version label of the files uploaded by run
+func ghaRunVersion(run WorkflowRun) string {
+	return "run #" + strconv.Itoa(run.RunNumber)
+}
+
+// ghaSelectArtifact finds the single unexpired artifact of a run whose name matches
+// regex, or the only unexpired artifact if regex is empty
+func ghaSelectArtifact(slug string, run WorkflowRun, regex string) (Artifact, error) {
+	resp, err := ghDefaultClient.getRunArtifacts(slug, run.ID)
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	var artifacts artifactList
+	if err := readGhJson(resp, &artifacts); err != nil {
+		return Artifact{}, err
+	}
+
+	var expr *regexp2.Regexp
+	if regex != "" {
+		expr, err = regexp2.Compile(regex, 0)
+		if err != nil {
+			return Artifact{}, fmt.Errorf("invalid artifact regex %q: %w", regex, err)
+		}
+	}
+
+	var matches []Artifact
+	for _, a := range artifacts.Artifacts {
+		if a.Expired {
+			continue
+		}
+		if expr != nil {
+			if ok, _ := expr.MatchString(a.Name); !ok {
+				continue
+			}
+		}
+		matches = append(matches, a)
+	}
+
+	if len(matches) == 0 {
+		return Artifact{}, fmt.Errorf("run #%d doesn't have any unexpired artifacts matching regex", run.RunNumber)
+	}
+	if len(matches) > 1 {
+		return Artifact{}, fmt.Errorf("run #%d has more than one artifact matching regex", run.RunNumber)
+	}
+	return matches[0], nil
+}
+
+// ghaArtifactFile is a file inside a downloaded artifact archive. Closing it removes the
+// temporary copy of the archive.
+type ghaArtifactFile struct {
+	io.ReadCloser
+	// Path is the file's path inside the archive
+	Path    string
+	archive *zip.ReadCloser
+	temp    string
+}
+
+func (f *ghaArtifactFile) Close() error {
+	err := f.ReadCloser.Close()
+	_ = f.archive.Close()
+	_ = os.Remove(f.temp)
+	return err
+}
+
+// ghaOpenArtifactFile downloads an artifact and opens the file at filePath inside it, or
+// if filePath is empty, the single file whose name matches regex
+func ghaOpenArtifactFile(slug string, artifactID int64, filePath string, regex string) (*ghaArtifactFile, error) {
+	if config.GetGhApiKey() == "" {
+		return nil, errors.New("downloading GitHub Actions artifacts requires a GitHub token")
+	}
+
+	resp, err := ghDefaultClient.downloadArtifact(slug, artifactID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download artifact %d (artifacts expire, updating may fix this): %w", artifactID, err)
+	}
+	defer resp.Body.Close()
+
+	// Archives are read from disk rather than buffered, as zip needs random access
+	temp, err := os.CreateTemp("", "packwiz-artifact-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(temp, resp.Body)
+	_ = temp.Close()
+	if err != nil {
+		_ = os.Remove(temp.Name())
+		return nil, fmt.Errorf("failed to download artifact %d: %w", artifactID, err)
+	}
+
+	archive, err := zip.OpenReader(temp.Name())
+	if err != nil {
+		_ = os.Remove(temp.Name())
+		return nil, fmt.Errorf("failed to read artifact %d: %w", artifactID, err)
+	}
+
+	entry, err := ghaSelectArtifactEntry(archive.File, filePath, regex)
+	if err == nil {
+		var rc io.ReadCloser
+		if rc, err = entry.Open(); err == nil {
+			return &ghaArtifactFile{ReadCloser: rc, Path: entry.Name, archive: archive, temp: temp.Name()}, nil
+		}
+	}
+	_ = archive.Close()
+	_ = os.Remove(temp.Name())
+	return nil, err
+}
+
+func ghaSelectArtifactEntry(files []*zip.File, filePath string, regex string) (*zip.File, error) {
+	if filePath != "" {
+		for _, f := range files {
+			if f.Name == filePath {
+				return f, nil
+			}
+		}
+		return nil, fmt.Errorf("artifact doesn't contain %s", filePath)
+	}
+
+	expr, err := regexp2.Compile(regex, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %w", regex, err)
+	}
+
+	var matches []*zip.File
+	for _, f := range files {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if ok, _ := expr.MatchString(path.Base(f.Name)); ok {
+			matches = append(matches, f)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, errors.New("artifact doesn't have any files matching regex")
+	}
+	if len(matches) > 1 {
+		return nil, errors.New("artifact has more than one file matching regex")
+	}
+	return matches[0], nil
+}
+
+// ghaHashArtifactFile downloads the file selected by data's regex from an artifact into
+// the download cache, returning its path inside the archive and sha256 hash
+func ghaHashArtifactFile(data ghaUpdateData, artifactID int64) (string, string, error) {
+	file, err := ghaOpenArtifactFile(data.Slug, artifactID, "", data.fileRegex())
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+
+	hash, err := fileio.StoreInCache(file)
+	if err != nil {
+		return "", "", err
+	}
+	return file.Path, hash, nil
+}
+
+// GitHubActionsNewMod creates a Mod from an artifact of the latest successful run of a
+// GitHub Actions workflow. slugOrUrl is a repository URL or an owner/repo slug, workflow
+// is the workflow's file name (e.g. "build.yml") or ID. artifactRegex selects the
+// artifact (and may be empty if runs upload only one), regex the JAR inside it.
+//
+// Artifacts can't be exported to Modrinth or CurseForge packs, see CheckExportable.
+func GitHubActionsNewMod(slugOrUrl, workflow, branch, artifactRegex, regex, modType string) (*core.Mod, error) {
+	if workflow == "" {
+		return nil, errors.New("a workflow file name or ID is required")
+	}
+
+	repo, err := fetchRepo(ghSlugFromUrl(slugOrUrl))
+	if err != nil {
+		return nil, err
+	}
+
+	data := ghaUpdateData{
+		Slug:          repo.FullName,
+		Workflow:      workflow,
+		Branch:        branch,
+		ArtifactRegex: artifactRegex,
+		Regex:         regex,
+	}
+	run, err := ghaLatestRun(data.Slug, data.Workflow, data.Branch)
+	if err != nil {
+		return nil, err
+	}
+	artifact, err := ghaSelectArtifact(data.Slug, run, data.ArtifactRegex)
+	if err != nil {
+		return nil, err
+	}
+	return ghaInstallRun(repo, data, run, artifact, modType)
+}
+
+// ghaInstallRun creates a Mod from artifact, uploaded by run. data configures how future
+// runs are selected; the run and file are filled in.
+func ghaInstallRun(repo Repo, data ghaUpdateData, run WorkflowRun, artifact Artifact, modType string) (*core.Mod, error) {
+	if data.Workflow == "" {
+		return nil, errors.New("a workflow file name or ID is required")
+	}
+
+	filePath, hash, err := ghaHashArtifactFile(data, artifact.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	GetGithubClient().logger.Infof("Installing %s from %s in run #%d\n", path.Base(filePath), artifact.Name, run.RunNumber)
+
+	data.RunID = run.ID
+	data.ArtifactID = artifact.ID
+	data.File = filePath
+
+	updateMap := make(core.ModUpdate)
+	updateMap["github-actions"], err = data.ToMap()
+	if err != nil {
+		return nil, err
+	}
+
+	mod := core.NewMod(
+		core.SlugifyName(repo.Name),
+		repo.Name,
+		path.Base(filePath),
+		core.UniversalSide,
+		modType,
+		"",
+		false,
+		false,
+		updateMap,
+		core.ModDownload{
+			HashFormat: "sha256",
+			Hash:       hash,
+			Mode:       core.ModeGitHubActions,
+		},
+		nil,
+	)
+	mod.Version = ghaRunVersion(run)
+	return mod, nil
+}
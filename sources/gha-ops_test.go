@@ -0,0 +1,171 @@
+package sources
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/config"
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+const ghaTestJar = "mod 1.2 contents"
+
+// withGhToken sets the GitHub token for the duration of the test
+func withGhToken(t *testing.T, token string) {
+	t.Helper()
+	original := config.GetGhApiKey()
+	config.SetGitHubApiKey(token)
+	t.Cleanup(func() { config.SetGitHubApiKey(original) })
+}
+
+func ghaTestArchive(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, contents := range map[string]string{
+		"build/libs/mod-1.2.jar":         ghaTestJar,
+		"build/libs/mod-1.2-sources.jar": "sources",
+		"README.md":                      "readme",
+	} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// newTestGhaServer serves a repository whose build.yml workflow has two successful runs
+// (and one on the dev branch), with artifacts. Artifact downloads require a token.
+func newTestGhaServer(t *testing.T) {
+	t.Helper()
+	archive := ghaTestArchive(t)
+	withGhClient(t, newTestHTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ratelimit-remaining", "999")
+		switch r.URL.Path {
+		case "/repos/owner/repo":
+			_, _ = w.Write([]byte(`{"id":1,"name":"repo","full_name":"owner/repo"}`))
+		case "/repos/owner/repo/actions/workflows/build.yml/runs":
+			if r.URL.Query().Get("status") != "success" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if r.URL.Query().Get("branch") == "dev" {
+				_, _ = w.Write([]byte(`{"workflow_runs":[{"id":300,"run_number":13,"head_branch":"dev","conclusion":"success"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"workflow_runs":[{"id":200,"run_number":12,"head_sha":"abcdef123456","conclusion":"success"},{"id":100,"run_number":11,"conclusion":"success"}]}`))
+		case "/repos/owner/repo/actions/runs/200/artifacts":
+			_, _ = w.Write([]byte(`{"artifacts":[{"id":21,"name":"mod-jars"},{"id":22,"name":"test-reports"},{"id":23,"name":"old-jars","expired":true}]}`))
+		case "/repos/owner/repo/actions/runs/300/artifacts":
+			_, _ = w.Write([]byte(`{"artifacts":[]}`))
+		case "/repos/owner/repo/actions/artifacts/21/zip":
+			if r.Header.Get("Authorization") != "Bearer test-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write(archive)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})))
+}
+
+func TestGhaLatestRun(t *testing.T) {
+	newTestGhaServer(t)
+
+	run, err := ghaLatestRun("owner/repo", "build.yml", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), run.ID)
+
+	run, err = ghaLatestRun("owner/repo", "build.yml", "dev")
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), run.ID)
+
+	_, err = ghaLatestRun("owner/repo", "missing.yml", "")
+	assert.Error(t, err)
+}
+
+func TestGhaSelectArtifact(t *testing.T) {
+	newTestGhaServer(t)
+	run := WorkflowRun{ID: 200, RunNumber: 12}
+
+	artifact, err := ghaSelectArtifact("owner/repo", run, "jars")
+	require.NoError(t, err)
+	assert.Equal(t, int64(21), artifact.ID)
+
+	_, err = ghaSelectArtifact("owner/repo", run, "")
+	assert.Error(t, err, "more than one artifact")
+	_, err = ghaSelectArtifact("owner/repo", run, "^old-jars$")
+	assert.Error(t, err, "expired artifacts are skipped")
+}
+
+func TestGhaOpenArtifactFile(t *testing.T) {
+	newTestGhaServer(t)
+
+	t.Run("requires a token", func(t *testing.T) {
+		withGhToken(t, "")
+		_, err := ghaOpenArtifactFile("owner/repo", 21, "", ghDefaultAssetRegex)
+		assert.ErrorContains(t, err, "token")
+	})
+
+	withGhToken(t, "test-token")
+
+	t.Run("by regex", func(t *testing.T) {
+		file, err := ghaOpenArtifactFile("owner/repo", 21, "", ghDefaultAssetRegex)
+		require.NoError(t, err)
+		defer file.Close()
+		assert.Equal(t, "build/libs/mod-1.2.jar", file.Path)
+		data, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, ghaTestJar, string(data))
+	})
+
+	t.Run("by path", func(t *testing.T) {
+		file, err := ghaOpenArtifactFile("owner/repo", 21, "README.md", ghDefaultAssetRegex)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		_, err = ghaOpenArtifactFile("owner/repo", 21, "missing.jar", ghDefaultAssetRegex)
+		assert.Error(t, err)
+	})
+
+	t.Run("ambiguous regex", func(t *testing.T) {
+		_, err := ghaOpenArtifactFile("owner/repo", 21, "", `\.jar$`)
+		assert.Error(t, err)
+	})
+}
+
+func TestGitHubActionsNewMod(t *testing.T) {
+	newTestGhaServer(t)
+	withGhToken(t, "test-token")
+
+	mod, err := GitHubActionsNewMod("https://github.com/owner/repo", "build.yml", "", "jars", "", "mods")
+	require.NoError(t, err)
+	assert.Equal(t, "mod-1.2.jar", mod.FileName)
+	assert.Equal(t, "run #12", mod.Version)
+	assert.Equal(t, core.ModeGitHubActions, mod.Download.Mode)
+	assert.Empty(t, mod.Download.URL)
+	sum := sha256.Sum256([]byte(ghaTestJar))
+	assert.Equal(t, hex.EncodeToString(sum[:]), mod.Download.Hash)
+	assert.Equal(t, core.ModSourceData{
+		"slug":           "owner/repo",
+		"workflow":       "build.yml",
+		"artifact-regex": "jars",
+		"run-id":         int64(200),
+		"artifact-id":    int64(21),
+		"file":           "build/libs/mod-1.2.jar",
+	}, mod.Update["github-actions"])
+
+	_, err = GitHubActionsNewMod("owner/repo", "", "", "jars", "", "mods")
+	assert.Error(t, err)
+}
@@ -0,0 +1,111 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// Options understood by the GitHub Actions Source, in core.SourceRequest.Options, in
+// addition to GhOptionBranch (restricting runs to a branch) and GhOptionRegex (selecting
+// the file inside the artifact)
+const (
+	// GhaOptionWorkflow is the workflow's file name (e.g. "build.yml") or ID (required)
+	GhaOptionWorkflow = "workflow"
+	// GhaOptionArtifact is the regular expression artifact names are matched against
+	GhaOptionArtifact = "artifact"
+)
+
+// ghaSourceVersion is the GitHub Actions-specific data stored in core.SourceVersion.Data
+type ghaSourceVersion struct {
+	Repo     Repo
+	Run      WorkflowRun
+	Artifact Artifact
+}
+
+type ghaSource struct{}
+
+func (s ghaSource) GetName() string {
+	return "github-actions"
+}
+
+func (s ghaSource) Search(string, core.SourceRequest) ([]core.SourceProject, error) {
+	return nil, fmt.Errorf("%w: GitHub Actions does not support searching for projects", core.ErrSourceUnavailable)
+}
+
+func (s ghaSource) ResolveProject(identifier string, _ core.SourceRequest) (core.SourceProject, error) {
+	repo, err := fetchRepo(ghSlugFromUrl(identifier))
+	if err != nil {
+		return core.SourceProject{}, err
+	}
+
+	return core.SourceProject{
+		Source: "github-actions",
+		ID:     repo.FullName,
+		Slug:   core.SlugifyName(repo.Name),
+		Name:   repo.Name,
+		Data:   repo,
+	}, nil
+}
+
+// ListVersions returns only the latest successful run, as older artifacts soon expire
+func (s ghaSource) ListVersions(project core.SourceProject, req core.SourceRequest) ([]core.SourceVersion, error) {
+	repo, ok := project.Data.(Repo)
+	if !ok {
+		return nil, errors.New("not a GitHub project")
+	}
+
+	workflow := req.Option(GhaOptionWorkflow)
+	if workflow == "" {
+		return nil, errors.New("a workflow file name or ID is required")
+	}
+
+	run, err := ghaLatestRun(repo.FullName, workflow, req.Option(GhOptionBranch))
+	if err != nil {
+		return nil, err
+	}
+	artifact, err := ghaSelectArtifact(repo.FullName, run, req.Option(GhaOptionArtifact))
+	if err != nil {
+		return nil, err
+	}
+
+	return []core.SourceVersion{{
+		ID:      strconv.FormatInt(run.ID, 10),
+		Version: ghaRunVersion(run),
+		Data:    ghaSourceVersion{Repo: repo, Run: run, Artifact: artifact},
+	}}, nil
+}
+
+func (s ghaSource) SelectVersion(_ core.SourceProject, versions []core.SourceVersion, _ core.SourceRequest) (core.SourceVersion, error) {
+	if len(versions) == 0 {
+		return core.SourceVersion{}, errors.New("no runs found")
+	}
+	return versions[0], nil
+}
+
+func (s ghaSource) NewMod(_ core.SourceProject, version core.SourceVersion, req core.SourceRequest) (*core.Mod, error) {
+	data, ok := version.Data.(ghaSourceVersion)
+	if !ok {
+		return nil, errors.New("not a GitHub Actions run")
+	}
+
+	modType := req.MetaFolder
+	if modType == "" {
+		modType = "mods"
+	}
+
+	return ghaInstallRun(data.Repo, ghaUpdateData{
+		Slug:          data.Repo.FullName,
+		Workflow:      req.Option(GhaOptionWorkflow),
+		Branch:        req.Option(GhOptionBranch),
+		ArtifactRegex: req.Option(GhaOptionArtifact),
+		Regex:         req.Option(GhOptionRegex),
+	}, data.Run, data.Artifact, modType)
+}
+
+func (s ghaSource) FindMissingDependencies(core.SourceVersion, core.SourceRequest) ([]*core.Mod, error) {
+	// Workflow runs carry no dependency information
+	return nil, nil
+}
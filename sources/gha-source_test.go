@@ -0,0 +1,52 @@
+package sources
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func TestGhaSource(t *testing.T) {
+	newTestGhaServer(t)
+	withGhToken(t, "test-token")
+
+	src := ghaSource{}
+
+	t.Run("search is unavailable", func(t *testing.T) {
+		_, err := src.Search("repo", core.SourceRequest{})
+		assert.True(t, errors.Is(err, core.ErrSourceUnavailable))
+	})
+
+	t.Run("workflow is required", func(t *testing.T) {
+		_, _, err := core.NewModFromSource(src, "owner/repo", core.SourceRequest{})
+		assert.Error(t, err)
+	})
+
+	t.Run("installs the latest run", func(t *testing.T) {
+		req := core.SourceRequest{Options: map[string]string{
+			GhaOptionWorkflow: "build.yml",
+			GhaOptionArtifact: "jars",
+		}}
+		mod, version, err := core.NewModFromSource(src, "owner/repo", req)
+		require.NoError(t, err)
+		assert.Equal(t, "200", version.ID)
+		assert.Equal(t, "run #12", version.Version)
+		assert.Equal(t, "mod-1.2.jar", mod.FileName)
+		assert.Equal(t, "mods", mod.ModType)
+		assert.Equal(t, "build.yml", mod.Update["github-actions"]["workflow"])
+		assert.NotContains(t, mod.Update["github-actions"], "branch")
+	})
+
+	t.Run("branch without artifacts", func(t *testing.T) {
+		req := core.SourceRequest{Options: map[string]string{
+			GhaOptionWorkflow: "build.yml",
+			GhOptionBranch:    "dev",
+		}}
+		_, _, err := core.NewModFromSource(src, "owner/repo", req)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,179 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func init() {
+	RegisterGithubActions(core.DefaultRegistry)
+}
+
+// RegisterGithubActions registers the GitHub Actions Updater/Source/MetaDownloader on reg.
+// Library consumers building an isolated *core.Registry (instead of relying on
+// core.DefaultRegistry) should call this - or sources.RegisterAll - explicitly.
+func RegisterGithubActions(reg *core.Registry) {
+	reg.AddUpdater(ghaUpdater{})
+	reg.AddSource(ghaSource{})
+	reg.AddMetaDownloader("github-actions", ghaDownloader{})
+}
+
+type ghaUpdateData struct {
+	Slug string `mapstructure:"slug"`
+	// Workflow is the workflow's file name or ID
+	Workflow string `mapstructure:"workflow"`
+	// Branch, if set, restricts runs to those on it
+	Branch string `mapstructure:"branch,omitempty"`
+	// ArtifactRegex selects the artifact, if runs upload more than one
+	ArtifactRegex string `mapstructure:"artifact-regex,omitempty"`
+	// Regex selects the file inside the artifact, ghDefaultAssetRegex if unset
+	Regex string `mapstructure:"regex,omitempty"`
+	// RunID is the installed run, used as the version
+	RunID      int64 `mapstructure:"run-id"`
+	ArtifactID int64 `mapstructure:"artifact-id"`
+	// File is the installed file's path inside the artifact
+	File string `mapstructure:"file"`
+}
+
+func (u ghaUpdateData) ToMap() (map[string]interface{}, error) {
+	newMap := make(map[string]interface{})
+	err := mapstructure.Decode(u, &newMap)
+	return newMap, err
+}
+
+func (u ghaUpdateData) fileRegex() string {
+	if u.Regex == "" {
+		return ghDefaultAssetRegex
+	}
+	return u.Regex
+}
+
+type ghaUpdater struct{}
+
+func (u ghaUpdater) GetName() string {
+	return "github-actions"
+}
+
+func (u ghaUpdater) ParseUpdate(updateUnparsed map[string]interface{}) (interface{}, error) {
+	var updateData ghaUpdateData
+	err := mapstructure.Decode(updateUnparsed, &updateData)
+	return updateData, err
+}
+
+type ghaCachedStateStore struct {
+	Run      WorkflowRun
+	Artifact Artifact
+}
+
+func (u ghaUpdater) CheckUpdate(mods []*core.Mod, _ core.Pack) ([]core.UpdateCheck, error) {
+	results := make([]core.UpdateCheck, len(mods))
+
+	for i, mod := range mods {
+		var data ghaUpdateData
+		err := mod.DecodeNamedModSourceData("github-actions", &data)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: errors.New("failed to parse update metadata")}
+			continue
+		}
+
+		run, err := ghaLatestRun(data.Slug, data.Workflow, data.Branch)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: fmt.Errorf("failed to get latest run: %v", err)}
+			continue
+		}
+
+		// Runs have no version numbers to compare against a version constraint
+		constraintIgnored := mod.VersionConstraint != ""
+
+		// Run IDs increase over time, so an older run (e.g. after a re-run) isn't an update
+		if run.ID <= data.RunID {
+			results[i] = core.UpdateCheck{UpdateAvailable: false, ConstraintIgnored: constraintIgnored}
+			continue
+		}
+
+		artifact, err := ghaSelectArtifact(data.Slug, run, data.ArtifactRegex)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: err}
+			continue
+		}
+
+		// The new file name isn't known until the artifact is downloaded, so the run and its
+		// commit are shown instead if the installed version isn't recorded
+		sha := run.HeadSha
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		version := ghaRunVersion(run)
+		results[i] = core.UpdateCheck{
+			UpdateAvailable:   true,
+			UpdateString:      mod.DescribeUpdate(version, fmt.Sprintf("%s (%s)", version, sha)),
+			ConstraintIgnored: constraintIgnored,
+			CachedState:       ghaCachedStateStore{Run: run, Artifact: artifact},
+		}
+	}
+
+	return results, nil
+}
+
+func (u ghaUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
+	for i, mod := range mods {
+		modState := cachedState[i].(ghaCachedStateStore)
+
+		var data ghaUpdateData
+		err := mod.DecodeNamedModSourceData("github-actions", &data)
+		if err != nil {
+			return err
+		}
+
+		filePath, hash, err := ghaHashArtifactFile(data, modState.Artifact.ID)
+		if err != nil {
+			return err
+		}
+
+		mod.FileName = path.Base(filePath)
+		mod.Version = ghaRunVersion(modState.Run)
+		mod.Download = core.ModDownload{
+			HashFormat: "sha256",
+			Hash:       hash,
+			Mode:       core.ModeGitHubActions,
+		}
+		mod.Update["github-actions"]["run-id"] = modState.Run.ID
+		mod.Update["github-actions"]["artifact-id"] = modState.Artifact.ID
+		mod.Update["github-actions"]["file"] = filePath
+	}
+
+	return nil
+}
+
+type ghaDownloader struct{}
+
+func (d ghaDownloader) GetFilesMetadata(mods []*core.Mod) ([]core.MetaDownloaderData, error) {
+	downloaderData := make([]core.MetaDownloaderData, len(mods))
+	for i, mod := range mods {
+		var data ghaUpdateData
+		err := mod.DecodeNamedModSourceData("github-actions", &data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GitHub Actions update metadata from %s", mod.Name)
+		}
+		downloaderData[i] = ghaDownloadMetadata{data}
+	}
+	return downloaderData, nil
+}
+
+type ghaDownloadMetadata struct {
+	data ghaUpdateData
+}
+
+func (m ghaDownloadMetadata) GetManualDownload() (bool, core.ManualDownload) {
+	return false, core.ManualDownload{}
+}
+
+func (m ghaDownloadMetadata) DownloadFile() (io.ReadCloser, error) {
+	return ghaOpenArtifactFile(m.data.Slug, m.data.ArtifactID, m.data.File, m.data.fileRegex())
+}
@@ -0,0 +1,85 @@
+package sources
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func ghaTestMod(runID int64) *core.Mod {
+	return &core.Mod{
+		Name:     "Test Mod",
+		FileName: "mod-1.1.jar",
+		Update: core.ModUpdate{
+			"github-actions": core.ModSourceData{
+				"slug":           "owner/repo",
+				"workflow":       "build.yml",
+				"artifact-regex": "jars",
+				"run-id":         runID,
+				"artifact-id":    int64(21),
+				"file":           "build/libs/mod-1.2.jar",
+			},
+		},
+	}
+}
+
+func TestGhaUpdater_CheckUpdate(t *testing.T) {
+	newTestGhaServer(t)
+
+	constrained := ghaTestMod(200)
+	constrained.VersionConstraint = "<2"
+	versioned := ghaTestMod(100)
+	versioned.Version = "run #11"
+	mods := []*core.Mod{ghaTestMod(100), ghaTestMod(200), {Name: "Bad Mod", Update: core.ModUpdate{"github-actions": nil}}, constrained, versioned}
+	results, err := ghaUpdater{}.CheckUpdate(mods, core.Pack{})
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	assert.True(t, results[0].UpdateAvailable)
+	assert.Equal(t, "mod-1.1.jar -> run #12 (abcdef1)", results[0].UpdateString)
+	assert.False(t, results[1].UpdateAvailable)
+	assert.NoError(t, results[1].Error)
+	assert.Error(t, results[2].Error)
+	assert.False(t, results[1].ConstraintIgnored)
+	assert.True(t, results[3].ConstraintIgnored, "runs have no versions to constrain")
+	assert.Equal(t, "run #11 -> run #12", results[4].UpdateString)
+}
+
+func TestGhaUpdater_DoUpdate(t *testing.T) {
+	newTestGhaServer(t)
+	withGhToken(t, "test-token")
+
+	mod := ghaTestMod(100)
+	state := ghaCachedStateStore{Run: WorkflowRun{ID: 200, RunNumber: 12}, Artifact: Artifact{ID: 21, Name: "mod-jars"}}
+	require.NoError(t, ghaUpdater{}.DoUpdate([]*core.Mod{mod}, []interface{}{state}))
+
+	assert.Equal(t, "mod-1.2.jar", mod.FileName)
+	assert.Equal(t, "run #12", mod.Version)
+	assert.Equal(t, core.ModeGitHubActions, mod.Download.Mode)
+	assert.NotEmpty(t, mod.Download.Hash)
+	assert.Equal(t, int64(200), mod.Update["github-actions"]["run-id"])
+	assert.Equal(t, int64(21), mod.Update["github-actions"]["artifact-id"])
+}
+
+func TestGhaDownloader(t *testing.T) {
+	newTestGhaServer(t)
+	withGhToken(t, "test-token")
+
+	meta, err := ghaDownloader{}.GetFilesMetadata([]*core.Mod{ghaTestMod(200)})
+	require.NoError(t, err)
+	require.Len(t, meta, 1)
+
+	manual, _ := meta[0].GetManualDownload()
+	assert.False(t, manual)
+
+	file, err := meta[0].DownloadFile()
+	require.NoError(t, err)
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, ghaTestJar, string(data))
+}
@@ -0,0 +1,120 @@
+package sources
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/leocov-dev/packwiz-nxt/config"
+	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+)
+
+// giteaDefaultHost is used when no host is given; Codeberg is the largest public Forgejo instance
+const giteaDefaultHost = "codeberg.org"
+
+// giteaReleasesPageSize is the number of releases fetched per request (the default
+// maximum page size of Gitea servers)
+const giteaReleasesPageSize = 50
+
+type giteaApiClient struct {
+	httpClient *http.Client
+	logger     core.Logger
+}
+
+var giteaDefaultClient = *NewGiteaClient(&http.Client{Timeout: core.DefaultHTTPTimeout}, core.PrintLogger{})
+
+// NewGiteaClient constructs a Gitea/Forgejo API client using the given httpClient. The
+// same client is used for every host.
+func NewGiteaClient(httpClient *http.Client, logger core.Logger) *giteaApiClient {
+	return &giteaApiClient{httpClient, logger}
+}
+
+// GetGiteaClient returns the default Gitea/Forgejo API client, mirroring GetGithubClient.
+func GetGiteaClient() *giteaApiClient {
+	return &giteaDefaultClient
+}
+
+// SetLogger overrides the client's logger, used to report non-fatal warnings/progress.
+func (c *giteaApiClient) SetLogger(l core.Logger) {
+	c.logger = l
+}
+
+// makeGet requests url, authenticating with the token configured for host (see
+// config.SetGiteaApiKey) if there is one. The token is only sent if url is on host, so it
+// isn't leaked to other servers (e.g. assets hosted elsewhere).
+func (c *giteaApiClient) makeGet(host string, url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", core.UserAgent)
+	req.Header.Set("Accept", "application/json")
+	if token := config.GetGiteaApiKey(host); token != "" && strings.EqualFold(req.URL.Host, host) {
+		req.Header.Set("Authorization", "token "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("invalid response status: %v", resp.Status)
+	}
+	return resp, nil
+}
+
+func (c *giteaApiClient) getJson(host string, path string, v any) error {
+	resp, err := c.makeGet(host, "https://"+host+"/api/v1"+path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+func (c *giteaApiClient) getRepo(host string, slug string) (Repo, error) {
+	var repo Repo
+	if err := c.getJson(host, "/repos/"+slug, &repo); err != nil {
+		return repo, err
+	}
+	if repo.FullName == "" {
+		return repo, errors.New("invalid json while fetching project: " + slug)
+	}
+	return repo, nil
+}
+
+// getReleases fetches the most recent releases of a repository, newest first
+func (c *giteaApiClient) getReleases(host string, slug string) ([]Release, error) {
+	query := url.Values{}
+	query.Set("limit", fmt.Sprint(giteaReleasesPageSize))
+
+	var releases []Release
+	if err := c.getJson(host, "/repos/"+slug+"/releases?"+query.Encode(), &releases); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// hashAsset downloads an asset (authenticating against host if the asset is hosted there)
+// into the shared download cache, and returns its sha256 hash
+func (c *giteaApiClient) hashAsset(host string, asset Asset) (string, error) {
+	resp, err := c.makeGet(host, asset.BrowserDownloadURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", asset.BrowserDownloadURL, err)
+	}
+	defer resp.Body.Close()
+
+	return fileio.StoreInCache(resp.Body)
+}
@@ -0,0 +1,92 @@
+package sources
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/config"
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// withGiteaClient swaps the package-level Gitea client singleton for the
+// duration of the test, restoring the original afterward.
+func withGiteaClient(t *testing.T, httpClient *http.Client) {
+	t.Helper()
+	original := giteaDefaultClient
+	giteaDefaultClient = *NewGiteaClient(httpClient, core.NoopLogger{})
+	t.Cleanup(func() { giteaDefaultClient = original })
+}
+
+// newTestGiteaServer serves owner/repo, with a v2.0 prerelease on "dev" and a v1.0 release on
+// "main". The Authorization header of every request is recorded in auth.
+func newTestGiteaServer(t *testing.T, auth *[]string) {
+	t.Helper()
+	withGiteaClient(t, newTestHTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*auth = append(*auth, r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/v1/repos/owner/repo":
+			_, _ = w.Write([]byte(`{"id":1,"name":"repo","full_name":"owner/repo"}`))
+		case "/api/v1/repos/owner/repo/releases":
+			_, _ = w.Write([]byte(`[` +
+				`{"tag_name":"v3.0","target_commitish":"main","draft":true,"assets":[{"name":"mod-3.0.jar","browser_download_url":"https://codeberg.org/owner/repo/releases/download/v3.0/mod-3.0.jar"}]},` +
+				`{"tag_name":"v2.0","target_commitish":"dev","prerelease":true,"assets":[{"name":"mod-2.0.jar","browser_download_url":"https://codeberg.org/owner/repo/releases/download/v2.0/mod-2.0.jar"},{"name":"mod-2.0-sources.jar","browser_download_url":"https://codeberg.org/owner/repo/releases/download/v2.0/mod-2.0-sources.jar"}]},` +
+				`{"tag_name":"v1.0","target_commitish":"main","assets":[{"name":"mod-1.0.jar","browser_download_url":"https://codeberg.org/owner/repo/releases/download/v1.0/mod-1.0.jar"}]}` +
+				`]`))
+		case "/owner/repo/releases/download/v2.0/mod-2.0.jar", "/owner/repo/releases/download/v1.0/mod-1.0.jar":
+			_, _ = w.Write([]byte("jar contents"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})))
+}
+
+func TestGiteaApiClient(t *testing.T) {
+	var auth []string
+	newTestGiteaServer(t, &auth)
+
+	t.Run("anonymous without a token", func(t *testing.T) {
+		auth = nil
+		repo, err := giteaDefaultClient.getRepo("codeberg.org", "owner/repo")
+		require.NoError(t, err)
+		assert.Equal(t, "owner/repo", repo.FullName)
+		assert.Equal(t, []string{""}, auth)
+	})
+
+	t.Run("uses the token for the host", func(t *testing.T) {
+		config.SetGiteaApiKey("codeberg.org", "secret")
+		t.Cleanup(func() { config.SetGiteaApiKey("codeberg.org", "") })
+
+		auth = nil
+		releases, err := giteaDefaultClient.getReleases("codeberg.org", "owner/repo")
+		require.NoError(t, err)
+		assert.Len(t, releases, 3)
+		_, err = giteaDefaultClient.getRepo("git.example.com", "owner/repo")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"token secret", ""}, auth)
+	})
+
+	t.Run("asset downloads only send the token to the host", func(t *testing.T) {
+		withTestCache(t)
+		config.SetGiteaApiKey("codeberg.org", "secret")
+		t.Cleanup(func() { config.SetGiteaApiKey("codeberg.org", "") })
+
+		auth = nil
+		hash, err := giteaDefaultClient.hashAsset("codeberg.org", Asset{BrowserDownloadURL: "https://codeberg.org/owner/repo/releases/download/v1.0/mod-1.0.jar"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, hash)
+		_, err = giteaDefaultClient.hashAsset("codeberg.org", Asset{BrowserDownloadURL: "https://cdn.example.com/owner/repo/releases/download/v1.0/mod-1.0.jar"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"token secret", ""}, auth)
+
+		_, err = giteaDefaultClient.hashAsset("codeberg.org", Asset{BrowserDownloadURL: "https://codeberg.org/owner/repo/releases/download/v1.0/missing.jar"})
+		assert.ErrorContains(t, err, "404")
+	})
+
+	t.Run("non-200 status is an error", func(t *testing.T) {
+		_, err := giteaDefaultClient.getRepo("codeberg.org", "owner/missing")
+		assert.ErrorContains(t, err, "404")
+	})
+}
@@ -0,0 +1,120 @@
+package sources
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+var giteaUrlRegex = regexp.MustCompile(`^https?://([^/]+)/([^/]+/[^/]+?)(?:\.git)?(?:/.*)?$`)
+
+// GiteaParseUrl splits a Gitea/Forgejo repository URL into its host and owner/repo slug.
+// An owner/repo slug is returned unchanged with defaultHost, or codeberg.org if that is
+// empty.
+func GiteaParseUrl(slugOrUrl string, defaultHost string) (host string, slug string, err error) {
+	if matches := giteaUrlRegex.FindStringSubmatch(slugOrUrl); matches != nil {
+		return matches[1], matches[2], nil
+	}
+	if !ghSlugRegex.MatchString(slugOrUrl) {
+		return "", "", fmt.Errorf("not a repository URL or owner/repo slug: %s", slugOrUrl)
+	}
+	if defaultHost == "" {
+		defaultHost = giteaDefaultHost
+	}
+	return strings.TrimSuffix(defaultHost, "/"), slugOrUrl, nil
+}
+
+// getGiteaReleasesForBranch fetches the releases of a repository on a Gitea/Forgejo host,
+// newest first, restricted to those targeting branch if it is non-empty. Prereleases are
+// included, as the GitHub source's prerelease and tag options aren't supported for Gitea,
+// but drafts aren't, as they aren't published yet.
+func getGiteaReleasesForBranch(host string, slug string, branch string) ([]Release, error) {
+	releases, err := giteaDefaultClient.getReleases(host, slug)
+	if err != nil {
+		return nil, err
+	}
+	return ghReleaseFilter{Branch: branch, Prereleases: true}.apply(releases, slug)
+}
+
+// GiteaNewMod creates a Mod from the latest release of a Gitea/Forgejo repository, in the
+// same way as GitHubNewMod. slugOrUrl is a repository URL, or an owner/repo slug on host.
+func GiteaNewMod(host, slugOrUrl, branch, regex, modType string) (*core.Mod, error) {
+	host, slug, err := GiteaParseUrl(slugOrUrl, host)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := giteaDefaultClient.getRepo(host, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if regex == "" {
+		regex = ghDefaultAssetRegex
+	}
+
+	releases, err := getGiteaReleasesForBranch(host, repo.FullName, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest release: %v", err)
+	}
+
+	return giteaInstallRelease(host, repo, releases[0], branch, regex, modType)
+}
+
+// giteaInstallRelease creates a Mod from release. branch is recorded only if the user
+// restricted releases to it, so that updates otherwise follow releases from any branch.
+func giteaInstallRelease(
+	host string,
+	repo Repo,
+	release Release,
+	branch string,
+	regex string,
+	modType string,
+) (*core.Mod, error) {
+	file, err := selectReleaseAsset(release.Assets, regex)
+	if err != nil {
+		return nil, err
+	}
+
+	GetGiteaClient().logger.Infof("Installing %s from release %s\n", file.Name, release.TagName)
+
+	updateMap := make(core.ModUpdate)
+	updateMap["gitea"], err = giteaUpdateData{
+		Host:   host,
+		Slug:   repo.FullName,
+		Tag:    release.TagName,
+		Branch: branch,
+		Regex:  regex,
+	}.ToMap()
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := giteaDefaultClient.hashAsset(host, file)
+	if err != nil {
+		return nil, err
+	}
+
+	mod := core.NewMod(
+		core.SlugifyName(repo.Name),
+		repo.Name,
+		file.Name,
+		core.UniversalSide,
+		modType,
+		"",
+		false,
+		false,
+		updateMap,
+		core.ModDownload{
+			URL:        file.BrowserDownloadURL,
+			HashFormat: "sha256",
+			Hash:       hash,
+			Size:       file.Size,
+		},
+		nil,
+	)
+	mod.Version = release.TagName
+	return mod, nil
+}
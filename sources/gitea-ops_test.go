@@ -0,0 +1,61 @@
+package sources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGiteaParseUrl(t *testing.T) {
+	cases := []struct {
+		input       string
+		defaultHost string
+		host        string
+		slug        string
+	}{
+		{"https://codeberg.org/jmansfield/go-modrinth", "", "codeberg.org", "jmansfield/go-modrinth"},
+		{"https://git.example.com/owner/repo.git", "", "git.example.com", "owner/repo"},
+		{"https://git.example.com/owner/repo/releases", "", "git.example.com", "owner/repo"},
+		{"owner/repo", "", "codeberg.org", "owner/repo"},
+		{"owner/repo", "git.example.com", "git.example.com", "owner/repo"},
+	}
+	for _, tc := range cases {
+		host, slug, err := GiteaParseUrl(tc.input, tc.defaultHost)
+		require.NoError(t, err, tc.input)
+		assert.Equal(t, tc.host, host, tc.input)
+		assert.Equal(t, tc.slug, slug, tc.input)
+	}
+
+	_, _, err := GiteaParseUrl("not a repo", "")
+	assert.Error(t, err)
+}
+
+func TestGiteaNewMod(t *testing.T) {
+	var auth []string
+	newTestGiteaServer(t, &auth)
+
+	t.Run("latest release from any branch", func(t *testing.T) {
+		mod, err := GiteaNewMod("", "https://codeberg.org/owner/repo", "", "", "mods")
+		require.NoError(t, err)
+		assert.Equal(t, "mod-2.0.jar", mod.FileName)
+		assert.Equal(t, "repo", mod.Slug)
+		assert.Equal(t, "sha256", mod.Download.HashFormat)
+		assert.NotEmpty(t, mod.Download.Hash)
+		assert.Equal(t, "codeberg.org", mod.Update["gitea"]["host"])
+		assert.Equal(t, "v2.0", mod.Update["gitea"]["tag"])
+		assert.Equal(t, "", mod.Update["gitea"]["branch"])
+	})
+
+	t.Run("branch restricts releases and is recorded", func(t *testing.T) {
+		mod, err := GiteaNewMod("codeberg.org", "owner/repo", "main", "", "mods")
+		require.NoError(t, err)
+		assert.Equal(t, "mod-1.0.jar", mod.FileName)
+		assert.Equal(t, "main", mod.Update["gitea"]["branch"])
+	})
+
+	t.Run("unknown branch", func(t *testing.T) {
+		_, err := GiteaNewMod("", "owner/repo", "missing", "", "mods")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,111 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// Options understood by the Gitea Source, in core.SourceRequest.Options. GhOptionBranch and
+// GhOptionRegex are also understood, as for GitHub.
+const (
+	// GiteaOptionHost is the Gitea/Forgejo server used for owner/repo slugs (codeberg.org by default)
+	GiteaOptionHost = "host"
+)
+
+// giteaSourceProject is the Gitea-specific data stored in core.SourceProject.Data
+type giteaSourceProject struct {
+	Host string
+	Repo Repo
+}
+
+// giteaSourceVersion is the Gitea-specific data stored in core.SourceVersion.Data
+type giteaSourceVersion struct {
+	Host    string
+	Repo    Repo
+	Release Release
+}
+
+type giteaSource struct{}
+
+func (s giteaSource) GetName() string {
+	return "gitea"
+}
+
+func (s giteaSource) Search(string, core.SourceRequest) ([]core.SourceProject, error) {
+	return nil, fmt.Errorf("%w: Gitea does not support searching for projects", core.ErrSourceUnavailable)
+}
+
+func (s giteaSource) ResolveProject(identifier string, req core.SourceRequest) (core.SourceProject, error) {
+	host, slug, err := GiteaParseUrl(identifier, req.Option(GiteaOptionHost))
+	if err != nil {
+		return core.SourceProject{}, err
+	}
+
+	repo, err := giteaDefaultClient.getRepo(host, slug)
+	if err != nil {
+		return core.SourceProject{}, err
+	}
+
+	return core.SourceProject{
+		Source: "gitea",
+		ID:     host + "/" + repo.FullName,
+		Slug:   core.SlugifyName(repo.Name),
+		Name:   repo.Name,
+		Data:   giteaSourceProject{Host: host, Repo: repo},
+	}, nil
+}
+
+func (s giteaSource) ListVersions(project core.SourceProject, req core.SourceRequest) ([]core.SourceVersion, error) {
+	data, ok := project.Data.(giteaSourceProject)
+	if !ok {
+		return nil, errors.New("not a Gitea project")
+	}
+
+	releases, err := getGiteaReleasesForBranch(data.Host, data.Repo.FullName, req.Option(GhOptionBranch))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get releases: %w", err)
+	}
+
+	versions := make([]core.SourceVersion, 0, len(releases))
+	for _, release := range releases {
+		versions = append(versions, core.SourceVersion{
+			ID:      release.TagName,
+			Version: release.TagName,
+			Data:    giteaSourceVersion{Host: data.Host, Repo: data.Repo, Release: release},
+		})
+	}
+	return versions, nil
+}
+
+func (s giteaSource) SelectVersion(_ core.SourceProject, versions []core.SourceVersion, _ core.SourceRequest) (core.SourceVersion, error) {
+	// Gitea lists releases newest first
+	if len(versions) == 0 {
+		return core.SourceVersion{}, errors.New("no releases found")
+	}
+	return versions[0], nil
+}
+
+func (s giteaSource) NewMod(_ core.SourceProject, version core.SourceVersion, req core.SourceRequest) (*core.Mod, error) {
+	data, ok := version.Data.(giteaSourceVersion)
+	if !ok {
+		return nil, errors.New("not a Gitea release")
+	}
+
+	regex := req.Option(GhOptionRegex)
+	if regex == "" {
+		regex = ghDefaultAssetRegex
+	}
+	modType := req.MetaFolder
+	if modType == "" {
+		modType = "mods"
+	}
+
+	return giteaInstallRelease(data.Host, data.Repo, data.Release, req.Option(GhOptionBranch), regex, modType)
+}
+
+func (s giteaSource) FindMissingDependencies(core.SourceVersion, core.SourceRequest) ([]*core.Mod, error) {
+	// Gitea releases carry no dependency information
+	return nil, nil
+}
@@ -0,0 +1,52 @@
+package sources
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func TestGiteaSource(t *testing.T) {
+	var auth []string
+	newTestGiteaServer(t, &auth)
+
+	src := giteaSource{}
+
+	t.Run("installs the latest release from a URL", func(t *testing.T) {
+		mod, version, err := core.NewModFromSource(src, "https://codeberg.org/owner/repo", core.SourceRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, "v2.0", version.Version)
+		assert.Equal(t, "mod-2.0.jar", mod.FileName)
+		assert.Equal(t, "mods", mod.ModType)
+	})
+
+	t.Run("host, branch and regex options", func(t *testing.T) {
+		req := core.SourceRequest{
+			MetaFolder: "plugins",
+			Options: map[string]string{
+				GiteaOptionHost: "git.example.com",
+				GhOptionBranch:  "main",
+				GhOptionRegex:   `\.jar$`,
+			},
+		}
+		project, err := src.ResolveProject("owner/repo", req)
+		require.NoError(t, err)
+		assert.Equal(t, "git.example.com/owner/repo", project.ID)
+
+		mod, _, err := core.NewModFromSourceProject(src, project, req)
+		require.NoError(t, err)
+		assert.Equal(t, "mod-1.0.jar", mod.FileName)
+		assert.Equal(t, "plugins", mod.ModType)
+		assert.Equal(t, "git.example.com", mod.Update["gitea"]["host"])
+		assert.Equal(t, `\.jar$`, mod.Update["gitea"]["regex"])
+	})
+
+	t.Run("search is unavailable", func(t *testing.T) {
+		_, err := src.Search("repo", core.SourceRequest{})
+		assert.True(t, errors.Is(err, core.ErrSourceUnavailable))
+	})
+}
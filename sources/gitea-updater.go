@@ -0,0 +1,138 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func init() {
+	RegisterGitea(core.DefaultRegistry)
+}
+
+// RegisterGitea registers the Gitea/Forgejo Updater/Source on reg. Library consumers building an
+// isolated *core.Registry (instead of relying on core.DefaultRegistry) should call this
+// - or sources.RegisterAll - explicitly.
+func RegisterGitea(reg *core.Registry) {
+	reg.AddUpdater(giteaUpdater{})
+	reg.AddSource(giteaSource{})
+}
+
+type giteaUpdateData struct {
+	Host   string `mapstructure:"host"`
+	Slug   string `mapstructure:"slug"`
+	Tag    string `mapstructure:"tag"`
+	Branch string `mapstructure:"branch"`
+	Regex  string `mapstructure:"regex"`
+}
+
+func (u giteaUpdateData) ToMap() (map[string]interface{}, error) {
+	newMap := make(map[string]interface{})
+	err := mapstructure.Decode(u, &newMap)
+	return newMap, err
+}
+
+type giteaUpdater struct{}
+
+func (u giteaUpdater) GetName() string {
+	return "gitea"
+}
+
+func (u giteaUpdater) ParseUpdate(updateUnparsed map[string]interface{}) (interface{}, error) {
+	var updateData giteaUpdateData
+	err := mapstructure.Decode(updateUnparsed, &updateData)
+	return updateData, err
+}
+
+type giteaCachedStateStore struct {
+	Host  string
+	Tag   string
+	Asset Asset
+}
+
+func (u giteaUpdater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateCheck, error) {
+	results := make([]core.UpdateCheck, len(mods))
+	// Only used to skip Minecraft versions in tags, so packs without one are still updated
+	mcVersions, _ := pack.GetSupportedMCVersions()
+
+	for i, mod := range mods {
+		var data giteaUpdateData
+		err := mod.DecodeNamedModSourceData("gitea", &data)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: errors.New("failed to parse update metadata")}
+			continue
+		}
+		if data.Host == "" {
+			data.Host = giteaDefaultHost
+		}
+
+		releases, err := getGiteaReleasesForBranch(data.Host, data.Slug, data.Branch)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: fmt.Errorf("failed to get latest release: %v", err)}
+			continue
+		}
+		heldBack := ""
+		accepted, err := core.FilterAcceptedVersions(mod, mcVersions, releases, func(r Release) string {
+			return ghTagVersion(r.TagName)
+		})
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: err}
+			continue
+		}
+		if len(accepted) == 0 || accepted[0].TagName != releases[0].TagName {
+			heldBack = releases[0].TagName
+		}
+		if len(accepted) == 0 {
+			results[i] = core.UpdateCheck{UpdateAvailable: false, HeldBack: heldBack}
+			continue
+		}
+		newRelease := accepted[0]
+
+		if !(ghReleaseFilter{}).isNewer(newRelease, data.Tag, releases) { // The installed release is already the latest
+			results[i] = core.UpdateCheck{UpdateAvailable: false, HeldBack: heldBack}
+			continue
+		}
+
+		newFile, err := selectReleaseAsset(newRelease.Assets, data.Regex)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: err}
+			continue
+		}
+
+		results[i] = core.UpdateCheck{
+			UpdateAvailable: true,
+			UpdateString:    mod.DescribeUpdate(newRelease.TagName, newFile.Name),
+			HeldBack:        heldBack,
+			CachedState:     giteaCachedStateStore{data.Host, newRelease.TagName, newFile},
+		}
+	}
+
+	return results, nil
+}
+
+func (u giteaUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
+	for i, mod := range mods {
+		modState := cachedState[i].(giteaCachedStateStore)
+		file := modState.Asset
+
+		hash, err := giteaDefaultClient.hashAsset(modState.Host, file)
+		if err != nil {
+			return err
+		}
+
+		mod.FileName = file.Name
+		mod.Version = modState.Tag
+		mod.Download = core.ModDownload{
+			URL:        file.BrowserDownloadURL,
+			HashFormat: "sha256",
+			Hash:       hash,
+			Size:       file.Size,
+		}
+		mod.Update["gitea"]["tag"] = modState.Tag
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package sources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func giteaTestMod(tag string, branch string) *core.Mod {
+	return &core.Mod{
+		Name:     "repo",
+		FileName: "old.jar",
+		Update: core.ModUpdate{
+			"gitea": core.ModSourceData{
+				"host":   "codeberg.org",
+				"slug":   "owner/repo",
+				"tag":    tag,
+				"branch": branch,
+				"regex":  ghDefaultAssetRegex,
+			},
+		},
+	}
+}
+
+func TestGiteaUpdater_CheckUpdate(t *testing.T) {
+	var auth []string
+	newTestGiteaServer(t, &auth)
+
+	mods := []*core.Mod{
+		giteaTestMod("v1.0", ""),
+		giteaTestMod("v2.0", ""),
+		giteaTestMod("v1.0", "main"),
+	}
+	results, err := giteaUpdater{}.CheckUpdate(mods, core.Pack{})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.True(t, results[0].UpdateAvailable)
+	assert.Equal(t, "old.jar -> mod-2.0.jar", results[0].UpdateString, "drafts aren't updated to")
+	assert.False(t, results[1].UpdateAvailable)
+	assert.False(t, results[2].UpdateAvailable)
+	assert.NoError(t, results[2].Error)
+
+	t.Run("version constraint holds back newer releases", func(t *testing.T) {
+		constrained := giteaTestMod("v1.0", "")
+		constrained.VersionConstraint = "<2"
+		downgrade := giteaTestMod("v2.0", "")
+		downgrade.VersionConstraint = "<2"
+
+		results, err := giteaUpdater{}.CheckUpdate([]*core.Mod{constrained, downgrade}, core.Pack{})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.False(t, results[0].UpdateAvailable)
+		assert.Equal(t, "v2.0", results[0].HeldBack)
+		assert.False(t, results[1].UpdateAvailable, "older releases aren't offered as updates")
+		assert.NoError(t, results[1].Error)
+	})
+}
+
+func TestGiteaUpdater_DoUpdate(t *testing.T) {
+	var auth []string
+	newTestGiteaServer(t, &auth)
+
+	mod := giteaTestMod("v1.0", "")
+	results, err := giteaUpdater{}.CheckUpdate([]*core.Mod{mod}, core.Pack{})
+	require.NoError(t, err)
+
+	require.NoError(t, giteaUpdater{}.DoUpdate([]*core.Mod{mod}, []interface{}{results[0].CachedState}))
+	assert.Equal(t, "mod-2.0.jar", mod.FileName)
+	assert.Equal(t, "https://codeberg.org/owner/repo/releases/download/v2.0/mod-2.0.jar", mod.Download.URL)
+	assert.NotEmpty(t, mod.Download.Hash)
+	assert.Equal(t, "v2.0", mod.Update["gitea"]["tag"])
+}
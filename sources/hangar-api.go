@@ -0,0 +1,162 @@
+package sources
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+const hangarApiServer = "hangar.papermc.io"
+
+// hangarPageSize is the number of projects or versions fetched per request
+const hangarPageSize = 50
+
+// HangarProject is a subset of a Hangar project
+type HangarProject struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Namespace   struct {
+		Owner string `json:"owner"`
+		Slug  string `json:"slug"`
+	} `json:"namespace"`
+	Stats struct {
+		Downloads uint64 `json:"downloads"`
+	} `json:"stats"`
+}
+
+// HangarVersion is a subset of a version of a Hangar project
+type HangarVersion struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"createdAt"`
+	Channel   struct {
+		Name string `json:"name"`
+	} `json:"channel"`
+	// Downloads, PluginDependencies and PlatformDependencies are keyed by platform (e.g. PAPER)
+	Downloads            map[string]HangarDownload           `json:"downloads"`
+	PluginDependencies   map[string][]HangarPluginDependency `json:"pluginDependencies"`
+	PlatformDependencies map[string][]string                 `json:"platformDependencies"`
+}
+
+// HangarDownload is the file of a version for one platform. Files hosted elsewhere only
+// have an ExternalURL.
+type HangarDownload struct {
+	FileInfo *struct {
+		Name       string `json:"name"`
+		SizeBytes  int64  `json:"sizeBytes"`
+		Sha256Hash string `json:"sha256Hash"`
+	} `json:"fileInfo"`
+	ExternalURL string `json:"externalUrl"`
+	DownloadURL string `json:"downloadUrl"`
+}
+
+// HangarPluginDependency is a dependency of a version on another plugin. Plugins that
+// aren't on Hangar only have an ExternalURL.
+type HangarPluginDependency struct {
+	Name        string `json:"name"`
+	Required    bool   `json:"required"`
+	ExternalURL string `json:"externalUrl"`
+}
+
+type hangarApiClient struct {
+	httpClient *http.Client
+	logger     core.Logger
+}
+
+var hangarDefaultClient = *NewHangarClient(&http.Client{Timeout: core.DefaultHTTPTimeout}, core.PrintLogger{})
+
+// NewHangarClient constructs a Hangar API client using the given httpClient.
+func NewHangarClient(httpClient *http.Client, logger core.Logger) *hangarApiClient {
+	return &hangarApiClient{httpClient, logger}
+}
+
+// GetHangarClient returns the default Hangar API client.
+func GetHangarClient() *hangarApiClient {
+	return &hangarDefaultClient
+}
+
+// SetLogger overrides the client's logger, used to report non-fatal warnings/progress.
+func (c *hangarApiClient) SetLogger(l core.Logger) {
+	c.logger = l
+}
+
+func (c *hangarApiClient) getJson(path string, query url.Values, v any) error {
+	u := "https://" + hangarApiServer + "/api/v1" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", core.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("invalid response status: %v", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+func (c *hangarApiClient) getProject(slug string) (HangarProject, error) {
+	var project HangarProject
+	if err := c.getJson("/projects/"+url.PathEscape(slug), nil, &project); err != nil {
+		return project, fmt.Errorf("failed to fetch project %s: %w", slug, err)
+	}
+	if project.Namespace.Slug == "" {
+		return project, errors.New("invalid json while fetching project: " + slug)
+	}
+	return project, nil
+}
+
+// searchProjects finds projects supporting platform, optionally on mcVersion
+func (c *hangarApiClient) searchProjects(query string, platform string, mcVersion string) ([]HangarProject, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", fmt.Sprint(hangarPageSize))
+	params.Set("platform", platform)
+	if mcVersion != "" {
+		params.Set("version", mcVersion)
+	}
+
+	var result struct {
+		Result []HangarProject `json:"result"`
+	}
+	if err := c.getJson("/projects", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to search for projects: %w", err)
+	}
+	return result.Result, nil
+}
+
+// getVersions fetches the most recent versions of a project for platform, newest first
+func (c *hangarApiClient) getVersions(slug string, platform string) ([]HangarVersion, error) {
+	params := url.Values{}
+	params.Set("limit", fmt.Sprint(hangarPageSize))
+	params.Set("platform", platform)
+
+	var result struct {
+		Result []HangarVersion `json:"result"`
+	}
+	if err := c.getJson("/projects/"+url.PathEscape(slug)+"/versions", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch versions of %s: %w", slug, err)
+	}
+	return result.Result, nil
+}
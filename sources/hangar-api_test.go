@@ -0,0 +1,91 @@
+package sources
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// withHangarClient swaps the package-level Hangar client singleton for the
+// duration of the test, restoring the original afterward.
+func withHangarClient(t *testing.T, httpClient *http.Client) {
+	t.Helper()
+	original := hangarDefaultClient
+	hangarDefaultClient = *NewHangarClient(httpClient, core.NoopLogger{})
+	t.Cleanup(func() { hangarDefaultClient = original })
+}
+
+// hangarTestPack is a Paper server pack for Minecraft 1.21.4
+func hangarTestPack() core.Pack {
+	return core.Pack{
+		Versions: map[string]string{"minecraft": "1.21.4", "paper": "231"},
+		Mods:     map[string]*core.Mod{},
+	}
+}
+
+// newTestHangarServer serves the ViaVersion and ViaBackwards projects. ViaBackwards has a
+// 5.2.0-SNAPSHOT version requiring ViaVersion and an externally hosted plugin, a 5.1.0
+// release and a 4.0.0 release for an older Minecraft version.
+func newTestHangarServer(t *testing.T) {
+	t.Helper()
+	withHangarClient(t, newTestHTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/projects" && r.URL.Query().Has("platform") && r.URL.Query().Get("platform") != "PAPER" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/projects":
+			if r.URL.Query().Get("q") != "via" || r.URL.Query().Get("version") != "1.21.4" {
+				_, _ = w.Write([]byte(`{"result":[]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"result":[{"id":2,"name":"ViaBackwards","description":"Allows older clients","namespace":{"owner":"ViaVersion","slug":"ViaBackwards"},"stats":{"downloads":100}}]}`))
+		case "/api/v1/projects/ViaVersion":
+			_, _ = w.Write([]byte(`{"id":1,"name":"ViaVersion","namespace":{"owner":"ViaVersion","slug":"ViaVersion"}}`))
+		case "/api/v1/projects/ViaBackwards":
+			_, _ = w.Write([]byte(`{"id":2,"name":"ViaBackwards","namespace":{"owner":"ViaVersion","slug":"ViaBackwards"}}`))
+		case "/api/v1/projects/ViaVersion/versions":
+			_, _ = w.Write([]byte(`{"result":[` +
+				`{"id":10,"name":"5.2.1","channel":{"name":"Release"},"downloads":{"PAPER":{"fileInfo":{"name":"ViaVersion-5.2.1.jar","sha256Hash":"aaa"},"downloadUrl":"https://hangarcdn.papermc.io/plugins/ViaVersion/ViaVersion/versions/5.2.1/PAPER/ViaVersion-5.2.1.jar"}},"platformDependencies":{"PAPER":["1.21.3","1.21.4"]}}` +
+				`]}`))
+		case "/api/v1/projects/ViaBackwards/versions":
+			_, _ = w.Write([]byte(`{"result":[` +
+				`{"id":22,"name":"5.2.0-SNAPSHOT","channel":{"name":"Snapshot"},"downloads":{"PAPER":{"fileInfo":{"name":"ViaBackwards-5.2.0-SNAPSHOT.jar","sha256Hash":"ccc"},"downloadUrl":"https://hangarcdn.papermc.io/plugins/ViaVersion/ViaBackwards/versions/5.2.0-SNAPSHOT/PAPER/ViaBackwards-5.2.0-SNAPSHOT.jar"}},` +
+				`"pluginDependencies":{"PAPER":[{"name":"ViaVersion","required":true},{"name":"ProtocolLib","required":true,"externalUrl":"https://github.com/dmulloy2/ProtocolLib"},{"name":"Geyser","required":false}]},"platformDependencies":{"PAPER":["1.21.4"]}},` +
+				`{"id":21,"name":"5.1.0","channel":{"name":"Release"},"downloads":{"PAPER":{"fileInfo":{"name":"ViaBackwards-5.1.0.jar","sha256Hash":"bbb"},"downloadUrl":"https://hangarcdn.papermc.io/plugins/ViaVersion/ViaBackwards/versions/5.1.0/PAPER/ViaBackwards-5.1.0.jar"}},"platformDependencies":{"PAPER":["1.21.3","1.21.4"]}},` +
+				`{"id":20,"name":"4.0.0","channel":{"name":"Release"},"downloads":{"PAPER":{"fileInfo":{"name":"ViaBackwards-4.0.0.jar","sha256Hash":"000"},"downloadUrl":"https://hangarcdn.papermc.io/plugins/ViaVersion/ViaBackwards/versions/4.0.0/PAPER/ViaBackwards-4.0.0.jar"}},"platformDependencies":{"PAPER":["1.20.4"]}}` +
+				`]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})))
+}
+
+func TestHangarApiClient(t *testing.T) {
+	newTestHangarServer(t)
+
+	project, err := hangarDefaultClient.getProject("ViaBackwards")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), project.ID)
+	assert.Equal(t, "ViaVersion", project.Namespace.Owner)
+
+	_, err = hangarDefaultClient.getProject("missing")
+	assert.Error(t, err)
+
+	versions, err := hangarDefaultClient.getVersions("ViaBackwards", "PAPER")
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	assert.Equal(t, "5.2.0-SNAPSHOT", versions[0].Name)
+	assert.Equal(t, "ViaBackwards-5.2.0-SNAPSHOT.jar", versions[0].Downloads["PAPER"].FileInfo.Name)
+	assert.Len(t, versions[0].PluginDependencies["PAPER"], 3)
+
+	projects, err := hangarDefaultClient.searchProjects("via", "PAPER", "1.21.4")
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "ViaBackwards", projects[0].Name)
+	assert.Equal(t, uint64(100), projects[0].Stats.Downloads)
+}
@@ -0,0 +1,222 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+var hangarUrlRegex = regexp.MustCompile(`^https?://hangar\.papermc\.io/[^/]+/([^/?#]+)`)
+
+var hangarSlugRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// HangarParseSlugOrUrl returns the project slug of a Hangar project URL, or input if it is
+// already a slug
+func HangarParseSlugOrUrl(input string) (string, error) {
+	if matches := hangarUrlRegex.FindStringSubmatch(input); matches != nil {
+		return matches[1], nil
+	}
+	if !hangarSlugRegex.MatchString(input) {
+		return "", fmt.Errorf("not a Hangar project URL or slug: %s", input)
+	}
+	return input, nil
+}
+
+// hangarPlatform returns the Hangar platform of the pack's server platform; Purpur runs
+// Paper plugins
+func hangarPlatform(pack core.Pack) (string, error) {
+	if _, ok := pack.GetServerPlatform(); !ok {
+		return "", fmt.Errorf("Hangar plugins require a server platform (%s) in pack.toml", strings.Join(core.ServerPlatforms, " or "))
+	}
+	return "PAPER", nil
+}
+
+// hangarFilterVersions returns the versions with a download for platform that support
+// one of mcVersions, and are in channel if it is non-empty
+func hangarFilterVersions(versions []HangarVersion, platform string, mcVersions []string, channel string) []HangarVersion {
+	var filtered []HangarVersion
+	for _, v := range versions {
+		if _, ok := v.Downloads[platform]; !ok {
+			continue
+		}
+		if channel != "" && !strings.EqualFold(v.Channel.Name, channel) {
+			continue
+		}
+		if !slices.ContainsFunc(v.PlatformDependencies[platform], func(mcVersion string) bool {
+			return slices.Contains(mcVersions, mcVersion)
+		}) {
+			continue
+		}
+		filtered = append(filtered, v)
+	}
+	return filtered
+}
+
+// hangarIsNewer returns true if candidate should replace the installed version named
+// installed. versions is newest first, as returned by hangarListVersions; the installed
+// version is newer if it is listed before candidate (e.g. when a version constraint was
+// added after installing it).
+func hangarIsNewer(candidate HangarVersion, installed string, versions []HangarVersion) bool {
+	for _, v := range versions {
+		if v.Name == candidate.Name {
+			return v.Name != installed
+		}
+		if v.Name == installed {
+			return false
+		}
+	}
+	return true
+}
+
+// hangarListVersions fetches the versions of a project compatible with the pack, newest first
+func hangarListVersions(slug string, platform string, pack core.Pack, channel string) ([]HangarVersion, error) {
+	mcVersions, err := pack.GetSupportedMCVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := hangarDefaultClient.getVersions(slug, platform)
+	if err != nil {
+		return nil, err
+	}
+	return hangarFilterVersions(versions, platform, mcVersions, channel), nil
+}
+
+// HangarNewMod creates a Mod from the latest version of a Hangar project compatible with
+// the pack. channel restricts versions to a release channel (e.g. "Release"), if set.
+func HangarNewMod(slugOrUrl string, pack core.Pack, channel string, modType string) (*core.Mod, error) {
+	slug, err := HangarParseSlugOrUrl(slugOrUrl)
+	if err != nil {
+		return nil, err
+	}
+	platform, err := hangarPlatform(pack)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := hangarDefaultClient.getProject(slug)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := hangarListVersions(project.Namespace.Slug, platform, pack, channel)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no versions of %s are compatible with this pack", project.Name)
+	}
+
+	return hangarInstallVersion(project, versions[0], platform, channel, modType)
+}
+
+// hangarResolveDownload returns the file name, URL and sha256 hash of a version's download.
+// Externally hosted files are downloaded into the cache to hash them.
+func hangarResolveDownload(download HangarDownload) (fileName string, downloadUrl string, hash string, err error) {
+	if download.FileInfo != nil && download.DownloadURL != "" {
+		return download.FileInfo.Name, download.DownloadURL, download.FileInfo.Sha256Hash, nil
+	}
+	if download.ExternalURL == "" {
+		return "", "", "", errors.New("version has no download")
+	}
+
+	fileName, err = urlFileName(download.ExternalURL)
+	if err != nil {
+		return "", "", "", err
+	}
+	hash, err = urlHashFile(download.ExternalURL)
+	if err != nil {
+		return "", "", "", err
+	}
+	return fileName, download.ExternalURL, hash, nil
+}
+
+// hangarInstallVersion creates a Mod from version of project. channel is recorded only if
+// the user restricted versions to it.
+func hangarInstallVersion(project HangarProject, version HangarVersion, platform string, channel string, modType string) (*core.Mod, error) {
+	fileName, downloadUrl, hash, err := hangarResolveDownload(version.Downloads[platform])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get download of %s %s: %w", project.Name, version.Name, err)
+	}
+
+	GetHangarClient().logger.Infof("Installing %s from version %s\n", fileName, version.Name)
+
+	updateMap := make(core.ModUpdate)
+	updateMap["hangar"], err = hangarUpdateData{
+		Slug:     project.Namespace.Slug,
+		Platform: platform,
+		Version:  version.Name,
+		Channel:  channel,
+	}.ToMap()
+	if err != nil {
+		return nil, err
+	}
+
+	mod := core.NewMod(
+		core.SlugifyName(project.Name),
+		project.Name,
+		fileName,
+		core.ServerSide,
+		modType,
+		"",
+		false,
+		false,
+		updateMap,
+		core.ModDownload{
+			URL:        downloadUrl,
+			HashFormat: "sha256",
+			Hash:       hash,
+		},
+		nil,
+	)
+	mod.Version = version.Name
+	return mod, nil
+}
+
+// hangarFindMissingDependencies installs the required Hangar-hosted plugin dependencies of
+// version that aren't in the pack yet. Dependencies hosted elsewhere are reported, as
+// they have to be added manually.
+func hangarFindMissingDependencies(version HangarVersion, platform string, pack core.Pack, modType string) ([]*core.Mod, error) {
+	installed := make(map[string]bool)
+	for _, mod := range pack.Mods {
+		installed[strings.ToLower(mod.Slug)] = true
+		var data hangarUpdateData
+		if mod.DecodeNamedModSourceData("hangar", &data) == nil {
+			installed[strings.ToLower(data.Slug)] = true
+		}
+	}
+
+	var mods []*core.Mod
+	for _, dep := range version.PluginDependencies[platform] {
+		if !dep.Required || installed[strings.ToLower(dep.Name)] || installed[core.SlugifyName(dep.Name)] {
+			continue
+		}
+		if dep.ExternalURL != "" {
+			GetHangarClient().logger.Warnf("Required dependency %s isn't on Hangar, add it from %s\n", dep.Name, dep.ExternalURL)
+			continue
+		}
+
+		project, err := hangarDefaultClient.getProject(dep.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve dependency %s: %w", dep.Name, err)
+		}
+		versions, err := hangarListVersions(project.Namespace.Slug, platform, pack, "")
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("no versions of dependency %s are compatible with this pack", project.Name)
+		}
+
+		mod, err := hangarInstallVersion(project, versions[0], platform, "", modType)
+		if err != nil {
+			return nil, err
+		}
+		mods = append(mods, mod)
+		installed[strings.ToLower(dep.Name)] = true
+	}
+	return mods, nil
+}
@@ -0,0 +1,96 @@
+package sources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func TestHangarParseSlugOrUrl(t *testing.T) {
+	for input, expected := range map[string]string{
+		"ViaVersion": "ViaVersion",
+		"https://hangar.papermc.io/ViaVersion/ViaBackwards":          "ViaBackwards",
+		"https://hangar.papermc.io/ViaVersion/ViaBackwards/versions": "ViaBackwards",
+	} {
+		slug, err := HangarParseSlugOrUrl(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, slug, input)
+	}
+
+	_, err := HangarParseSlugOrUrl("https://example.com/not a slug")
+	assert.Error(t, err)
+}
+
+func TestHangarFilterVersions(t *testing.T) {
+	newTestHangarServer(t)
+	versions, err := hangarDefaultClient.getVersions("ViaBackwards", "PAPER")
+	require.NoError(t, err)
+
+	names := func(versions []HangarVersion) []string {
+		var out []string
+		for _, v := range versions {
+			out = append(out, v.Name)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"5.2.0-SNAPSHOT", "5.1.0"}, names(hangarFilterVersions(versions, "PAPER", []string{"1.21.4"}, "")))
+	assert.Equal(t, []string{"5.1.0"}, names(hangarFilterVersions(versions, "PAPER", []string{"1.21.4"}, "release")))
+	assert.Equal(t, []string{"4.0.0"}, names(hangarFilterVersions(versions, "PAPER", []string{"1.20.4"}, "")))
+	assert.Empty(t, hangarFilterVersions(versions, "VELOCITY", []string{"1.21.4"}, ""))
+}
+
+func TestHangarNewMod(t *testing.T) {
+	newTestHangarServer(t)
+
+	mod, err := HangarNewMod("https://hangar.papermc.io/ViaVersion/ViaBackwards", hangarTestPack(), "Release", "plugins")
+	require.NoError(t, err)
+	assert.Equal(t, "viabackwards", mod.Slug)
+	assert.Equal(t, "ViaBackwards-5.1.0.jar", mod.FileName)
+	assert.Equal(t, core.ServerSide, mod.Side)
+	assert.Equal(t, "plugins", mod.ModType)
+	assert.Equal(t, core.ModDownload{
+		URL:        "https://hangarcdn.papermc.io/plugins/ViaVersion/ViaBackwards/versions/5.1.0/PAPER/ViaBackwards-5.1.0.jar",
+		HashFormat: "sha256",
+		Hash:       "bbb",
+	}, mod.Download)
+	assert.Equal(t, core.ModSourceData{
+		"slug":     "ViaBackwards",
+		"platform": "PAPER",
+		"version":  "5.1.0",
+		"channel":  "Release",
+	}, mod.Update["hangar"])
+
+	t.Run("requires a server platform", func(t *testing.T) {
+		pack := core.Pack{Versions: map[string]string{"minecraft": "1.21.4", "fabric": "0.16.0"}}
+		_, err := HangarNewMod("ViaBackwards", pack, "", "plugins")
+		assert.ErrorContains(t, err, "server platform")
+	})
+
+	t.Run("no compatible versions", func(t *testing.T) {
+		pack := hangarTestPack()
+		pack.Versions["minecraft"] = "1.19.4"
+		_, err := HangarNewMod("ViaVersion", pack, "", "plugins")
+		assert.Error(t, err)
+	})
+}
+
+func TestHangarFindMissingDependencies(t *testing.T) {
+	newTestHangarServer(t)
+	versions, err := hangarDefaultClient.getVersions("ViaBackwards", "PAPER")
+	require.NoError(t, err)
+
+	mods, err := hangarFindMissingDependencies(versions[0], "PAPER", hangarTestPack(), "plugins")
+	require.NoError(t, err)
+	require.Len(t, mods, 1, "optional and externally hosted dependencies are skipped")
+	assert.Equal(t, "ViaVersion-5.2.1.jar", mods[0].FileName)
+
+	pack := hangarTestPack()
+	pack.Mods["viaversion"] = mods[0]
+	mods, err = hangarFindMissingDependencies(versions[0], "PAPER", pack, "plugins")
+	require.NoError(t, err)
+	assert.Empty(t, mods)
+}
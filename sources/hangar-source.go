@@ -0,0 +1,135 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// Options understood by the Hangar Source, in core.SourceRequest.Options
+const (
+	// HangarOptionChannel restricts versions to a release channel, such as "Release"
+	HangarOptionChannel = "channel"
+)
+
+// hangarSourceVersion is the Hangar-specific data stored in core.SourceVersion.Data
+type hangarSourceVersion struct {
+	Project  HangarProject
+	Version  HangarVersion
+	Platform string
+}
+
+type hangarSource struct{}
+
+func (s hangarSource) GetName() string {
+	return "hangar"
+}
+
+func hangarSourceProject(project HangarProject) core.SourceProject {
+	return core.SourceProject{
+		Source:    "hangar",
+		ID:        fmt.Sprint(project.ID),
+		Slug:      project.Namespace.Slug,
+		Name:      project.Name,
+		Summary:   project.Description,
+		Downloads: project.Stats.Downloads,
+		Data:      project,
+	}
+}
+
+func (s hangarSource) Search(query string, req core.SourceRequest) ([]core.SourceProject, error) {
+	platform, err := hangarPlatform(req.Pack)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", core.ErrSourceUnavailable, err)
+	}
+	mcVersion, err := req.Pack.GetMCVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := hangarDefaultClient.searchProjects(query, platform, mcVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]core.SourceProject, 0, len(projects))
+	for _, project := range projects {
+		results = append(results, hangarSourceProject(project))
+	}
+	return results, nil
+}
+
+func (s hangarSource) ResolveProject(identifier string, _ core.SourceRequest) (core.SourceProject, error) {
+	slug, err := HangarParseSlugOrUrl(identifier)
+	if err != nil {
+		return core.SourceProject{}, err
+	}
+
+	project, err := hangarDefaultClient.getProject(slug)
+	if err != nil {
+		return core.SourceProject{}, err
+	}
+	return hangarSourceProject(project), nil
+}
+
+func (s hangarSource) ListVersions(project core.SourceProject, req core.SourceRequest) ([]core.SourceVersion, error) {
+	data, ok := project.Data.(HangarProject)
+	if !ok {
+		return nil, errors.New("not a Hangar project")
+	}
+	platform, err := hangarPlatform(req.Pack)
+	if err != nil {
+		return nil, err
+	}
+
+	hangarVersions, err := hangarListVersions(data.Namespace.Slug, platform, req.Pack, req.Option(HangarOptionChannel))
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]core.SourceVersion, 0, len(hangarVersions))
+	for _, v := range hangarVersions {
+		version := core.SourceVersion{
+			ID:      fmt.Sprint(v.ID),
+			Version: v.Name,
+			Data:    hangarSourceVersion{Project: data, Version: v, Platform: platform},
+		}
+		if info := v.Downloads[platform].FileInfo; info != nil {
+			version.FileName = info.Name
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+func (s hangarSource) SelectVersion(_ core.SourceProject, versions []core.SourceVersion, _ core.SourceRequest) (core.SourceVersion, error) {
+	// Hangar lists versions newest first
+	if len(versions) == 0 {
+		return core.SourceVersion{}, errors.New("no versions found")
+	}
+	return versions[0], nil
+}
+
+func hangarModType(req core.SourceRequest) string {
+	if req.MetaFolder != "" {
+		return req.MetaFolder
+	}
+	return "plugins"
+}
+
+func (s hangarSource) NewMod(_ core.SourceProject, version core.SourceVersion, req core.SourceRequest) (*core.Mod, error) {
+	data, ok := version.Data.(hangarSourceVersion)
+	if !ok {
+		return nil, errors.New("not a Hangar version")
+	}
+	return hangarInstallVersion(data.Project, data.Version, data.Platform, req.Option(HangarOptionChannel), hangarModType(req))
+}
+
+func (s hangarSource) FindMissingDependencies(version core.SourceVersion, req core.SourceRequest) ([]*core.Mod, error) {
+	data, ok := version.Data.(hangarSourceVersion)
+	if !ok {
+		return nil, errors.New("not a Hangar version")
+	}
+	return hangarFindMissingDependencies(data.Version, data.Platform, req.Pack, hangarModType(req))
+}
@@ -0,0 +1,59 @@
+package sources
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func TestHangarSource(t *testing.T) {
+	newTestHangarServer(t)
+
+	src := hangarSource{}
+	req := core.SourceRequest{Pack: hangarTestPack()}
+
+	t.Run("installs the latest compatible version into plugins", func(t *testing.T) {
+		mod, version, err := core.NewModFromSource(src, "ViaBackwards", req)
+		require.NoError(t, err)
+		assert.Equal(t, "5.2.0-SNAPSHOT", version.Version)
+		assert.Equal(t, "ViaBackwards-5.2.0-SNAPSHOT.jar", version.FileName)
+		assert.Equal(t, "plugins", mod.ModType)
+		assert.NotContains(t, mod.Update["hangar"], "channel")
+
+		deps, err := src.FindMissingDependencies(version, req)
+		require.NoError(t, err)
+		require.Len(t, deps, 1)
+		assert.Equal(t, "ViaVersion", deps[0].Name)
+	})
+
+	t.Run("channel option and meta folder", func(t *testing.T) {
+		req := core.SourceRequest{
+			Pack:       hangarTestPack(),
+			MetaFolder: "server-plugins",
+			Options:    map[string]string{HangarOptionChannel: "Release"},
+		}
+		mod, _, err := core.NewModFromSource(src, "https://hangar.papermc.io/ViaVersion/ViaBackwards", req)
+		require.NoError(t, err)
+		assert.Equal(t, "ViaBackwards-5.1.0.jar", mod.FileName)
+		assert.Equal(t, "server-plugins", mod.ModType)
+		assert.Equal(t, "Release", mod.Update["hangar"]["channel"])
+	})
+
+	t.Run("search", func(t *testing.T) {
+		projects, err := src.Search("via", req)
+		require.NoError(t, err)
+		require.Len(t, projects, 1)
+		assert.Equal(t, "ViaBackwards", projects[0].Slug)
+		assert.Equal(t, "Allows older clients", projects[0].Summary)
+	})
+
+	t.Run("unavailable without a server platform", func(t *testing.T) {
+		req := core.SourceRequest{Pack: core.Pack{Versions: map[string]string{"minecraft": "1.21.4"}}}
+		_, err := src.Search("via", req)
+		assert.True(t, errors.Is(err, core.ErrSourceUnavailable))
+	})
+}
@@ -0,0 +1,143 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func init() {
+	RegisterHangar(core.DefaultRegistry)
+}
+
+// RegisterHangar registers the Hangar Updater/Source on reg. Library consumers building an
+// isolated *core.Registry (instead of relying on core.DefaultRegistry) should call this
+// - or sources.RegisterAll - explicitly.
+func RegisterHangar(reg *core.Registry) {
+	reg.AddUpdater(hangarUpdater{})
+	reg.AddSource(hangarSource{})
+}
+
+type hangarUpdateData struct {
+	Slug     string `mapstructure:"slug"`
+	Platform string `mapstructure:"platform"`
+	Version  string `mapstructure:"version"`
+	Channel  string `mapstructure:"channel,omitempty"`
+}
+
+func (u hangarUpdateData) ToMap() (map[string]interface{}, error) {
+	newMap := make(map[string]interface{})
+	err := mapstructure.Decode(u, &newMap)
+	return newMap, err
+}
+
+type hangarUpdater struct{}
+
+func (u hangarUpdater) GetName() string {
+	return "hangar"
+}
+
+func (u hangarUpdater) ParseUpdate(updateUnparsed map[string]interface{}) (interface{}, error) {
+	var updateData hangarUpdateData
+	err := mapstructure.Decode(updateUnparsed, &updateData)
+	return updateData, err
+}
+
+type hangarCachedStateStore struct {
+	Version HangarVersion
+}
+
+func (u hangarUpdater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateCheck, error) {
+	results := make([]core.UpdateCheck, len(mods))
+	mcVersions, _ := pack.GetSupportedMCVersions()
+
+	for i, mod := range mods {
+		var data hangarUpdateData
+		err := mod.DecodeNamedModSourceData("hangar", &data)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: errors.New("failed to parse update metadata")}
+			continue
+		}
+
+		versions, err := hangarListVersions(data.Slug, data.Platform, pack, data.Channel)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: fmt.Errorf("failed to get latest version: %v", err)}
+			continue
+		}
+		if len(versions) == 0 {
+			results[i] = core.UpdateCheck{Error: fmt.Errorf("no versions of %s are compatible with this pack", mod.Name)}
+			continue
+		}
+		heldBack := ""
+		accepted, err := core.FilterAcceptedVersions(mod, mcVersions, versions, func(v HangarVersion) string {
+			return v.Name
+		})
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: err}
+			continue
+		}
+		if len(accepted) == 0 || accepted[0].Name != versions[0].Name {
+			heldBack = versions[0].Name
+		}
+		if len(accepted) == 0 {
+			results[i] = core.UpdateCheck{UpdateAvailable: false, HeldBack: heldBack}
+			continue
+		}
+		newVersion := accepted[0]
+
+		if !hangarIsNewer(newVersion, data.Version, versions) { // The installed version is already the latest
+			results[i] = core.UpdateCheck{UpdateAvailable: false, HeldBack: heldBack}
+			continue
+		}
+
+		download := newVersion.Downloads[data.Platform]
+		newFileName := download.ExternalURL
+		if download.FileInfo != nil {
+			newFileName = download.FileInfo.Name
+		}
+
+		results[i] = core.UpdateCheck{
+			UpdateAvailable: true,
+			UpdateString:    mod.DescribeUpdate(newVersion.Name, newFileName),
+			HeldBack:        heldBack,
+			CachedState:     hangarCachedStateStore{Version: newVersion},
+		}
+	}
+
+	return results, nil
+}
+
+func (u hangarUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
+	for i, mod := range mods {
+		modState := cachedState[i].(hangarCachedStateStore)
+
+		var data hangarUpdateData
+		if err := mod.DecodeNamedModSourceData("hangar", &data); err != nil {
+			return err
+		}
+
+		fileName, downloadUrl, hash, err := hangarResolveDownload(modState.Version.Downloads[data.Platform])
+		if err != nil {
+			return fmt.Errorf("failed to get download of %s %s: %w", mod.Name, modState.Version.Name, err)
+		}
+
+		mod.FileName = fileName
+		mod.Version = modState.Version.Name
+		mod.Download = core.ModDownload{
+			URL:        downloadUrl,
+			HashFormat: "sha256",
+			Hash:       hash,
+		}
+
+		data.Version = modState.Version.Name
+		mod.Update["hangar"], err = data.ToMap()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package sources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func hangarTestMod(version string, channel string) *core.Mod {
+	data := core.ModSourceData{
+		"slug":     "ViaBackwards",
+		"platform": "PAPER",
+		"version":  version,
+	}
+	if channel != "" {
+		data["channel"] = channel
+	}
+	return &core.Mod{
+		Name:     "ViaBackwards",
+		FileName: "old.jar",
+		Update:   core.ModUpdate{"hangar": data},
+	}
+}
+
+func TestHangarUpdater_CheckUpdate(t *testing.T) {
+	newTestHangarServer(t)
+
+	mods := []*core.Mod{
+		hangarTestMod("5.1.0", ""),
+		hangarTestMod("5.2.0-SNAPSHOT", ""),
+		hangarTestMod("5.1.0", "Release"),
+	}
+	results, err := hangarUpdater{}.CheckUpdate(mods, hangarTestPack())
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.True(t, results[0].UpdateAvailable)
+	assert.Equal(t, "old.jar -> ViaBackwards-5.2.0-SNAPSHOT.jar", results[0].UpdateString)
+	assert.False(t, results[1].UpdateAvailable)
+	assert.False(t, results[2].UpdateAvailable)
+	assert.NoError(t, results[2].Error)
+
+	t.Run("version constraint holds back newer versions", func(t *testing.T) {
+		constrained := hangarTestMod("4.0.0", "")
+		constrained.VersionConstraint = "~5.1"
+		downgrade := hangarTestMod("5.2.0-SNAPSHOT", "")
+		downgrade.VersionConstraint = "~5.1"
+
+		results, err := hangarUpdater{}.CheckUpdate([]*core.Mod{constrained, downgrade}, hangarTestPack())
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.True(t, results[0].UpdateAvailable)
+		assert.Equal(t, "old.jar -> ViaBackwards-5.1.0.jar", results[0].UpdateString)
+		assert.Equal(t, "5.2.0-SNAPSHOT", results[0].HeldBack)
+		assert.False(t, results[1].UpdateAvailable, "older versions aren't offered as updates")
+		assert.NoError(t, results[1].Error)
+	})
+}
+
+func TestHangarUpdater_DoUpdate(t *testing.T) {
+	newTestHangarServer(t)
+
+	mod := hangarTestMod("4.0.0", "Release")
+	results, err := hangarUpdater{}.CheckUpdate([]*core.Mod{mod}, hangarTestPack())
+	require.NoError(t, err)
+	require.True(t, results[0].UpdateAvailable)
+
+	require.NoError(t, hangarUpdater{}.DoUpdate([]*core.Mod{mod}, []interface{}{results[0].CachedState}))
+	assert.Equal(t, "ViaBackwards-5.1.0.jar", mod.FileName)
+	assert.Equal(t, "bbb", mod.Download.Hash)
+	assert.Equal(t, "5.1.0", mod.Update["hangar"]["version"])
+	assert.Equal(t, "Release", mod.Update["hangar"]["channel"])
+}
@@ -86,7 +86,7 @@ func ModrinthSearchForProjects(query string, versions []string) ([]*modrinthApi.
 
 // "Loaders" that are supported regardless of the configured mod loaders
 var defaultMRLoaders = []string{
-	// TODO: check if Canvas/Iris/Optifine are installed? suggest installing them?
+	// Shader loader mods are checked for by core.CheckPack (see core.ShaderLoaders)
 	"canvas",
 	"iris",
 	"optifine",
@@ -100,10 +100,15 @@ var withDatapackPathMRLoaders = []string{
 	"optifine",
 	"vanilla",   // Core shaders
 	"minecraft", // Resource packs
-	// TODO: check if a datapack loader is installed; suggest installing one?
-	"datapack", // Datapacks (requires a datapack loader)
+	"datapack",  // Datapacks (requires a datapack loader, see core.CheckPack)
 }
 
+// mrDatapackLoaders restricts version lookups to datapack versions, for updating installed datapacks
+var mrDatapackLoaders = []string{"datapack"}
+
+// errNoDatapackFolder is returned when installing a datapack into a pack without a datapack folder
+var errNoDatapackFolder = errors.New("datapacks are installed into the pack's datapack folder, which isn't set; set one with 'packwiz settings datapack-folder <folder>'")
+
 var mrLoaderFolders = map[string]string{
 	"quilt":      "mods",
 	"fabric":     "mods",
@@ -171,11 +176,20 @@ var mrLoaderCompatGroups = map[string][]string{
 	"bungeecord": {"waterfall"},
 }
 
-func mrGetProjectTypeFolder(projectType string, fileLoaders []string, packLoaders []string) (string, error) {
+// mrGetProjectTypeFolder returns the folder a file of the given project type and loaders is installed
+// into. Datapacks are installed into datapackFolder, and fail if it is empty.
+func mrGetProjectTypeFolder(projectType string, fileLoaders []string, packLoaders []string, datapackFolder string) (string, error) {
 	if projectType == "modpack" {
 		return "", errors.New("this command should not be used to add Modrinth modpacks, and importing of Modrinth modpacks is not yet supported")
 	} else if projectType == "resourcepack" {
 		return "resourcepacks", nil
+	} else if projectType == "plugin" {
+		return "plugins", nil
+	} else if projectType == "datapack" {
+		if datapackFolder == "" {
+			return "", errNoDatapackFolder
+		}
+		return datapackFolder, nil
 	} else if projectType == "shader" {
 		bestLoaderIdx := math.MaxInt
 		for _, v := range fileLoaders {
@@ -189,7 +203,8 @@ func mrGetProjectTypeFolder(projectType string, fileLoaders []string, packLoader
 		}
 		return "shaderpacks", nil
 	} else if projectType == "mod" {
-		// Look up pack loaders in the list of loaders (note this is currently filtered to quilt/fabric/neoforge/forge)
+		// Look up pack loaders in the list of loaders (mod loaders, and the plugin APIs of the
+		// pack's server platform - plugins are also listed as mods)
 		bestLoaderIdx := math.MaxInt
 		for _, v := range fileLoaders {
 			if slices.Contains(packLoaders, v) {
@@ -205,7 +220,10 @@ func mrGetProjectTypeFolder(projectType string, fileLoaders []string, packLoader
 
 		// Datapack loader is "datapack"
 		if slices.Contains(fileLoaders, "datapack") {
-			return "", errors.New("datapacks are not supported yet")
+			if datapackFolder == "" {
+				return "", errNoDatapackFolder
+			}
+			return datapackFolder, nil
 		}
 		// Default to "mods" for mod type
 		return "mods", nil
@@ -409,15 +427,26 @@ func mrFindLatestVersion(versions []*modrinthApi.Version, gameVersions []string,
 }
 
 func ModrinthGetLatestVersion(projectID string, name string, pack core.Pack, optionalDatapackFolder string) (*modrinthApi.Version, error) {
-	gameVersions, err := pack.GetSupportedMCVersions()
+	versions, err := ModrinthListVersions(projectID, pack, optionalDatapackFolder)
 	if err != nil {
 		return nil, err
 	}
-	var loaders []string
+	return ModrinthSelectLatestVersion(versions, name, pack)
+}
+
+// ModrinthListVersions fetches the versions of a project that are compatible with the pack's
+// Minecraft versions and loaders.
+func ModrinthListVersions(projectID string, pack core.Pack, optionalDatapackFolder string) ([]*modrinthApi.Version, error) {
 	if optionalDatapackFolder != "" {
-		loaders = append(pack.GetCompatibleLoaders(), withDatapackPathMRLoaders...)
-	} else {
-		loaders = append(pack.GetCompatibleLoaders(), defaultMRLoaders...)
+		return mrListVersionsForLoaders(projectID, pack, append(pack.GetCompatibleLoaders(), withDatapackPathMRLoaders...))
+	}
+	return mrListVersionsForLoaders(projectID, pack, append(pack.GetCompatibleLoaders(), defaultMRLoaders...))
+}
+
+func mrListVersionsForLoaders(projectID string, pack core.Pack, loaders []string) ([]*modrinthApi.Version, error) {
+	gameVersions, err := pack.GetSupportedMCVersions()
+	if err != nil {
+		return nil, err
 	}
 
 	result, err := GetModrinthClient().Versions.ListVersions(projectID, modrinthApi.ListVersionsOptions{
@@ -431,11 +460,24 @@ func ModrinthGetLatestVersion(projectID string, name string, pack core.Pack, opt
 		// TODO: retry with datapack specified, to determine what the issue is? or just request all and filter afterwards
 		return nil, errors.New("no valid versions found")
 	}
+	return result, nil
+}
+
+// ModrinthSelectLatestVersion picks the newest of versions (as returned by ModrinthListVersions)
+// for the pack's Minecraft versions, warning if version numbers and release dates disagree.
+func ModrinthSelectLatestVersion(versions []*modrinthApi.Version, name string, pack core.Pack) (*modrinthApi.Version, error) {
+	if len(versions) == 0 {
+		return nil, errors.New("no valid versions found")
+	}
+	gameVersions, err := pack.GetSupportedMCVersions()
+	if err != nil {
+		return nil, err
+	}
 
 	// TODO: option to always compare using flexver?
 	// TODO: ask user which one to use?
-	flexverLatest := mrFindLatestVersion(result, gameVersions, true)
-	releaseDateLatest := mrFindLatestVersion(result, gameVersions, false)
+	flexverLatest := mrFindLatestVersion(versions, gameVersions, true)
+	releaseDateLatest := mrFindLatestVersion(versions, gameVersions, false)
 	if flexverLatest != releaseDateLatest && releaseDateLatest.VersionNumber != nil && flexverLatest.VersionNumber != nil {
 		mrLogger.Warnf("Warning: Modrinth versions for %s inconsistent between latest version number and newest release date (%s vs %s)\n", name, *flexverLatest.VersionNumber, *releaseDateLatest.VersionNumber)
 	}
@@ -466,6 +508,25 @@ func mrShouldDownloadOnSide(side string) bool {
 	return side == "required" || side == "optional"
 }
 
+// mrFileDownload returns the download of a Modrinth file, recording all of its hashes and its
+// size so exports don't need to download it. It returns false if the file has no hash.
+func mrFileDownload(file *modrinthApi.File) (core.ModDownload, bool) {
+	algorithm, hash := mrGetBestHash(file)
+	if algorithm == "" {
+		return core.ModDownload{}, false
+	}
+	download := core.ModDownload{
+		URL:        *file.URL,
+		HashFormat: algorithm,
+		Hash:       hash,
+	}
+	download.SetHashes(file.Hashes)
+	if file.Size != nil {
+		download.Size = uint64(*file.Size)
+	}
+	return download, true
+}
+
 func mrGetBestHash(v *modrinthApi.File) (string, string) {
 	// Try preferred hashes first; SHA1 is required for Modrinth pack exporting, but
 	// so is SHA512, so we can't win with the current one-hash format
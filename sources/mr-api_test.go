@@ -152,47 +152,86 @@ func TestMrFindLatestVersion(t *testing.T) {
 
 func TestMrGetProjectTypeFolder(t *testing.T) {
 	t.Run("modpack errors", func(t *testing.T) {
-		_, err := mrGetProjectTypeFolder("modpack", nil, nil)
+		_, err := mrGetProjectTypeFolder("modpack", nil, nil, "")
 		assert.Error(t, err)
 	})
 
 	t.Run("resourcepack", func(t *testing.T) {
-		folder, err := mrGetProjectTypeFolder("resourcepack", nil, nil)
+		folder, err := mrGetProjectTypeFolder("resourcepack", nil, nil, "")
 		require.NoError(t, err)
 		assert.Equal(t, "resourcepacks", folder)
 	})
 
 	t.Run("shader picks best loader folder from fileLoaders alone", func(t *testing.T) {
-		folder, err := mrGetProjectTypeFolder("shader", []string{"optifine", "iris"}, nil)
+		folder, err := mrGetProjectTypeFolder("shader", []string{"optifine", "iris"}, nil, "")
 		require.NoError(t, err)
 		assert.Equal(t, "shaderpacks", folder)
 	})
 
 	t.Run("shader falls back to shaderpacks when no known loader", func(t *testing.T) {
-		folder, err := mrGetProjectTypeFolder("shader", []string{"unknown"}, nil)
+		folder, err := mrGetProjectTypeFolder("shader", []string{"unknown"}, nil, "")
 		require.NoError(t, err)
 		assert.Equal(t, "shaderpacks", folder)
 	})
 
 	t.Run("mod requires loader present in both fileLoaders and packLoaders", func(t *testing.T) {
-		folder, err := mrGetProjectTypeFolder("mod", []string{"quilt", "fabric"}, []string{"fabric"})
+		folder, err := mrGetProjectTypeFolder("mod", []string{"quilt", "fabric"}, []string{"fabric"}, "")
 		require.NoError(t, err)
 		assert.Equal(t, "mods", folder)
 	})
 
 	t.Run("mod with no shared loader falls back to mods", func(t *testing.T) {
-		folder, err := mrGetProjectTypeFolder("mod", []string{"forge"}, []string{"fabric"})
+		folder, err := mrGetProjectTypeFolder("mod", []string{"forge"}, []string{"fabric"}, "")
 		require.NoError(t, err)
 		assert.Equal(t, "mods", folder)
 	})
 
-	t.Run("mod datapack loader errors", func(t *testing.T) {
-		_, err := mrGetProjectTypeFolder("mod", []string{"datapack"}, []string{"fabric"})
-		assert.Error(t, err)
+	t.Run("plugin", func(t *testing.T) {
+		folder, err := mrGetProjectTypeFolder("plugin", []string{"paper"}, nil, "")
+		require.NoError(t, err)
+		assert.Equal(t, "plugins", folder)
+	})
+
+	t.Run("mod listing plugin loaders goes in plugins on a server platform", func(t *testing.T) {
+		folder, err := mrGetProjectTypeFolder("mod", []string{"bukkit", "spigot"}, []string{"paper", "spigot", "bukkit"}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "plugins", folder)
+	})
+
+	t.Run("mod prefers mod loaders to plugin loaders", func(t *testing.T) {
+		folder, err := mrGetProjectTypeFolder("mod", []string{"paper", "fabric"}, []string{"fabric", "paper", "spigot", "bukkit"}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "mods", folder)
+	})
+
+	t.Run("mod datapack loader without a datapack folder errors", func(t *testing.T) {
+		_, err := mrGetProjectTypeFolder("mod", []string{"datapack"}, []string{"fabric"}, "")
+		assert.ErrorIs(t, err, errNoDatapackFolder)
+	})
+
+	t.Run("mod datapack loader goes in the datapack folder", func(t *testing.T) {
+		folder, err := mrGetProjectTypeFolder("mod", []string{"datapack"}, []string{"fabric"}, "config/paxi/datapacks")
+		require.NoError(t, err)
+		assert.Equal(t, "config/paxi/datapacks", folder)
+	})
+
+	t.Run("mod prefers mod loaders to the datapack loader", func(t *testing.T) {
+		folder, err := mrGetProjectTypeFolder("mod", []string{"datapack", "fabric"}, []string{"fabric"}, "config/paxi/datapacks")
+		require.NoError(t, err)
+		assert.Equal(t, "mods", folder)
+	})
+
+	t.Run("datapack project type", func(t *testing.T) {
+		folder, err := mrGetProjectTypeFolder("datapack", []string{"datapack"}, nil, "world/datapacks")
+		require.NoError(t, err)
+		assert.Equal(t, "world/datapacks", folder)
+
+		_, err = mrGetProjectTypeFolder("datapack", []string{"datapack"}, nil, "")
+		assert.ErrorIs(t, err, errNoDatapackFolder)
 	})
 
 	t.Run("unknown project type errors", func(t *testing.T) {
-		_, err := mrGetProjectTypeFolder("plugin-but-not-really", nil, nil)
+		_, err := mrGetProjectTypeFolder("plugin-but-not-really", nil, nil, "")
 		assert.Error(t, err)
 	})
 }
@@ -252,6 +291,25 @@ func TestMrGetBestHash(t *testing.T) {
 	})
 }
 
+func TestMrFileDownload(t *testing.T) {
+	size := uint32(2048)
+	file := &modrinthApi.File{
+		URL:    strPtr("https://cdn.modrinth.com/data/AANobbMI/versions/1/sodium.jar"),
+		Hashes: map[string]string{"sha512": "h512", "sha1": "h1"},
+		Size:   &size,
+	}
+	download, ok := mrFileDownload(file)
+	require.True(t, ok)
+	assert.Equal(t, "https://cdn.modrinth.com/data/AANobbMI/versions/1/sodium.jar", download.URL)
+	assert.Equal(t, "sha512", download.HashFormat)
+	assert.Equal(t, "h512", download.Hash)
+	assert.Equal(t, map[string]string{"sha1": "h1"}, download.Hashes)
+	assert.Equal(t, uint64(2048), download.Size)
+
+	_, ok = mrFileDownload(&modrinthApi.File{URL: file.URL})
+	assert.False(t, ok, "files without hashes can't be downloaded")
+}
+
 func TestMrGetInstalledProjectIDs(t *testing.T) {
 	newModrinthMod := func(projectID string) *core.Mod {
 		update := make(core.ModUpdate)
@@ -3,6 +3,7 @@ package sources
 import (
 	"context"
 	"fmt"
+	"math"
 	"net/url"
 	"sort"
 	"strconv"
@@ -43,11 +44,43 @@ func CanBeIncludedDirectly(mod *core.Mod, restrictDomains bool) bool {
 	return false
 }
 
+// SplitModrinthExport splits the mods of a Modrinth export into those whose
+// manifest entries can be built from their metadata alone, and those that
+// must be downloaded: mods that have to be embedded in the exported archive,
+// and mods whose metadata lacks the sha1 and sha512 hashes or the file size
+// the manifest requires.
+func SplitModrinthExport(mods []*core.Mod, restrictDomains bool) (fromMetadata []*core.Mod, toDownload []*core.Mod) {
+	for _, mod := range mods {
+		if CanBeIncludedDirectly(mod, restrictDomains) && hasManifestMetadata(mod) {
+			fromMetadata = append(fromMetadata, mod)
+		} else {
+			toDownload = append(toDownload, mod)
+		}
+	}
+	return fromMetadata, toDownload
+}
+
+// hasManifestMetadata reports whether the mod's metadata has every hash and
+// the file size a Modrinth manifest entry needs
+func hasManifestMetadata(mod *core.Mod) bool {
+	if mod.Download.Size == 0 || mod.Download.Size > math.MaxUint32 {
+		return false
+	}
+	for _, format := range []string{"sha1", "sha512"} {
+		if _, ok := mod.Download.GetHash(format); !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // BuildModrinthManifest builds the Modrinth pack manifest (the contents of
 // modrinth.index.json) for the given pack, driving the provided download
 // session to completion.
 //
-// For each completed download that CanBeIncludedDirectly, a manifest file
+// Each of fromMetadata (see SplitModrinthExport) gets a manifest file entry
+// built from its metadata, without being downloaded. For each completed
+// download that CanBeIncludedDirectly, a manifest file
 // entry is added (with client/server env derived from the mod's Side and
 // Option). Downloads that cannot be included directly are instead handed to
 // embed, along with the override subdirectory ("overrides", "client-overrides"
@@ -56,12 +89,21 @@ func CanBeIncludedDirectly(mod *core.Mod, restrictDomains bool) bool {
 func BuildModrinthManifest(
 	ctx context.Context,
 	pack core.Pack,
+	fromMetadata []*core.Mod,
 	session fileio.DownloadSession,
 	restrictDomains bool,
 	embed func(dl fileio.CompletedDownload, dir string),
 ) (ModrinthPack, error) {
 	manifestFiles := make([]ModrinthPackFile, 0)
 
+	for _, mod := range fromMetadata {
+		sha1, _ := mod.Download.GetHash("sha1")
+		sha512, _ := mod.Download.GetHash("sha512")
+		manifestFiles = append(manifestFiles, buildModrinthManifestFile(mod, sha1, sha512, mod.Download.Size))
+
+		fmt.Printf("%s (%s) added to manifest\n", mod.Name, mod.FileName)
+	}
+
 	for dl := range session.StartDownloads(ctx) {
 		if CanBeIncludedDirectly(dl.Mod, restrictDomains) {
 			if dl.Error != nil {
@@ -72,11 +114,11 @@ func BuildModrinthManifest(
 				fmt.Printf("Warning for %s (%s): %v\n", dl.Mod.Name, dl.Mod.FileName, warning)
 			}
 
-			file, err := buildModrinthManifestFile(dl)
+			fileSize, err := strconv.ParseUint(dl.Hashes["length-bytes"], 10, 64)
 			if err != nil {
-				return ModrinthPack{}, err
+				return ModrinthPack{}, fmt.Errorf("failed to parse file size for %s (%s): %w", dl.Mod.Name, dl.Mod.FileName, err)
 			}
-			manifestFiles = append(manifestFiles, file)
+			manifestFiles = append(manifestFiles, buildModrinthManifestFile(dl.Mod, dl.Hashes["sha1"], dl.Hashes["sha512"], fileSize))
 
 			fmt.Printf("%s (%s) added to manifest\n", dl.Mod.Name, dl.Mod.FileName)
 		} else {
@@ -123,42 +165,38 @@ func BuildModrinthManifest(
 	}, nil
 }
 
-func buildModrinthManifestFile(dl fileio.CompletedDownload) (ModrinthPackFile, error) {
-	path := dl.Mod.GetRelDownloadPath()
+func buildModrinthManifestFile(mod *core.Mod, sha1 string, sha512 string, fileSize uint64) ModrinthPackFile {
+	path := mod.GetRelDownloadPath()
 
 	hashes := map[string]string{
-		"sha1":   dl.Hashes["sha1"],
-		"sha512": dl.Hashes["sha512"],
-	}
-	fileSize, err := strconv.ParseUint(dl.Hashes["length-bytes"], 10, 64)
-	if err != nil {
-		return ModrinthPackFile{}, fmt.Errorf("failed to parse file size for %s (%s): %w", dl.Mod.Name, dl.Mod.FileName, err)
+		"sha1":   sha1,
+		"sha512": sha512,
 	}
 
 	// Create env options based on configured optional/side
 	var envInstalled string
-	if dl.Mod.Option != nil && dl.Mod.Option.Optional {
+	if mod.Option != nil && mod.Option.Optional {
 		envInstalled = "optional"
 	} else {
 		envInstalled = "required"
 	}
 	var clientEnv, serverEnv string
-	if dl.Mod.Side == core.UniversalSide || dl.Mod.Side == core.EmptySide {
+	if mod.Side == core.UniversalSide || mod.Side == core.EmptySide {
 		clientEnv = envInstalled
 		serverEnv = envInstalled
-	} else if dl.Mod.Side == core.ClientSide {
+	} else if mod.Side == core.ClientSide {
 		clientEnv = envInstalled
 		serverEnv = "unsupported"
-	} else if dl.Mod.Side == core.ServerSide {
+	} else if mod.Side == core.ServerSide {
 		clientEnv = "unsupported"
 		serverEnv = envInstalled
 	}
 
 	// Modrinth URLs must be RFC3986
-	u, err := core.ReEncodeURL(dl.Mod.Download.URL)
+	u, err := core.ReEncodeURL(mod.Download.URL)
 	if err != nil {
 		fmt.Printf("Error re-encoding download URL: %s\n", err.Error())
-		u = dl.Mod.Download.URL
+		u = mod.Download.URL
 	}
 
 	return ModrinthPackFile{
@@ -170,5 +208,5 @@ func buildModrinthManifestFile(dl fileio.CompletedDownload) (ModrinthPackFile, e
 		}{Client: clientEnv, Server: serverEnv},
 		Downloads: []string{u},
 		FileSize:  uint32(fileSize),
-	}, nil
+	}
 }
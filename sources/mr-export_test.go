@@ -49,3 +49,41 @@ func TestCanBeIncludedDirectly(t *testing.T) {
 		assert.False(t, CanBeIncludedDirectly(mod, true))
 	})
 }
+
+func TestSplitModrinthExport(t *testing.T) {
+	withMetadata := &core.Mod{Name: "Sodium", Download: core.ModDownload{
+		URL: "https://cdn.modrinth.com/sodium.jar", HashFormat: "sha512", Hash: "h512",
+		Hashes: map[string]string{"sha1": "h1"}, Size: 2048,
+	}}
+	noSize := &core.Mod{Name: "Lithium", Download: core.ModDownload{
+		URL: "https://cdn.modrinth.com/lithium.jar", HashFormat: "sha512", Hash: "h512",
+		Hashes: map[string]string{"sha1": "h1"},
+	}}
+	noSha1 := &core.Mod{Name: "Balm", Download: core.ModDownload{
+		URL: "https://github.com/balm.jar", HashFormat: "sha256", Hash: "h256", Size: 2048,
+	}}
+	embedded := &core.Mod{Name: "JEI", Download: core.ModDownload{
+		Mode: core.ModeCF, HashFormat: "sha1", Hash: "h1",
+		Hashes: map[string]string{"sha512": "h512"}, Size: 2048,
+	}}
+
+	fromMetadata, toDownload := SplitModrinthExport([]*core.Mod{withMetadata, noSize, noSha1, embedded}, true)
+	assert.Equal(t, []*core.Mod{withMetadata}, fromMetadata)
+	assert.Equal(t, []*core.Mod{noSize, noSha1, embedded}, toDownload)
+}
+
+func TestBuildModrinthManifestFile(t *testing.T) {
+	mod := &core.Mod{
+		Name:     "Sodium",
+		FileName: "sodium.jar",
+		Side:     core.ClientSide,
+		Download: core.ModDownload{URL: "https://cdn.modrinth.com/sodium 1.jar"},
+	}
+
+	file := buildModrinthManifestFile(mod, "h1", "h512", 2048)
+	assert.Equal(t, map[string]string{"sha1": "h1", "sha512": "h512"}, file.Hashes)
+	assert.Equal(t, uint32(2048), file.FileSize)
+	assert.Equal(t, []string{"https://cdn.modrinth.com/sodium%201.jar"}, file.Downloads)
+	assert.Equal(t, "required", file.Env.Client)
+	assert.Equal(t, "unsupported", file.Env.Server)
+}
@@ -15,6 +15,7 @@ func ModrinthNewMod(
 	version *modrinthApi.Version,
 	modType string,
 	compatibleLoaders []string,
+	datapackFolder string,
 	optionalFilenameMatch string,
 ) (*core.Mod, error) {
 
@@ -22,7 +23,7 @@ func ModrinthNewMod(
 
 	primaryFile := GetModrinthVersionPrimaryFile(version, optionalFilenameMatch)
 
-	mod, err := createModrinthMod(project, version, primaryFile, compatibleLoaders, modType)
+	mod, err := createModrinthMod(project, version, primaryFile, compatibleLoaders, datapackFolder, modType)
 	if err != nil {
 		return nil, err
 	}
@@ -162,7 +163,7 @@ func ModrinthFindMissingDependencies(
 		}
 	}
 
-	mods, err := createModrinthDependencies(pack.GetCompatibleLoaders(), depMetadata)
+	mods, err := createModrinthDependencies(pack.GetCompatibleLoaders(), optionalDatapackFolder, depMetadata)
 	if err != nil {
 		return nil, err
 	}
@@ -190,6 +191,7 @@ func createModrinthMod(
 	version *modrinthApi.Version,
 	file *modrinthApi.File,
 	compatibleLoaders []string,
+	datapackFolder string,
 	customMetaFolder string,
 ) (*core.Mod, error) {
 	updateMap := make(core.ModUpdate)
@@ -197,7 +199,7 @@ func createModrinthMod(
 	var err error
 	metaFolder := customMetaFolder
 	if metaFolder == "" {
-		metaFolder, err = mrGetProjectTypeFolder(*project.ProjectType, version.Loaders, compatibleLoaders)
+		metaFolder, err = mrGetProjectTypeFolder(*project.ProjectType, version.Loaders, compatibleLoaders, datapackFolder)
 		if err != nil {
 			return nil, err
 		}
@@ -218,17 +220,11 @@ func createModrinthMod(
 		side = core.UniversalSide
 	}
 
-	algorithm, hash := mrGetBestHash(file)
-	if algorithm == "" {
+	download, ok := mrFileDownload(file)
+	if !ok {
 		return nil, errors.New("file doesn't have a hash")
 	}
 
-	download := core.ModDownload{
-		URL:        *file.URL,
-		HashFormat: algorithm,
-		Hash:       hash,
-	}
-
 	mod := core.NewMod(
 		getModrinthProjectSlug(project),
 		*project.Title,
@@ -242,6 +238,9 @@ func createModrinthMod(
 		download,
 		nil,
 	)
+	if version.VersionNumber != nil {
+		mod.Version = *version.VersionNumber
+	}
 
 	return mod, nil
 }
@@ -255,12 +254,13 @@ func getModrinthProjectSlug(project *modrinthApi.Project) string {
 
 func createModrinthDependencies(
 	compatibleLoaders []string,
+	datapackFolder string,
 	depMetadata []ModrinthDepMetadataStore,
 ) ([]*core.Mod, error) {
 	mods := make([]*core.Mod, 0)
 
 	for _, v := range depMetadata {
-		mod, err := createModrinthMod(v.ProjectInfo, v.VersionInfo, v.FileInfo, compatibleLoaders, "")
+		mod, err := createModrinthMod(v.ProjectInfo, v.VersionInfo, v.FileInfo, compatibleLoaders, datapackFolder, "")
 		if err != nil {
 			return nil, err
 		}
@@ -72,8 +72,9 @@ func TestModrinthNewMod(t *testing.T) {
 
 	newVersion := func(hashes map[string]string) *modrinthApi.Version {
 		return &modrinthApi.Version{
-			ID:      strPtr("version123"),
-			Loaders: []string{"fabric"},
+			ID:            strPtr("version123"),
+			VersionNumber: strPtr("15.2.0"),
+			Loaders:       []string{"fabric"},
 			Files: []*modrinthApi.File{
 				{
 					Filename: strPtr("jei-1.20.1.jar"),
@@ -89,12 +90,13 @@ func TestModrinthNewMod(t *testing.T) {
 		project := newProject("required", "required")
 		version := newVersion(map[string]string{"sha512": "abc512"})
 
-		mod, err := ModrinthNewMod(project, version, "", []string{"fabric"}, "")
+		mod, err := ModrinthNewMod(project, version, "", []string{"fabric"}, "", "")
 		require.NoError(t, err)
 
 		assert.Equal(t, "jei", mod.Slug)
 		assert.Equal(t, "Just Enough Items", mod.Name)
 		assert.Equal(t, "jei-1.20.1.jar", mod.FileName)
+		assert.Equal(t, "15.2.0", mod.Version)
 		assert.Equal(t, core.UniversalSide, mod.Side)
 		assert.Equal(t, "mods", mod.ModType)
 		assert.Equal(t, "sha512", mod.Download.HashFormat)
@@ -105,7 +107,7 @@ func TestModrinthNewMod(t *testing.T) {
 		project := newProject("unsupported", "unsupported")
 		version := newVersion(map[string]string{"sha512": "abc512"})
 
-		mod, err := ModrinthNewMod(project, version, "", []string{"fabric"}, "")
+		mod, err := ModrinthNewMod(project, version, "", []string{"fabric"}, "", "")
 		require.NoError(t, err)
 		assert.Equal(t, core.UniversalSide, mod.Side)
 	})
@@ -114,7 +116,7 @@ func TestModrinthNewMod(t *testing.T) {
 		project := newProject("required", "required")
 		version := newVersion(map[string]string{})
 
-		_, err := ModrinthNewMod(project, version, "", []string{"fabric"}, "")
+		_, err := ModrinthNewMod(project, version, "", []string{"fabric"}, "", "")
 		assert.Error(t, err)
 	})
 }
@@ -0,0 +1,244 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+
+	modrinthApi "codeberg.org/jmansfield/go-modrinth/modrinth"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// Options understood by the Modrinth Source, in core.SourceRequest.Options
+const (
+	// MrOptionVersionID installs a specific Modrinth version ID
+	MrOptionVersionID = "version-id"
+	// MrOptionFilename selects a file other than the primary file of a version
+	MrOptionFilename = "filename"
+	// MrOptionDatapackFolder allows datapack versions, installed to the given folder. Defaults
+	// to the pack's datapack folder (see core.DatapackFolderOption).
+	MrOptionDatapackFolder = "datapack-folder"
+)
+
+// mrDatapackFolder returns the folder datapacks are installed into for req
+func mrDatapackFolder(req core.SourceRequest) string {
+	if folder := req.Option(MrOptionDatapackFolder); folder != "" {
+		return folder
+	}
+	return req.Pack.GetDatapackFolder()
+}
+
+// mrSourceProject is the Modrinth-specific data stored in core.SourceProject.Data
+type mrSourceProject struct {
+	Project *modrinthApi.Project
+	// Version and VersionID are set when the identifier referenced a specific version
+	Version   string
+	VersionID string
+	Filename  string
+}
+
+// mrSourceVersion is the Modrinth-specific data stored in core.SourceVersion.Data
+type mrSourceVersion struct {
+	Project  *modrinthApi.Project
+	Version  *modrinthApi.Version
+	Filename string
+}
+
+type mrSource struct{}
+
+func (s mrSource) GetName() string {
+	return "modrinth"
+}
+
+func (s mrSource) Search(query string, req core.SourceRequest) ([]core.SourceProject, error) {
+	mcVersions, err := req.Pack.GetSupportedMCVersions()
+	if err != nil {
+		return nil, err
+	}
+	return searchModrinth(query, mcVersions)
+}
+
+func (s mrSource) ResolveProject(identifier string, req core.SourceRequest) (core.SourceProject, error) {
+	data := mrSourceProject{
+		VersionID: req.Option(MrOptionVersionID),
+		Filename:  req.Option(MrOptionFilename),
+	}
+
+	var slug, version, versionID, filename string
+	if _, err := ParseModrinthSlugOrUrl(identifier, &slug, &version, &versionID, &filename); err != nil {
+		return core.SourceProject{}, err
+	}
+	if slug == "" && versionID == "" {
+		// Not a URL or slug; could still be a project ID
+		slug = identifier
+	}
+	if version != "" {
+		data.Version = version
+	}
+	if versionID != "" {
+		data.VersionID = versionID
+	}
+	if filename != "" {
+		data.Filename = filename
+	}
+
+	var err error
+	if data.VersionID != "" && slug == "" {
+		data.Project, _, err = ModrinthProjectFromVersionID(data.VersionID)
+	} else {
+		// Modrinth transparently handles slugs/project IDs in their API; we don't have to detect which one it is.
+		data.Project, err = GetModrinthClient().Projects.Get(slug)
+	}
+	if err != nil {
+		var notFound *modrinthApi.NotFoundErrorResponse
+		if errors.As(err, &notFound) {
+			return core.SourceProject{}, fmt.Errorf("%w: %s", core.ErrProjectNotFound, identifier)
+		}
+		return core.SourceProject{}, fmt.Errorf("failed to get project %s: %w", identifier, err)
+	}
+
+	return mrToSourceProject(data), nil
+}
+
+func mrToSourceProject(data mrSourceProject) core.SourceProject {
+	project := core.SourceProject{
+		Source: "modrinth",
+		Data:   data,
+	}
+	if data.Project.ID != nil {
+		project.ID = *data.Project.ID
+	}
+	if data.Project.Slug != nil {
+		project.Slug = *data.Project.Slug
+	}
+	if data.Project.Title != nil {
+		project.Name = *data.Project.Title
+	}
+	if data.Project.Description != nil {
+		project.Summary = *data.Project.Description
+	}
+	if data.Project.Downloads != nil {
+		project.Downloads = uint64(*data.Project.Downloads)
+	}
+	return project
+}
+
+// mrSourceProjectData returns the Modrinth data for project, fetching the full project if
+// it came from a search result
+func mrSourceProjectData(project core.SourceProject, req core.SourceRequest) (mrSourceProject, error) {
+	if data, ok := project.Data.(mrSourceProject); ok && data.Project != nil {
+		return data, nil
+	}
+	if project.Source != "modrinth" || project.ID == "" {
+		return mrSourceProject{}, errors.New("not a Modrinth project")
+	}
+	p, err := GetModrinthClient().Projects.Get(project.ID)
+	if err != nil {
+		return mrSourceProject{}, fmt.Errorf("failed to get project %s: %w", project.ID, err)
+	}
+	return mrSourceProject{
+		Project:   p,
+		VersionID: req.Option(MrOptionVersionID),
+		Filename:  req.Option(MrOptionFilename),
+	}, nil
+}
+
+func (s mrSource) ListVersions(project core.SourceProject, req core.SourceRequest) ([]core.SourceVersion, error) {
+	data, err := mrSourceProjectData(project, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []*modrinthApi.Version
+	switch {
+	case data.VersionID != "":
+		// Explicitly requested versions are installed even if they don't match the pack's versions/loaders
+		version, err := GetModrinthClient().Versions.Get(data.VersionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch version %s: %w", data.VersionID, err)
+		}
+		versions = []*modrinthApi.Version{version}
+	case data.Version != "":
+		version, err := ResolveModrinthVersion(data.Project, data.Version)
+		if err != nil {
+			return nil, err
+		}
+		versions = []*modrinthApi.Version{version}
+	default:
+		versions, err = ModrinthListVersions(*data.Project.ID, req.Pack, mrDatapackFolder(req))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]core.SourceVersion, 0, len(versions))
+	for _, v := range versions {
+		result = append(result, mrToSourceVersion(data.Project, v, data.Filename))
+	}
+	return result, nil
+}
+
+func mrToSourceVersion(project *modrinthApi.Project, v *modrinthApi.Version, optionalFilenameMatch string) core.SourceVersion {
+	version := core.SourceVersion{Data: mrSourceVersion{
+		Project:  project,
+		Version:  v,
+		Filename: optionalFilenameMatch,
+	}}
+	if v.ID != nil {
+		version.ID = *v.ID
+	}
+	if v.VersionNumber != nil {
+		version.Version = *v.VersionNumber
+	}
+	if len(v.Files) > 0 {
+		if file := GetModrinthVersionPrimaryFile(v, optionalFilenameMatch); file.Filename != nil {
+			version.FileName = *file.Filename
+		}
+	}
+	return version
+}
+
+func (s mrSource) SelectVersion(project core.SourceProject, versions []core.SourceVersion, req core.SourceRequest) (core.SourceVersion, error) {
+	mrVersions := make([]*modrinthApi.Version, 0, len(versions))
+	for _, v := range versions {
+		data, ok := v.Data.(mrSourceVersion)
+		if !ok {
+			return core.SourceVersion{}, errors.New("not a Modrinth version")
+		}
+		mrVersions = append(mrVersions, data.Version)
+	}
+
+	latest, err := ModrinthSelectLatestVersion(mrVersions, project.Name, req.Pack)
+	if err != nil {
+		return core.SourceVersion{}, err
+	}
+	for i, v := range mrVersions {
+		if v == latest {
+			return versions[i], nil
+		}
+	}
+	return core.SourceVersion{}, errors.New("no valid versions found")
+}
+
+func (s mrSource) NewMod(_ core.SourceProject, version core.SourceVersion, req core.SourceRequest) (*core.Mod, error) {
+	data, ok := version.Data.(mrSourceVersion)
+	if !ok {
+		return nil, errors.New("not a Modrinth version")
+	}
+	if len(data.Version.Files) == 0 {
+		return nil, errors.New("version doesn't have any files attached")
+	}
+
+	return ModrinthNewMod(data.Project, data.Version, req.MetaFolder, req.Pack.GetCompatibleLoaders(), mrDatapackFolder(req), data.Filename)
+}
+
+func (s mrSource) FindMissingDependencies(version core.SourceVersion, req core.SourceRequest) ([]*core.Mod, error) {
+	data, ok := version.Data.(mrSourceVersion)
+	if !ok {
+		return nil, errors.New("not a Modrinth version")
+	}
+	if len(data.Version.Dependencies) == 0 {
+		return nil, nil
+	}
+	return ModrinthFindMissingDependencies(data.Version, req.Pack, mrDatapackFolder(req))
+}
@@ -0,0 +1,127 @@
+package sources
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+const mrSourceTestProject = `{"id":"AANobbMI","slug":"sodium","title":"Sodium","description":"fast","project_type":"mod","client_side":"required","server_side":"unsupported","downloads":100,"versions":["v1","v2"]}`
+
+func TestMrSource(t *testing.T) {
+	withMrClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/project/sodium", "/project/AANobbMI":
+			_, _ = w.Write([]byte(mrSourceTestProject))
+		case "/project/AANobbMI/version":
+			_, _ = w.Write([]byte(`[` +
+				`{"id":"v2","project_id":"AANobbMI","version_number":"0.5.0","loaders":["fabric"],"game_versions":["1.20.1"],"date_published":"2024-02-01T00:00:00Z","files":[{"filename":"sodium-0.5.0.jar","primary":true,"url":"https://example.com/sodium-0.5.0.jar","hashes":{"sha1":"bbb"}}]},` +
+				`{"id":"v1","project_id":"AANobbMI","version_number":"0.4.0","loaders":["fabric"],"game_versions":["1.20.1"],"date_published":"2024-01-01T00:00:00Z","files":[{"filename":"sodium-0.4.0.jar","primary":true,"url":"https://example.com/sodium-0.4.0.jar","hashes":{"sha1":"aaa"}}]}` +
+				`]`))
+		case "/version/v1":
+			_, _ = w.Write([]byte(`{"id":"v1","project_id":"AANobbMI","version_number":"0.4.0","loaders":["fabric"],"game_versions":["1.20.1"],"date_published":"2024-01-01T00:00:00Z","files":[{"filename":"sodium-0.4.0.jar","primary":true,"url":"https://example.com/sodium-0.4.0.jar","hashes":{"sha1":"aaa"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	src := mrSource{}
+	req := core.SourceRequest{Pack: core.Pack{Versions: map[string]string{"minecraft": "1.20.1", "fabric": "0.15.0"}}}
+
+	t.Run("resolves a slug and installs the latest version", func(t *testing.T) {
+		project, err := src.ResolveProject("sodium", req)
+		require.NoError(t, err)
+		assert.Equal(t, "AANobbMI", project.ID)
+		assert.Equal(t, "Sodium", project.Name)
+		assert.Equal(t, uint64(100), project.Downloads)
+
+		mod, version, err := core.NewModFromSourceProject(src, project, req)
+		require.NoError(t, err)
+		assert.Equal(t, "v2", version.ID)
+		assert.Equal(t, "0.5.0", version.Version)
+		assert.Equal(t, "sodium-0.5.0.jar", mod.FileName)
+		assert.Equal(t, "sodium", mod.Slug)
+		assert.Equal(t, "mods", mod.ModType)
+	})
+
+	t.Run("a version ID in the URL is installed", func(t *testing.T) {
+		mod, version, err := core.NewModFromSource(src, "https://cdn.modrinth.com/data/AANobbMI/versions/v1/sodium-0.4.0.jar", req)
+		require.NoError(t, err)
+		assert.Equal(t, "v1", version.ID)
+		assert.Equal(t, "sodium-0.4.0.jar", mod.FileName)
+	})
+
+	t.Run("a version ID option is installed", func(t *testing.T) {
+		versionReq := req
+		versionReq.Options = map[string]string{MrOptionVersionID: "v1"}
+		_, version, err := core.NewModFromSource(src, "", versionReq)
+		require.NoError(t, err)
+		assert.Equal(t, "v1", version.ID)
+	})
+
+	t.Run("search results can be installed", func(t *testing.T) {
+		mod, _, err := core.NewModFromSourceProject(src, core.SourceProject{Source: "modrinth", ID: "AANobbMI"}, req)
+		require.NoError(t, err)
+		assert.Equal(t, "sodium-0.5.0.jar", mod.FileName)
+	})
+
+	t.Run("unknown project errors", func(t *testing.T) {
+		_, err := src.ResolveProject("does-not-exist", req)
+		assert.ErrorIs(t, err, core.ErrProjectNotFound)
+	})
+
+	t.Run("versions without dependencies have none missing", func(t *testing.T) {
+		_, version, err := core.NewModFromSource(src, "sodium", req)
+		require.NoError(t, err)
+		deps, err := src.FindMissingDependencies(version, req)
+		require.NoError(t, err)
+		assert.Empty(t, deps)
+	})
+}
+
+func TestMrSource_Datapack(t *testing.T) {
+	var requestedLoaders []string
+	withMrClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/project/terralith":
+			_, _ = w.Write([]byte(`{"id":"8oi3bsk5","slug":"terralith","title":"Terralith","project_type":"mod","client_side":"required","server_side":"required"}`))
+		case "/project/8oi3bsk5/version":
+			requestedLoaders = append(requestedLoaders, r.URL.Query().Get("loaders"))
+			_, _ = w.Write([]byte(`[{"id":"d1","project_id":"8oi3bsk5","version_number":"2.5.4","loaders":["datapack"],"game_versions":["1.21.1"],"date_published":"2024-08-01T00:00:00Z","files":[{"filename":"Terralith_1.21_v2.5.4.zip","primary":true,"url":"https://example.com/Terralith.zip","hashes":{"sha1":"ccc"}}]}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	src := mrSource{}
+	pack := core.Pack{Versions: map[string]string{"minecraft": "1.21.1", "fabric": "0.16.5"}}
+
+	t.Run("datapacks need a datapack folder", func(t *testing.T) {
+		_, _, err := core.NewModFromSource(src, "terralith", core.SourceRequest{Pack: pack})
+		assert.Error(t, err)
+	})
+
+	t.Run("datapacks are installed into the pack's datapack folder", func(t *testing.T) {
+		datapackPack := pack
+		datapackPack.Options = map[string]interface{}{}
+		datapackPack.SetDatapackFolder("config/paxi/datapacks")
+
+		mod, _, err := core.NewModFromSource(src, "terralith", core.SourceRequest{Pack: datapackPack})
+		require.NoError(t, err)
+		assert.Equal(t, "config/paxi/datapacks", mod.ModType)
+		assert.Contains(t, requestedLoaders[len(requestedLoaders)-1], "datapack")
+	})
+
+	t.Run("the datapack folder option overrides the pack setting", func(t *testing.T) {
+		mod, _, err := core.NewModFromSource(src, "terralith", core.SourceRequest{
+			Pack:    pack,
+			Options: map[string]string{MrOptionDatapackFolder: "world/datapacks"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "world/datapacks", mod.ModType)
+	})
+}
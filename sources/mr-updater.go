@@ -3,6 +3,7 @@ package sources
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	modrinthApi "codeberg.org/jmansfield/go-modrinth/modrinth"
 	"github.com/mitchellh/mapstructure"
@@ -14,11 +15,12 @@ func init() {
 	RegisterModrinth(core.DefaultRegistry)
 }
 
-// RegisterModrinth registers the Modrinth Updater on reg. Library consumers building an
+// RegisterModrinth registers the Modrinth Updater/Source on reg. Library consumers building an
 // isolated *core.Registry (instead of relying on core.DefaultRegistry) should call this
 // - or sources.RegisterAll - explicitly.
 func RegisterModrinth(reg *core.Registry) {
 	reg.AddUpdater(mrUpdater{})
+	reg.AddSource(mrSource{})
 }
 
 type mrUpdateData struct {
@@ -62,14 +64,25 @@ func (u mrUpdater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateC
 			continue
 		}
 
-		newVersion, err := ModrinthGetLatestVersion(data.ProjectID, mod.Name, pack, "")
+		var versions []*modrinthApi.Version
+		if pack.IsDatapack(mod) {
+			// Keep datapacks as datapacks, even if the project also publishes a mod
+			versions, err = mrListVersionsForLoaders(data.ProjectID, pack, mrDatapackLoaders)
+		} else {
+			versions, err = ModrinthListVersions(data.ProjectID, pack, "")
+		}
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: fmt.Errorf("failed to get latest version: %v", err)}
+			continue
+		}
+		newVersion, check, err := mrSelectAcceptedVersion(versions, data.InstalledVersion, mod, pack)
 		if err != nil {
 			results[i] = core.UpdateCheck{Error: fmt.Errorf("failed to get latest version: %v", err)}
 			continue
 		}
 
-		if *newVersion.ID == data.InstalledVersion { //The latest version from the site is the same as the installed one
-			results[i] = core.UpdateCheck{UpdateAvailable: false}
+		if newVersion == nil || *newVersion.ID == data.InstalledVersion { //The latest version from the site is the same as the installed one
+			results[i] = check
 			continue
 		}
 
@@ -80,16 +93,90 @@ func (u mrUpdater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateC
 
 		newFilename := GetModrinthVersionPrimaryFile(newVersion, "").Filename
 
-		results[i] = core.UpdateCheck{
-			UpdateAvailable: true,
-			UpdateString:    mod.FileName + " -> " + *newFilename,
-			CachedState:     mrCachedStateStore{data.ProjectID, newVersion},
+		check.UpdateAvailable = true
+		newVersionNumber := ""
+		if newVersion.VersionNumber != nil {
+			newVersionNumber = *newVersion.VersionNumber
 		}
+		check.UpdateString = mod.DescribeUpdate(newVersionNumber, *newFilename)
+		check.CachedState = mrCachedStateStore{data.ProjectID, newVersion}
+		results[i] = check
 	}
 
 	return results, nil
 }
 
+// mrSelectAcceptedVersion picks the latest of versions allowed by the mod's version constraint
+// and published before the minimum release age, without downgrading from an installed version
+// (with the ID installedID) that is too recent. The returned check records the newer versions
+// that were held back; the returned version is nil if no version is allowed.
+func mrSelectAcceptedVersion(versions []*modrinthApi.Version, installedID string, mod *core.Mod, pack core.Pack) (*modrinthApi.Version, core.UpdateCheck, error) {
+	var check core.UpdateCheck
+	latest, err := ModrinthSelectLatestVersion(versions, mod.Name, pack)
+	if err != nil {
+		return nil, check, err
+	}
+	label := func(v *modrinthApi.Version) string {
+		if v.VersionNumber == nil {
+			return ""
+		}
+		return *v.VersionNumber
+	}
+
+	if mod.VersionConstraint != "" {
+		mcVersions, err := pack.GetSupportedMCVersions()
+		if err != nil {
+			return nil, check, err
+		}
+		ok, err := mod.AcceptsVersion(label(latest), mcVersions)
+		if err != nil {
+			return nil, check, err
+		}
+		if !ok {
+			check.HeldBack = label(latest)
+			versions, err = core.FilterAcceptedVersions(mod, mcVersions, versions, label)
+			if err != nil || len(versions) == 0 {
+				return nil, check, err
+			}
+			if latest, err = ModrinthSelectLatestVersion(versions, mod.Name, pack); err != nil {
+				return nil, check, err
+			}
+		}
+	}
+
+	minAge, err := pack.MinReleaseAgeFor(mod)
+	if err != nil {
+		return nil, check, err
+	}
+	published := func(v *modrinthApi.Version) time.Time {
+		if v.DatePublished == nil {
+			return time.Time{}
+		}
+		return *v.DatePublished
+	}
+	isInstalled := func(v *modrinthApi.Version) bool {
+		return v.ID != nil && *v.ID == installedID
+	}
+	if !isInstalled(latest) && !core.ReleaseOldEnough(published(latest), minAge) {
+		check.TooRecent = label(latest)
+		var oldEnough []*modrinthApi.Version
+		for _, v := range versions {
+			if core.ReleaseOldEnough(published(v), minAge) {
+				oldEnough = append(oldEnough, v)
+			} else if isInstalled(v) {
+				return nil, check, nil
+			}
+		}
+		if len(oldEnough) == 0 {
+			return nil, check, nil
+		}
+		if latest, err = ModrinthSelectLatestVersion(oldEnough, mod.Name, pack); err != nil {
+			return nil, check, err
+		}
+	}
+	return latest, check, nil
+}
+
 func (u mrUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
 	for i, mod := range mods {
 		modState := cachedState[i].(mrCachedStateStore)
@@ -97,17 +184,17 @@ func (u mrUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
 
 		file := GetModrinthVersionPrimaryFile(version, "")
 
-		algorithm, hash := mrGetBestHash(file)
-		if algorithm == "" {
+		download, ok := mrFileDownload(file)
+		if !ok {
 			return errors.New("file for project " + mod.Name + " doesn't have a valid hash")
 		}
 
 		mod.FileName = *file.Filename
-		mod.Download = core.ModDownload{
-			URL:        *file.URL,
-			HashFormat: algorithm,
-			Hash:       hash,
+		mod.Version = ""
+		if version.VersionNumber != nil {
+			mod.Version = *version.VersionNumber
 		}
+		mod.Download = download
 		mod.Update["modrinth"]["version"] = version.ID
 	}
 
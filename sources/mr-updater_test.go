@@ -59,6 +59,11 @@ func TestMrUpdater_CheckUpdate(t *testing.T) {
 		require.Len(t, results, 1)
 		assert.True(t, results[0].UpdateAvailable)
 		assert.Equal(t, "old.jar -> new.jar", results[0].UpdateString)
+
+		mods[0].Version = "1.0"
+		results, err = mrUpdater{}.CheckUpdate(mods, pack)
+		require.NoError(t, err)
+		assert.Equal(t, "1.0 -> 2.0", results[0].UpdateString, "version labels are compared once recorded")
 	})
 
 	t.Run("no update when version matches installed", func(t *testing.T) {
@@ -74,6 +79,86 @@ func TestMrUpdater_CheckUpdate(t *testing.T) {
 		assert.False(t, results[0].UpdateAvailable)
 	})
 
+	t.Run("datapacks only update to datapack versions", func(t *testing.T) {
+		var loaders string
+		withMrClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			loaders = r.URL.Query().Get("loaders")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`[{"id":"v2","project_id":"abc","version_number":"2.0","loaders":["datapack"],"game_versions":["1.20.1"],"date_published":"2024-01-01T00:00:00Z","files":[{"filename":"new.zip","primary":true,"url":"https://example.com/new.zip","hashes":{"sha1":"abc123"}}]}]`))
+		}))
+
+		datapackPack := core.Pack{
+			Versions: map[string]string{"minecraft": "1.20.1", "fabric": "0.15.0"},
+			Options:  map[string]interface{}{core.DatapackFolderOption: "config/paxi/datapacks"},
+		}
+		mod := mrTestMod("Test Datapack", "abc", "v1")
+		mod.ModType = "config/paxi/datapacks"
+		results, err := mrUpdater{}.CheckUpdate([]*core.Mod{mod}, datapackPack)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].UpdateAvailable)
+		assert.Equal(t, `["datapack"]`, loaders)
+	})
+
+	t.Run("version constraint holds back newer versions", func(t *testing.T) {
+		withMrClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`[` +
+				`{"id":"v5","project_id":"abc","version_number":"mc1.20.1-5.0.0","game_versions":["1.20.1"],"date_published":"2024-03-01T00:00:00Z","files":[{"filename":"mod-5.0.0.jar","primary":true,"url":"https://example.com/5.jar","hashes":{"sha1":"abc123"}}]},` +
+				`{"id":"v43","project_id":"abc","version_number":"mc1.20.1-4.3.0","game_versions":["1.20.1"],"date_published":"2024-02-01T00:00:00Z","files":[{"filename":"mod-4.3.0.jar","primary":true,"url":"https://example.com/43.jar","hashes":{"sha1":"abc123"}}]},` +
+				`{"id":"v42","project_id":"abc","version_number":"mc1.20.1-4.2.1","game_versions":["1.20.1"],"date_published":"2024-01-01T00:00:00Z","files":[{"filename":"mod-4.2.1.jar","primary":true,"url":"https://example.com/42.jar","hashes":{"sha1":"abc123"}}]}]`))
+		}))
+
+		mod := mrTestMod("Test Mod", "abc", "v42")
+		mod.VersionConstraint = "^4.2"
+		results, err := mrUpdater{}.CheckUpdate([]*core.Mod{mod}, pack)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].UpdateAvailable)
+		assert.Equal(t, "old.jar -> mod-4.3.0.jar", results[0].UpdateString)
+		assert.Equal(t, "mc1.20.1-5.0.0", results[0].HeldBack)
+
+		mod.VersionConstraint = "~4.2"
+		results, err = mrUpdater{}.CheckUpdate([]*core.Mod{mod}, pack)
+		require.NoError(t, err)
+		assert.False(t, results[0].UpdateAvailable)
+		assert.Equal(t, "mc1.20.1-5.0.0", results[0].HeldBack)
+
+		mod.VersionConstraint = ">=6"
+		results, err = mrUpdater{}.CheckUpdate([]*core.Mod{mod}, pack)
+		require.NoError(t, err)
+		assert.False(t, results[0].UpdateAvailable, "no version satisfies the constraint")
+		assert.NoError(t, results[0].Error)
+	})
+
+	t.Run("minimum release age holds back recent versions", func(t *testing.T) {
+		withMrClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`[` +
+				`{"id":"v3","project_id":"abc","version_number":"3.0","game_versions":["1.20.1"],"date_published":"2999-01-01T00:00:00Z","files":[{"filename":"mod-3.0.jar","primary":true,"url":"https://example.com/3.jar","hashes":{"sha1":"abc123"}}]},` +
+				`{"id":"v2","project_id":"abc","version_number":"2.0","game_versions":["1.20.1"],"date_published":"2024-01-01T00:00:00Z","files":[{"filename":"mod-2.0.jar","primary":true,"url":"https://example.com/2.jar","hashes":{"sha1":"abc123"}}]}]`))
+		}))
+
+		agedPack := core.Pack{
+			Versions: map[string]string{"minecraft": "1.20.1"},
+			Options:  map[string]interface{}{core.MinReleaseAgeOption: "72h"},
+		}
+		outdated := mrTestMod("Outdated", "abc", "v1")
+		installedRecent := mrTestMod("Installed Recent", "abc", "v3")
+		exempt := mrTestMod("Exempt", "abc", "v1")
+		exempt.MinReleaseAge = "0"
+
+		results, err := mrUpdater{}.CheckUpdate([]*core.Mod{outdated, installedRecent, exempt}, agedPack)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Equal(t, "old.jar -> mod-2.0.jar", results[0].UpdateString)
+		assert.Equal(t, "3.0", results[0].TooRecent)
+		assert.Empty(t, results[0].HeldBack)
+		assert.False(t, results[1].UpdateAvailable, "recent versions that are installed aren't downgraded")
+		assert.Equal(t, "old.jar -> mod-3.0.jar", results[2].UpdateString)
+		assert.Empty(t, results[2].TooRecent)
+	})
+
 	t.Run("decode failure is reported per-mod", func(t *testing.T) {
 		badMod := &core.Mod{Name: "Bad Mod", Update: core.ModUpdate{"modrinth": nil}}
 		results, err := mrUpdater{}.CheckUpdate([]*core.Mod{badMod}, pack)
@@ -89,8 +174,10 @@ func TestMrUpdater_DoUpdate(t *testing.T) {
 	fileURL := "https://example.com/new.jar"
 	primary := true
 	versionID := "v2"
+	versionNumber := "2.0"
 	version := &modrinthApi.Version{
-		ID: &versionID,
+		ID:            &versionID,
+		VersionNumber: &versionNumber,
 		Files: []*modrinthApi.File{{
 			Filename: &filename,
 			URL:      &fileURL,
@@ -105,6 +192,7 @@ func TestMrUpdater_DoUpdate(t *testing.T) {
 	err := mrUpdater{}.DoUpdate([]*core.Mod{mod}, cachedState)
 	require.NoError(t, err)
 	assert.Equal(t, "new.jar", mod.FileName)
+	assert.Equal(t, "2.0", mod.Version)
 	assert.Equal(t, "deadbeef", mod.Download.Hash)
 	assert.Equal(t, "sha512", mod.Download.HashFormat)
 	assert.Equal(t, &versionID, mod.Update["modrinth"]["version"])
@@ -0,0 +1,219 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// mvnChecksumFormats are the checksum files tried next to an artifact, strongest first
+var mvnChecksumFormats = []string{"sha512", "sha256", "sha1"}
+
+// MavenCoordinates identifies an artifact in a Maven repository
+type MavenCoordinates struct {
+	Repository string
+	Group      string
+	Artifact   string
+	Classifier string
+}
+
+// ParseMavenCoordinates parses "group:artifact[:version[:classifier]]" coordinates, as
+// used in Gradle build scripts. The version is returned separately, and is empty if not
+// given.
+func ParseMavenCoordinates(repository string, coordinates string) (MavenCoordinates, string, error) {
+	parts := strings.Split(coordinates, ":")
+	if len(parts) < 2 || len(parts) > 4 || parts[0] == "" || parts[1] == "" {
+		return MavenCoordinates{}, "", fmt.Errorf("invalid Maven coordinates %q, expected group:artifact[:version[:classifier]]", coordinates)
+	}
+
+	coords := MavenCoordinates{
+		Repository: strings.TrimSuffix(repository, "/"),
+		Group:      parts[0],
+		Artifact:   parts[1],
+	}
+	var version string
+	if len(parts) > 2 {
+		version = parts[2]
+	}
+	if len(parts) > 3 {
+		coords.Classifier = parts[3]
+	}
+	return coords, version, nil
+}
+
+func (c MavenCoordinates) artifactUrl() string {
+	return strings.TrimSuffix(c.Repository, "/") + "/" + strings.ReplaceAll(c.Group, ".", "/") + "/" + c.Artifact
+}
+
+// MetadataUrl returns the URL of the artifact's maven-metadata.xml
+func (c MavenCoordinates) MetadataUrl() string {
+	return c.artifactUrl() + "/maven-metadata.xml"
+}
+
+// FileName returns the name of the artifact's JAR for version
+func (c MavenCoordinates) FileName(version string) string {
+	name := c.Artifact + "-" + version
+	if c.Classifier != "" {
+		name += "-" + c.Classifier
+	}
+	return name + ".jar"
+}
+
+// FileUrl returns the download URL of the artifact's JAR for version
+func (c MavenCoordinates) FileUrl(version string) string {
+	return c.artifactUrl() + "/" + version + "/" + c.FileName(version)
+}
+
+// MavenFindLatestVersion returns the highest version (by core.CompareVersions) of the
+// artifact that satisfies constraint (see core.VersionSatisfies). Snapshot versions are
+// never selected, as they aren't stable files.
+func MavenFindLatestVersion(coords MavenCoordinates, constraint string) (string, error) {
+	versions, err := mvnListVersions(coords)
+	if err != nil {
+		return "", err
+	}
+
+	var matching []string
+	for _, v := range versions {
+		ok, err := core.VersionSatisfies(v, constraint)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			matching = append(matching, v)
+		}
+	}
+
+	latest := mvnHighestVersion(matching)
+	if latest == "" {
+		if constraint != "" {
+			return "", fmt.Errorf("no versions of %s:%s match %q", coords.Group, coords.Artifact, constraint)
+		}
+		return "", fmt.Errorf("no versions of %s:%s found", coords.Group, coords.Artifact)
+	}
+	return latest, nil
+}
+
+// mvnListVersions returns the versions of the artifact listed in its maven-metadata.xml,
+// excluding snapshots
+func mvnListVersions(coords MavenCoordinates) ([]string, error) {
+	metadata, err := core.FetchMavenMetadata(coords.MetadataUrl())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versions of %s:%s: %w", coords.Group, coords.Artifact, err)
+	}
+
+	var versions []string
+	for _, v := range metadata.Versioning.Versions.Version {
+		if v != "" && !strings.HasSuffix(v, "-SNAPSHOT") {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+// mvnHighestVersion returns the highest of versions, or "" if there are none
+func mvnHighestVersion(versions []string) string {
+	latest := ""
+	for _, v := range versions {
+		if latest == "" || core.CompareVersions(latest, v) < 0 {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// MavenGetChecksum returns the strongest checksum published alongside the artifact's JAR
+// for version. If the repository publishes none, the JAR is downloaded into the download
+// cache (as url mods are) and its sha256 hash is returned instead. A checksum that can't
+// be fetched (e.g. a repository rejecting unknown extensions) is treated as not published.
+func MavenGetChecksum(coords MavenCoordinates, version string) (hashFormat string, hash string, err error) {
+	fileUrl := coords.FileUrl(version)
+	for _, format := range mvnChecksumFormats {
+		hash, err := mvnFetchChecksum(fileUrl + "." + format)
+		if err == nil && hash != "" {
+			return format, hash, nil
+		}
+	}
+
+	hash, err = urlHashFile(fileUrl)
+	if err != nil {
+		return "", "", err
+	}
+	return "sha256", hash, nil
+}
+
+// mvnFetchChecksum fetches a checksum file, returning "" if it doesn't exist
+func mvnFetchChecksum(url string) (string, error) {
+	resp, err := core.GetWithUA(url, "text/plain")
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return "", nil
+	}
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("failed to fetch %s: invalid status code %v", url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	// Some repositories write "<hash>  <filename>", like sha1sum
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// MavenNewMod creates a Mod for version of the artifact, recording the coordinates so it
+// can be updated, and constraint (if any) as the mod's version constraint
+func MavenNewMod(coords MavenCoordinates, version string, constraint string, modType string) (*core.Mod, error) {
+	if coords.Repository == "" {
+		return nil, errors.New("a Maven repository URL is required")
+	}
+
+	hashFormat, hash, err := MavenGetChecksum(coords, version)
+	if err != nil {
+		return nil, err
+	}
+
+	updateMap := make(core.ModUpdate)
+	updateMap["maven"], err = mvnUpdateData{
+		Repository: coords.Repository,
+		Group:      coords.Group,
+		Artifact:   coords.Artifact,
+		Classifier: coords.Classifier,
+		Version:    version,
+	}.ToMap()
+	if err != nil {
+		return nil, err
+	}
+
+	mod := core.NewMod(
+		core.SlugifyName(coords.Artifact),
+		coords.Artifact,
+		coords.FileName(version),
+		core.UniversalSide,
+		modType,
+		"",
+		false,
+		false,
+		updateMap,
+		core.ModDownload{
+			URL:        coords.FileUrl(version),
+			HashFormat: hashFormat,
+			Hash:       hash,
+		},
+		nil,
+	)
+	mod.Version = version
+	mod.VersionConstraint = constraint
+	return mod, nil
+}
@@ -0,0 +1,146 @@
+package sources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMavenRepo serves me.shedaniel.cloth:cloth-config with versions 11.0.99,
+// 11.1.106, 12.0.111 and 13.0-SNAPSHOT. Only the 12.0.111 JAR has a published .sha1 (its
+// .sha512 fails with a server error), and only the 11.1.106 "fabric" classifier JAR has a
+// .sha512. Other JARs are hashed into a temporary download cache.
+func newTestMavenRepo(t *testing.T) string {
+	t.Helper()
+	withTestCache(t)
+	const base = "/releases/me/shedaniel/cloth/cloth-config"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case base + "/maven-metadata.xml":
+			_, _ = w.Write([]byte(`<metadata><groupId>me.shedaniel.cloth</groupId><artifactId>cloth-config</artifactId><versioning>
+<release>12.0.111</release>
+<versions><version>11.0.99</version><version>11.1.106</version><version>12.0.111</version><version>13.0-SNAPSHOT</version></versions>
+</versioning></metadata>`))
+		case base + "/12.0.111/cloth-config-12.0.111.jar.sha512":
+			w.WriteHeader(http.StatusInternalServerError)
+		case base + "/12.0.111/cloth-config-12.0.111.jar.sha1":
+			_, _ = w.Write([]byte("ABCDEF0123  cloth-config-12.0.111.jar\n"))
+		case base + "/11.1.106/cloth-config-11.1.106-fabric.jar.sha512":
+			_, _ = w.Write([]byte("fff111"))
+		default:
+			if strings.HasSuffix(r.URL.Path, ".jar") {
+				_, _ = w.Write([]byte("jar contents"))
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + "/releases/"
+}
+
+func TestParseMavenCoordinates(t *testing.T) {
+	coords, version, err := ParseMavenCoordinates("https://maven.example.com/releases/", "me.shedaniel.cloth:cloth-config:11.1.106:fabric")
+	require.NoError(t, err)
+	assert.Equal(t, MavenCoordinates{
+		Repository: "https://maven.example.com/releases",
+		Group:      "me.shedaniel.cloth",
+		Artifact:   "cloth-config",
+		Classifier: "fabric",
+	}, coords)
+	assert.Equal(t, "11.1.106", version)
+	assert.Equal(t, "https://maven.example.com/releases/me/shedaniel/cloth/cloth-config/maven-metadata.xml", coords.MetadataUrl())
+	assert.Equal(t, "https://maven.example.com/releases/me/shedaniel/cloth/cloth-config/11.1.106/cloth-config-11.1.106-fabric.jar", coords.FileUrl("11.1.106"))
+
+	_, version, err = ParseMavenCoordinates("https://maven.example.com", "me.shedaniel.cloth:cloth-config")
+	require.NoError(t, err)
+	assert.Empty(t, version)
+
+	for _, invalid := range []string{"cloth-config", ":cloth-config", "a:b:c:d:e"} {
+		_, _, err := ParseMavenCoordinates("https://maven.example.com", invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestMavenFindLatestVersion(t *testing.T) {
+	repo := newTestMavenRepo(t)
+	coords, _, err := ParseMavenCoordinates(repo, "me.shedaniel.cloth:cloth-config")
+	require.NoError(t, err)
+
+	t.Run("highest non-snapshot version", func(t *testing.T) {
+		version, err := MavenFindLatestVersion(coords, "")
+		require.NoError(t, err)
+		assert.Equal(t, "12.0.111", version)
+	})
+
+	t.Run("version constraint", func(t *testing.T) {
+		version, err := MavenFindLatestVersion(coords, "<12")
+		require.NoError(t, err)
+		assert.Equal(t, "11.1.106", version)
+	})
+
+	t.Run("nothing matches", func(t *testing.T) {
+		_, err := MavenFindLatestVersion(coords, ">=14")
+		assert.ErrorContains(t, err, "no versions")
+	})
+
+	t.Run("unknown artifact", func(t *testing.T) {
+		missing := coords
+		missing.Artifact = "missing"
+		_, err := MavenFindLatestVersion(missing, "")
+		assert.Error(t, err)
+	})
+}
+
+func TestMavenGetChecksum(t *testing.T) {
+	repo := newTestMavenRepo(t)
+	coords, _, err := ParseMavenCoordinates(repo, "me.shedaniel.cloth:cloth-config")
+	require.NoError(t, err)
+
+	t.Run("published sha1, after a failed sha512", func(t *testing.T) {
+		format, hash, err := MavenGetChecksum(coords, "12.0.111")
+		require.NoError(t, err)
+		assert.Equal(t, "sha1", format)
+		assert.Equal(t, "abcdef0123", hash)
+	})
+
+	t.Run("stronger checksums are preferred", func(t *testing.T) {
+		classified := coords
+		classified.Classifier = "fabric"
+		format, hash, err := MavenGetChecksum(classified, "11.1.106")
+		require.NoError(t, err)
+		assert.Equal(t, "sha512", format)
+		assert.Equal(t, "fff111", hash)
+	})
+
+	t.Run("hashes the JAR without a published checksum", func(t *testing.T) {
+		format, hash, err := MavenGetChecksum(coords, "11.0.99")
+		require.NoError(t, err)
+		assert.Equal(t, "sha256", format)
+		assert.Equal(t, "dc6cd7efa1e5b601cbe937dddf25da66b3d45e488f87a0ce14ef24ac4df1e22a", hash)
+	})
+}
+
+func TestMavenNewMod(t *testing.T) {
+	repo := newTestMavenRepo(t)
+	coords, _, err := ParseMavenCoordinates(repo, "me.shedaniel.cloth:cloth-config")
+	require.NoError(t, err)
+
+	mod, err := MavenNewMod(coords, "12.0.111", "<13", "mods")
+	require.NoError(t, err)
+	assert.Equal(t, "cloth-config", mod.Slug)
+	assert.Equal(t, "cloth-config-12.0.111.jar", mod.FileName)
+	assert.Equal(t, coords.FileUrl("12.0.111"), mod.Download.URL)
+	assert.Equal(t, "sha1", mod.Download.HashFormat)
+	assert.Equal(t, "12.0.111", mod.Update["maven"]["version"])
+	assert.Equal(t, "<13", mod.VersionConstraint)
+	_, hasConstraint := mod.Update["maven"]["version-constraint"]
+	assert.False(t, hasConstraint)
+	assert.Equal(t, "me.shedaniel.cloth", mod.Update["maven"]["group"])
+	_, hasClassifier := mod.Update["maven"]["classifier"]
+	assert.False(t, hasClassifier)
+}
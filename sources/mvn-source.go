@@ -0,0 +1,103 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// Options understood by the Maven Source, in core.SourceRequest.Options
+const (
+	// MvnOptionRepository is the URL of the Maven repository (required)
+	MvnOptionRepository = "repository"
+	// MvnOptionVersionConstraint restricts the versions installed and updated to, see core.VersionSatisfies
+	MvnOptionVersionConstraint = "version-constraint"
+)
+
+// mvnSourceProject is the Maven-specific data stored in core.SourceProject.Data
+type mvnSourceProject struct {
+	Coordinates MavenCoordinates
+	// Version is set when the identifier included a version
+	Version string
+}
+
+type mvnSource struct{}
+
+func (s mvnSource) GetName() string {
+	return "maven"
+}
+
+func (s mvnSource) Search(string, core.SourceRequest) ([]core.SourceProject, error) {
+	return nil, fmt.Errorf("%w: Maven repositories do not support searching for projects", core.ErrSourceUnavailable)
+}
+
+// ResolveProject takes "group:artifact[:version[:classifier]]" coordinates, in the
+// repository given by the MvnOptionRepository option
+func (s mvnSource) ResolveProject(identifier string, req core.SourceRequest) (core.SourceProject, error) {
+	repository := req.Option(MvnOptionRepository)
+	if repository == "" {
+		return core.SourceProject{}, errors.New("a Maven repository URL is required")
+	}
+
+	coords, version, err := ParseMavenCoordinates(repository, identifier)
+	if err != nil {
+		return core.SourceProject{}, err
+	}
+
+	return core.SourceProject{
+		Source: "maven",
+		ID:     coords.Group + ":" + coords.Artifact,
+		Slug:   core.SlugifyName(coords.Artifact),
+		Name:   coords.Artifact,
+		Data:   mvnSourceProject{Coordinates: coords, Version: version},
+	}, nil
+}
+
+func (s mvnSource) ListVersions(project core.SourceProject, req core.SourceRequest) ([]core.SourceVersion, error) {
+	data, ok := project.Data.(mvnSourceProject)
+	if !ok {
+		return nil, errors.New("not a Maven project")
+	}
+
+	version := data.Version
+	if version == "" {
+		var err error
+		version, err = MavenFindLatestVersion(data.Coordinates, req.Option(MvnOptionVersionConstraint))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return []core.SourceVersion{{
+		ID:       version,
+		Version:  version,
+		FileName: data.Coordinates.FileName(version),
+	}}, nil
+}
+
+func (s mvnSource) SelectVersion(_ core.SourceProject, versions []core.SourceVersion, _ core.SourceRequest) (core.SourceVersion, error) {
+	// ListVersions only returns the latest matching version
+	if len(versions) == 0 {
+		return core.SourceVersion{}, errors.New("no versions found")
+	}
+	return versions[0], nil
+}
+
+func (s mvnSource) NewMod(project core.SourceProject, version core.SourceVersion, req core.SourceRequest) (*core.Mod, error) {
+	data, ok := project.Data.(mvnSourceProject)
+	if !ok {
+		return nil, errors.New("not a Maven project")
+	}
+
+	modType := req.MetaFolder
+	if modType == "" {
+		modType = "mods"
+	}
+	return MavenNewMod(data.Coordinates, version.Version, req.Option(MvnOptionVersionConstraint), modType)
+}
+
+func (s mvnSource) FindMissingDependencies(core.SourceVersion, core.SourceRequest) ([]*core.Mod, error) {
+	// POM dependencies are build dependencies, not mods to install alongside
+	return nil, nil
+}
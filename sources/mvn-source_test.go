@@ -0,0 +1,58 @@
+package sources
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func TestMvnSource(t *testing.T) {
+	repo := newTestMavenRepo(t)
+	src := mvnSource{}
+	req := core.SourceRequest{Options: map[string]string{MvnOptionRepository: repo}}
+
+	t.Run("installs the latest version", func(t *testing.T) {
+		mod, version, err := core.NewModFromSource(src, "me.shedaniel.cloth:cloth-config", req)
+		require.NoError(t, err)
+		assert.Equal(t, "12.0.111", version.Version)
+		assert.Equal(t, "cloth-config-12.0.111.jar", mod.FileName)
+		assert.Equal(t, "mods", mod.ModType)
+	})
+
+	t.Run("installs the version in the coordinates", func(t *testing.T) {
+		mod, _, err := core.NewModFromSource(src, "me.shedaniel.cloth:cloth-config:11.1.106:fabric", req)
+		require.NoError(t, err)
+		assert.Equal(t, "cloth-config-11.1.106-fabric.jar", mod.FileName)
+		assert.Equal(t, "sha512", mod.Download.HashFormat)
+		assert.Equal(t, "fabric", mod.Update["maven"]["classifier"])
+	})
+
+	t.Run("version constraint is applied and recorded", func(t *testing.T) {
+		constrained := core.SourceRequest{
+			MetaFolder: "libs",
+			Options: map[string]string{
+				MvnOptionRepository:        repo,
+				MvnOptionVersionConstraint: "11",
+			},
+		}
+		mod, _, err := core.NewModFromSource(src, "me.shedaniel.cloth:cloth-config", constrained)
+		require.NoError(t, err)
+		assert.Equal(t, "cloth-config-11.1.106.jar", mod.FileName)
+		assert.Equal(t, "libs", mod.ModType)
+		assert.Equal(t, "11", mod.VersionConstraint)
+	})
+
+	t.Run("repository is required", func(t *testing.T) {
+		_, err := src.ResolveProject("me.shedaniel.cloth:cloth-config", core.SourceRequest{})
+		assert.Error(t, err)
+	})
+
+	t.Run("search is unavailable", func(t *testing.T) {
+		_, err := src.Search("cloth", req)
+		assert.True(t, errors.Is(err, core.ErrSourceUnavailable))
+	})
+}
@@ -0,0 +1,141 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func init() {
+	RegisterMaven(core.DefaultRegistry)
+}
+
+// RegisterMaven registers the Maven Updater/Source on reg. Library consumers building an
+// isolated *core.Registry (instead of relying on core.DefaultRegistry) should call this
+// - or sources.RegisterAll - explicitly.
+func RegisterMaven(reg *core.Registry) {
+	reg.AddUpdater(mvnUpdater{})
+	reg.AddSource(mvnSource{})
+}
+
+type mvnUpdateData struct {
+	Repository string `mapstructure:"repository"`
+	Group      string `mapstructure:"group"`
+	Artifact   string `mapstructure:"artifact"`
+	Classifier string `mapstructure:"classifier,omitempty"`
+	Version    string `mapstructure:"version"`
+}
+
+func (u mvnUpdateData) ToMap() (map[string]interface{}, error) {
+	newMap := make(map[string]interface{})
+	err := mapstructure.Decode(u, &newMap)
+	return newMap, err
+}
+
+func (u mvnUpdateData) coordinates() MavenCoordinates {
+	return MavenCoordinates{
+		Repository: u.Repository,
+		Group:      u.Group,
+		Artifact:   u.Artifact,
+		Classifier: u.Classifier,
+	}
+}
+
+type mvnUpdater struct{}
+
+func (u mvnUpdater) GetName() string {
+	return "maven"
+}
+
+func (u mvnUpdater) ParseUpdate(updateUnparsed map[string]interface{}) (interface{}, error) {
+	var updateData mvnUpdateData
+	err := mapstructure.Decode(updateUnparsed, &updateData)
+	return updateData, err
+}
+
+type mvnCachedStateStore struct {
+	Version    string
+	HashFormat string
+	Hash       string
+}
+
+func (u mvnUpdater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateCheck, error) {
+	results := make([]core.UpdateCheck, len(mods))
+	// Only used to skip Minecraft versions in version numbers, so packs without one are still updated
+	mcVersions, _ := pack.GetSupportedMCVersions()
+
+	for i, mod := range mods {
+		var data mvnUpdateData
+		err := mod.DecodeNamedModSourceData("maven", &data)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: errors.New("failed to parse update metadata")}
+			continue
+		}
+
+		coords := data.coordinates()
+		versions, err := mvnListVersions(coords)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: err}
+			continue
+		}
+		accepted, err := core.FilterAcceptedVersions(mod, mcVersions, versions, func(v string) string { return v })
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: err}
+			continue
+		}
+		newVersion := mvnHighestVersion(accepted)
+		heldBack := ""
+		if latest := mvnHighestVersion(versions); latest != newVersion && core.CompareVersions(latest, data.Version) > 0 {
+			heldBack = latest
+		}
+
+		// The installed version may be newer than the latest allowed, e.g. if it was added
+		// before the constraint; that isn't an update
+		if newVersion == "" || core.CompareVersions(newVersion, data.Version) <= 0 {
+			results[i] = core.UpdateCheck{UpdateAvailable: false, HeldBack: heldBack}
+			continue
+		}
+
+		hashFormat, hash, err := MavenGetChecksum(coords, newVersion)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: fmt.Errorf("failed to get checksum: %w", err)}
+			continue
+		}
+
+		results[i] = core.UpdateCheck{
+			UpdateAvailable: true,
+			UpdateString:    mod.DescribeUpdate(newVersion, coords.FileName(newVersion)),
+			HeldBack:        heldBack,
+			CachedState:     mvnCachedStateStore{Version: newVersion, HashFormat: hashFormat, Hash: hash},
+		}
+	}
+
+	return results, nil
+}
+
+func (u mvnUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
+	for i, mod := range mods {
+		modState := cachedState[i].(mvnCachedStateStore)
+
+		var data mvnUpdateData
+		err := mod.DecodeNamedModSourceData("maven", &data)
+		if err != nil {
+			return err
+		}
+		coords := data.coordinates()
+
+		mod.FileName = coords.FileName(modState.Version)
+		mod.Version = modState.Version
+		mod.Download = core.ModDownload{
+			URL:        coords.FileUrl(modState.Version),
+			HashFormat: modState.HashFormat,
+			Hash:       modState.Hash,
+		}
+		mod.Update["maven"]["version"] = modState.Version
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package sources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func mvnTestMod(repo string, version string, constraint string) *core.Mod {
+	data := core.ModSourceData{
+		"repository": repo,
+		"group":      "me.shedaniel.cloth",
+		"artifact":   "cloth-config",
+		"version":    version,
+	}
+	return &core.Mod{
+		Name:              "cloth-config",
+		FileName:          "cloth-config-" + version + ".jar",
+		VersionConstraint: constraint,
+		Update:            core.ModUpdate{"maven": data},
+	}
+}
+
+func TestMvnUpdater_CheckUpdate(t *testing.T) {
+	repo := newTestMavenRepo(t)
+
+	mods := []*core.Mod{
+		mvnTestMod(repo, "11.0.99", ""),
+		mvnTestMod(repo, "12.0.111", ""),
+		mvnTestMod(repo, "11.0.99", "11"),
+		{Name: "Bad", Update: core.ModUpdate{"maven": core.ModSourceData{"group": 1}}},
+		mvnTestMod(repo, "12.0.111", "11"),
+	}
+
+	results, err := mvnUpdater{}.CheckUpdate(mods, core.Pack{})
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	assert.True(t, results[0].UpdateAvailable)
+	assert.Equal(t, "cloth-config-11.0.99.jar -> cloth-config-12.0.111.jar", results[0].UpdateString)
+	assert.False(t, results[1].UpdateAvailable)
+	assert.NoError(t, results[1].Error)
+	assert.True(t, results[2].UpdateAvailable)
+	assert.Equal(t, "cloth-config-11.0.99.jar -> cloth-config-11.1.106.jar", results[2].UpdateString)
+	assert.Equal(t, "12.0.111", results[2].HeldBack)
+	assert.Error(t, results[3].Error)
+	assert.False(t, results[4].UpdateAvailable, "older versions aren't offered as updates")
+	assert.NoError(t, results[4].Error)
+	assert.Empty(t, results[4].HeldBack, "the installed version isn't held back")
+}
+
+func TestMvnUpdater_DoUpdate(t *testing.T) {
+	repo := newTestMavenRepo(t)
+	mod := mvnTestMod(repo, "11.0.99", "")
+
+	err := mvnUpdater{}.DoUpdate([]*core.Mod{mod}, []interface{}{
+		mvnCachedStateStore{Version: "12.0.111", HashFormat: "sha1", Hash: "abcdef0123"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "cloth-config-12.0.111.jar", mod.FileName)
+	assert.Equal(t, repo+"me/shedaniel/cloth/cloth-config/12.0.111/cloth-config-12.0.111.jar", mod.Download.URL)
+	assert.Equal(t, "sha1", mod.Download.HashFormat)
+	assert.Equal(t, "abcdef0123", mod.Download.Hash)
+	assert.Equal(t, "12.0.111", mod.Update["maven"]["version"])
+}
@@ -0,0 +1,433 @@
+package sources
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+var pluginHttpClient = &http.Client{Timeout: core.DefaultHTTPTimeout}
+
+// pluginMod is the JSON representation of a core.Mod sent to and received from plugins
+type pluginMod struct {
+	Name     string       `json:"name"`
+	FileName string       `json:"filename"`
+	Version  string       `json:"version,omitempty"`
+	Side     core.ModSide `json:"side,omitempty"`
+	Pin      bool         `json:"pin,omitempty"`
+	// VersionConstraint is the mod's version constraint, which plugins should honour
+	VersionConstraint string            `json:"version-constraint,omitempty"`
+	Slug              string            `json:"slug,omitempty"`
+	Type              string            `json:"type,omitempty"`
+	Download          pluginModDownload `json:"download"`
+	Update            core.ModUpdate    `json:"update,omitempty"`
+}
+
+type pluginModDownload struct {
+	URL        string `json:"url,omitempty"`
+	HashFormat string `json:"hash-format"`
+	Hash       string `json:"hash"`
+	Mode       string `json:"mode,omitempty"`
+}
+
+// pluginPack is the subset of core.Pack sent to plugins
+type pluginPack struct {
+	Name       string            `json:"name"`
+	Versions   map[string]string `json:"versions"`
+	MCVersions []string          `json:"mcVersions"`
+	Loaders    []string          `json:"loaders"`
+}
+
+func toPluginMod(mod *core.Mod) pluginMod {
+	return pluginMod{
+		Name:              mod.Name,
+		FileName:          mod.FileName,
+		Version:           mod.Version,
+		Side:              mod.Side,
+		Pin:               mod.Pin,
+		VersionConstraint: mod.VersionConstraint,
+		Slug:              mod.Slug,
+		Type:              mod.ModType,
+		Download: pluginModDownload{
+			URL:        mod.Download.URL,
+			HashFormat: mod.Download.HashFormat,
+			Hash:       mod.Download.Hash,
+			Mode:       mod.Download.Mode,
+		},
+		Update: mod.Update,
+	}
+}
+
+func toPluginMods(mods []*core.Mod) []pluginMod {
+	result := make([]pluginMod, len(mods))
+	for i, mod := range mods {
+		result[i] = toPluginMod(mod)
+	}
+	return result
+}
+
+func toPluginPack(pack core.Pack) (pluginPack, error) {
+	mcVersions, err := pack.GetSupportedMCVersions()
+	if err != nil {
+		return pluginPack{}, err
+	}
+	return pluginPack{
+		Name:       pack.Name,
+		Versions:   pack.Versions,
+		MCVersions: mcVersions,
+		Loaders:    pack.GetCompatibleLoaders(),
+	}, nil
+}
+
+func (m pluginMod) toMod(modType string) *core.Mod {
+	if m.Type != "" {
+		modType = m.Type
+	}
+	slug := m.Slug
+	if slug == "" {
+		slug = core.SlugifyName(m.Name)
+	}
+	mod := core.NewMod(slug, m.Name, m.FileName, m.Side, modType, "", m.Pin, false, m.Update, core.ModDownload{
+		URL:        m.Download.URL,
+		HashFormat: m.Download.HashFormat,
+		Hash:       m.Download.Hash,
+		Mode:       m.Download.Mode,
+	}, nil)
+	mod.Version = m.Version
+	mod.VersionConstraint = m.VersionConstraint
+	return mod
+}
+
+type pluginUpdater struct {
+	plugin *Plugin
+}
+
+func (u pluginUpdater) GetName() string {
+	return u.plugin.Name
+}
+
+func (u pluginUpdater) ParseUpdate(updateUnparsed map[string]interface{}) (interface{}, error) {
+	// Update data is opaque to packwiz; the plugin receives it with each mod
+	return updateUnparsed, nil
+}
+
+type pluginCheckUpdateParams struct {
+	Mods []pluginMod `json:"mods"`
+	Pack pluginPack  `json:"pack"`
+}
+
+type pluginCheckUpdateResult struct {
+	UpdateAvailable bool            `json:"updateAvailable"`
+	UpdateString    string          `json:"updateString"`
+	HeldBack        string          `json:"heldBack"`
+	State           json.RawMessage `json:"state"`
+	Error           string          `json:"error"`
+}
+
+func (u pluginUpdater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateCheck, error) {
+	packData, err := toPluginPack(pack)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []pluginCheckUpdateResult
+	err = u.plugin.call("checkUpdate", pluginCheckUpdateParams{Mods: toPluginMods(mods), Pack: packData}, &res)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) != len(mods) {
+		return nil, fmt.Errorf("plugin %s returned %d update checks for %d mods", u.plugin.Name, len(res), len(mods))
+	}
+
+	results := make([]core.UpdateCheck, len(mods))
+	for i, r := range res {
+		if r.Error != "" {
+			results[i] = core.UpdateCheck{Error: errors.New(r.Error)}
+			continue
+		}
+		results[i] = core.UpdateCheck{
+			UpdateAvailable: r.UpdateAvailable,
+			UpdateString:    r.UpdateString,
+			HeldBack:        r.HeldBack,
+			CachedState:     r.State,
+		}
+	}
+	return results, nil
+}
+
+type pluginDoUpdateParams struct {
+	Mods   []pluginMod       `json:"mods"`
+	States []json.RawMessage `json:"states"`
+}
+
+type pluginDoUpdateResult struct {
+	Mods []pluginMod `json:"mods"`
+}
+
+func (u pluginUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
+	states := make([]json.RawMessage, len(cachedState))
+	for i, state := range cachedState {
+		states[i], _ = state.(json.RawMessage)
+	}
+
+	var res pluginDoUpdateResult
+	err := u.plugin.call("doUpdate", pluginDoUpdateParams{Mods: toPluginMods(mods), States: states}, &res)
+	if err != nil {
+		return err
+	}
+	if len(res.Mods) != len(mods) {
+		return fmt.Errorf("plugin %s returned %d updated mods for %d mods", u.plugin.Name, len(res.Mods), len(mods))
+	}
+
+	for i, mod := range mods {
+		updated := res.Mods[i]
+		if updated.Name != "" {
+			mod.Name = updated.Name
+		}
+		mod.FileName = updated.FileName
+		mod.Version = updated.Version
+		mod.Download = core.ModDownload{
+			URL:        updated.Download.URL,
+			HashFormat: updated.Download.HashFormat,
+			Hash:       updated.Download.Hash,
+			Mode:       updated.Download.Mode,
+		}
+		if data, ok := updated.Update[u.plugin.Name]; ok {
+			mod.Update[u.plugin.Name] = data
+		}
+	}
+	return nil
+}
+
+type pluginDownloader struct {
+	plugin *Plugin
+}
+
+type pluginGetFilesMetadataParams struct {
+	Mods []pluginMod `json:"mods"`
+}
+
+// pluginDownloadMetadata is returned by plugins for each mod: either a URL (with optional
+// request headers, e.g. for authentication) or details of a manual download
+type pluginDownloadMetadata struct {
+	URL     string                `json:"url"`
+	Headers map[string]string     `json:"headers"`
+	Manual  *pluginManualDownload `json:"manual"`
+}
+
+type pluginManualDownload struct {
+	Name     string `json:"name"`
+	FileName string `json:"filename"`
+	URL      string `json:"url"`
+}
+
+func (d pluginDownloader) GetFilesMetadata(mods []*core.Mod) ([]core.MetaDownloaderData, error) {
+	var res []pluginDownloadMetadata
+	err := d.plugin.call("getFilesMetadata", pluginGetFilesMetadataParams{Mods: toPluginMods(mods)}, &res)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) != len(mods) {
+		return nil, fmt.Errorf("plugin %s returned metadata for %d files, expected %d", d.plugin.Name, len(res), len(mods))
+	}
+
+	data := make([]core.MetaDownloaderData, len(res))
+	for i := range res {
+		data[i] = &res[i]
+	}
+	return data, nil
+}
+
+func (m *pluginDownloadMetadata) GetManualDownload() (bool, core.ManualDownload) {
+	if m.Manual == nil {
+		return false, core.ManualDownload{}
+	}
+	return true, core.ManualDownload{
+		Name:     m.Manual.Name,
+		FileName: m.Manual.FileName,
+		URL:      m.Manual.URL,
+	}
+}
+
+func (m *pluginDownloadMetadata) DownloadFile() (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", m.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", core.UserAgent)
+	req.Header.Set("Accept", "application/octet-stream")
+	for k, v := range m.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := pluginHttpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", m.URL, err)
+	}
+	if resp.StatusCode != 200 {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("failed to download %s: invalid status code %v", m.URL, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+type pluginSource struct {
+	plugin *Plugin
+}
+
+// pluginProject is the JSON representation of a core.SourceProject
+type pluginProject struct {
+	ID        string `json:"id"`
+	Slug      string `json:"slug"`
+	Name      string `json:"name"`
+	Summary   string `json:"summary"`
+	Downloads uint64 `json:"downloads"`
+}
+
+// pluginVersion is the JSON representation of a core.SourceVersion
+type pluginVersion struct {
+	ID       string `json:"id"`
+	Version  string `json:"version"`
+	FileName string `json:"filename"`
+}
+
+type pluginSearchParams struct {
+	Query string     `json:"query"`
+	Pack  pluginPack `json:"pack"`
+}
+
+type pluginResolveProjectParams struct {
+	Identifier string            `json:"identifier"`
+	Pack       pluginPack        `json:"pack"`
+	Options    map[string]string `json:"options,omitempty"`
+}
+
+type pluginListVersionsParams struct {
+	Project pluginProject     `json:"project"`
+	Pack    pluginPack        `json:"pack"`
+	Options map[string]string `json:"options,omitempty"`
+}
+
+type pluginNewModParams struct {
+	Project    pluginProject     `json:"project"`
+	Version    pluginVersion     `json:"version"`
+	Pack       pluginPack        `json:"pack"`
+	MetaFolder string            `json:"metaFolder,omitempty"`
+	Options    map[string]string `json:"options,omitempty"`
+}
+
+func (s pluginSource) GetName() string {
+	return s.plugin.Name
+}
+
+func (s pluginSource) toSourceProject(p pluginProject) core.SourceProject {
+	return core.SourceProject{
+		Source:    s.plugin.Name,
+		ID:        p.ID,
+		Slug:      p.Slug,
+		Name:      p.Name,
+		Summary:   p.Summary,
+		Downloads: p.Downloads,
+	}
+}
+
+func (s pluginSource) Search(query string, req core.SourceRequest) ([]core.SourceProject, error) {
+	packData, err := toPluginPack(req.Pack)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []pluginProject
+	if err := s.plugin.call("search", pluginSearchParams{Query: query, Pack: packData}, &res); err != nil {
+		return nil, err
+	}
+	projects := make([]core.SourceProject, len(res))
+	for i, p := range res {
+		projects[i] = s.toSourceProject(p)
+	}
+	return projects, nil
+}
+
+func (s pluginSource) ResolveProject(identifier string, req core.SourceRequest) (core.SourceProject, error) {
+	packData, err := toPluginPack(req.Pack)
+	if err != nil {
+		return core.SourceProject{}, err
+	}
+
+	var res pluginProject
+	err = s.plugin.call("resolveProject", pluginResolveProjectParams{Identifier: identifier, Pack: packData, Options: req.Options}, &res)
+	if err != nil {
+		return core.SourceProject{}, err
+	}
+	return s.toSourceProject(res), nil
+}
+
+func fromSourceProject(project core.SourceProject) pluginProject {
+	return pluginProject{
+		ID:        project.ID,
+		Slug:      project.Slug,
+		Name:      project.Name,
+		Summary:   project.Summary,
+		Downloads: project.Downloads,
+	}
+}
+
+func (s pluginSource) ListVersions(project core.SourceProject, req core.SourceRequest) ([]core.SourceVersion, error) {
+	packData, err := toPluginPack(req.Pack)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []pluginVersion
+	err = s.plugin.call("listVersions", pluginListVersionsParams{Project: fromSourceProject(project), Pack: packData, Options: req.Options}, &res)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]core.SourceVersion, len(res))
+	for i, v := range res {
+		versions[i] = core.SourceVersion{ID: v.ID, Version: v.Version, FileName: v.FileName}
+	}
+	return versions, nil
+}
+
+func (s pluginSource) SelectVersion(_ core.SourceProject, versions []core.SourceVersion, _ core.SourceRequest) (core.SourceVersion, error) {
+	// Plugins list versions newest first, already filtered for the pack
+	if len(versions) == 0 {
+		return core.SourceVersion{}, errors.New("no versions found")
+	}
+	return versions[0], nil
+}
+
+func (s pluginSource) NewMod(project core.SourceProject, version core.SourceVersion, req core.SourceRequest) (*core.Mod, error) {
+	packData, err := toPluginPack(req.Pack)
+	if err != nil {
+		return nil, err
+	}
+
+	var res pluginMod
+	err = s.plugin.call("newMod", pluginNewModParams{
+		Project:    fromSourceProject(project),
+		Version:    pluginVersion{ID: version.ID, Version: version.Version, FileName: version.FileName},
+		Pack:       packData,
+		MetaFolder: req.MetaFolder,
+		Options:    req.Options,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	mod := res.toMod("mods")
+	if req.MetaFolder != "" {
+		mod.ModType = req.MetaFolder
+	}
+	return mod, nil
+}
+
+func (s pluginSource) FindMissingDependencies(core.SourceVersion, core.SourceRequest) ([]*core.Mod, error) {
+	// The plugin protocol has no dependency information
+	return nil, nil
+}
@@ -0,0 +1,342 @@
+package sources
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/leocov-dev/packwiz-nxt/config"
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// Capabilities a plugin can declare in its initialize response
+const (
+	// PluginCapabilityUpdate registers the plugin as a core.Updater
+	PluginCapabilityUpdate = "update"
+	// PluginCapabilityDownload registers the plugin as a core.MetaDownloader, for mods
+	// with the download mode "metadata:<name>"
+	PluginCapabilityDownload = "download"
+	// PluginCapabilitySource registers the plugin as a core.Source, for searching and adding projects
+	PluginCapabilitySource = "source"
+)
+
+// PluginInitializeTimeout is how long a plugin has to answer the initialize handshake, and to
+// exit once its stdin is closed, before it is killed
+var PluginInitializeTimeout = 10 * time.Second
+
+// PluginCallTimeout is how long a plugin has to answer any other request before it is killed
+var PluginCallTimeout = 5 * time.Minute
+
+// Plugin is a running provider plugin: an executable speaking JSON-RPC 2.0 over its
+// stdin/stdout, one message per line. Calls are serialised; the plugin is expected to
+// exit when its stdin is closed. A plugin that doesn't respond in time (see PluginCallTimeout)
+// is killed. See docs/PLUGINS.md for the protocol.
+type Plugin struct {
+	Path         string
+	Name         string
+	Capabilities []string
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	// stdoutPipe is closed along with killing the plugin, as the plugin's own children may
+	// still hold it open
+	stdoutPipe io.ReadCloser
+	nextID     int64
+	logger     core.Logger
+}
+
+type pluginRequest struct {
+	JsonRpc string `json:"jsonrpc"`
+	ID      int64  `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type pluginResponse struct {
+	JsonRpc string          `json:"jsonrpc"`
+	ID      *int64          `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	Result  json.RawMessage `json:"result"`
+	Error   *pluginError    `json:"error"`
+}
+
+type pluginError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *pluginError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+type pluginLogParams struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+type pluginInitializeParams struct {
+	PackwizVersion string `json:"packwizVersion"`
+}
+
+type pluginInitializeResult struct {
+	Name         string   `json:"name"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// StartPlugin starts the plugin executable at path and performs the initialize
+// handshake, which tells packwiz the plugin's name (its update key) and capabilities.
+// Messages the plugin logs are written to logger.
+func StartPlugin(path string, logger core.Logger) (*Plugin, error) {
+	cmd := exec.Command(path)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start plugin %s: %w", path, err)
+	}
+
+	p := &Plugin{
+		Path:       path,
+		cmd:        cmd,
+		stdin:      stdin,
+		stdout:     bufio.NewReader(stdout),
+		stdoutPipe: stdout,
+		logger:     logger,
+	}
+
+	var res pluginInitializeResult
+	err = p.callWithTimeout("initialize", pluginInitializeParams{PackwizVersion: config.Version}, &res, PluginInitializeTimeout)
+	if err != nil {
+		_ = p.Close()
+		return nil, fmt.Errorf("failed to initialize plugin %s: %w", path, err)
+	}
+	if res.Name == "" {
+		_ = p.Close()
+		return nil, fmt.Errorf("plugin %s did not declare a name", path)
+	}
+	p.Name = res.Name
+	p.Capabilities = res.Capabilities
+	return p, nil
+}
+
+// HasCapability returns true if the plugin declared capability when it was initialized
+func (p *Plugin) HasCapability(capability string) bool {
+	for _, c := range p.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// Close closes the plugin's stdin and waits for it to exit, killing it if it doesn't exit
+// within PluginInitializeTimeout
+func (p *Plugin) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_ = p.stdin.Close()
+	timer := time.AfterFunc(PluginInitializeTimeout, p.kill)
+	defer timer.Stop()
+	return p.cmd.Wait()
+}
+
+// kill stops the plugin, ending any read waiting for it to respond
+func (p *Plugin) kill() {
+	_ = p.cmd.Process.Kill()
+	_ = p.stdoutPipe.Close()
+}
+
+// call sends a request to the plugin and decodes the result of the matching response
+// into result. Log notifications sent by the plugin before responding are forwarded to
+// the plugin's logger.
+func (p *Plugin) call(method string, params any, result any) error {
+	return p.callWithTimeout(method, params, result, PluginCallTimeout)
+}
+
+// callWithTimeout is call, killing the plugin if it hasn't responded within timeout
+func (p *Plugin) callWithTimeout(method string, params any, result any, timeout time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var timedOut atomic.Bool
+	timer := time.AfterFunc(timeout, func() {
+		timedOut.Store(true)
+		p.kill()
+	})
+	defer timer.Stop()
+
+	p.nextID++
+	id := p.nextID
+	data, err := json.Marshal(pluginRequest{JsonRpc: "2.0", ID: id, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if _, err := p.stdin.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to send %s: %w", method, err)
+	}
+
+	for {
+		line, err := p.stdout.ReadBytes('\n')
+		if err != nil {
+			if timedOut.Load() {
+				return fmt.Errorf("plugin did not respond to %s within %s", method, timeout)
+			}
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("plugin exited before responding to %s", method)
+			}
+			return fmt.Errorf("failed to read response to %s: %w", method, err)
+		}
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+
+		var res pluginResponse
+		if err := json.Unmarshal(line, &res); err != nil {
+			return fmt.Errorf("invalid response to %s: %w", method, err)
+		}
+		if res.ID == nil {
+			p.handleNotification(res)
+			continue
+		}
+		if *res.ID != id {
+			return fmt.Errorf("response ID %d does not match request ID %d", *res.ID, id)
+		}
+		if res.Error != nil {
+			return res.Error
+		}
+		if result == nil || len(res.Result) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(res.Result, result); err != nil {
+			return fmt.Errorf("invalid result for %s: %w", method, err)
+		}
+		return nil
+	}
+}
+
+func (p *Plugin) handleNotification(res pluginResponse) {
+	if res.Method != "log" {
+		return
+	}
+	var params pluginLogParams
+	if err := json.Unmarshal(res.Params, &params); err != nil {
+		return
+	}
+	name := p.Name
+	if name == "" {
+		name = filepath.Base(p.Path)
+	}
+	if params.Level == "warn" {
+		p.logger.Warnf("%s: %s\n", name, params.Message)
+	} else {
+		p.logger.Infof("%s: %s\n", name, params.Message)
+	}
+}
+
+// RegisterPlugin registers p on reg under the name it declared, as an Updater,
+// MetaDownloader and/or Source depending on its capabilities. A plugin may not replace a
+// provider that is already registered under the same name.
+func RegisterPlugin(reg *core.Registry, p *Plugin) error {
+	_, isUpdater := reg.GetUpdater(p.Name)
+	_, isDownloader := reg.GetMetaDownloader(p.Name)
+	_, isSource := reg.GetSource(p.Name)
+	if isUpdater || isDownloader || isSource {
+		return fmt.Errorf("plugin %s: %q is already registered", p.Path, p.Name)
+	}
+
+	if p.HasCapability(PluginCapabilityUpdate) {
+		reg.AddUpdater(pluginUpdater{p})
+	}
+	if p.HasCapability(PluginCapabilityDownload) {
+		reg.AddMetaDownloader(p.Name, pluginDownloader{p})
+	}
+	if p.HasCapability(PluginCapabilitySource) {
+		reg.AddSource(pluginSource{p})
+	}
+	return nil
+}
+
+// FindPlugins lists the executables in each of dirs, in order. Directories that don't
+// exist are skipped.
+func FindPlugins(dirs []string) ([]string, error) {
+	var paths []string
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read plugin directory %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			// Stat rather than using entry.Info() so that symlinks to executables are followed
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() || !isExecutable(path, info) {
+				continue
+			}
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+func isExecutable(path string, info os.FileInfo) bool {
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".exe", ".bat", ".cmd":
+			return true
+		}
+		return false
+	}
+	return info.Mode().Perm()&0111 != 0
+}
+
+// LoadPlugins starts every plugin executable found in dirs and registers it on reg (see
+// RegisterPlugin). Plugins that fail to start or register are skipped and reported in the
+// returned error; the ones that loaded successfully are returned so they can be closed.
+func LoadPlugins(reg *core.Registry, dirs []string) ([]*Plugin, error) {
+	paths, err := FindPlugins(dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	var plugins []*Plugin
+	var errs []error
+	for _, path := range paths {
+		p, err := StartPlugin(path, reg.Logger())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := RegisterPlugin(reg, p); err != nil {
+			_ = p.Close()
+			errs = append(errs, err)
+			continue
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, errors.Join(errs...)
+}
@@ -0,0 +1,287 @@
+package sources
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+// When stubPluginEnv is set, the test binary acts as a provider plugin instead of running
+// the tests, so the plugin protocol can be exercised end to end against a real process.
+const stubPluginEnv = "PACKWIZ_TEST_STUB_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if name := os.Getenv(stubPluginEnv); name != "" {
+		runStubPlugin(name)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runStubPlugin serves a "private" artifact server: every mod's latest version is 2.0. A
+// plugin named "hang" never responds.
+func runStubPlugin(name string) {
+	if name == "hang" {
+		select {}
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	encoder := json.NewEncoder(os.Stdout)
+	for scanner.Scan() {
+		var req struct {
+			ID     int64           `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			os.Exit(1)
+		}
+
+		var result any
+		var rpcErr *pluginError
+		switch req.Method {
+		case "initialize":
+			result = pluginInitializeResult{Name: name, Capabilities: []string{"update", "download", "source"}}
+		case "checkUpdate":
+			var params pluginCheckUpdateParams
+			_ = json.Unmarshal(req.Params, &params)
+			_ = encoder.Encode(map[string]any{"jsonrpc": "2.0", "method": "log", "params": pluginLogParams{Level: "warn", Message: "checking"}})
+			checks := make([]pluginCheckUpdateResult, len(params.Mods))
+			for i, mod := range params.Mods {
+				switch mod.Update[name]["version"] {
+				case "2.0":
+				case nil:
+					checks[i].Error = "missing version"
+				default:
+					checks[i] = pluginCheckUpdateResult{
+						UpdateAvailable: true,
+						UpdateString:    mod.FileName + " -> " + "private-2.0.jar",
+						State:           json.RawMessage(`{"version":"2.0"}`),
+					}
+				}
+			}
+			result = checks
+		case "doUpdate":
+			var params pluginDoUpdateParams
+			_ = json.Unmarshal(req.Params, &params)
+			for i := range params.Mods {
+				var state struct{ Version string }
+				_ = json.Unmarshal(params.States[i], &state)
+				params.Mods[i].FileName = "private-" + state.Version + ".jar"
+				params.Mods[i].Download = pluginModDownload{HashFormat: "sha256", Hash: "abc", Mode: "metadata:" + name}
+				params.Mods[i].Update[name]["version"] = state.Version
+			}
+			result = pluginDoUpdateResult{Mods: params.Mods}
+		case "getFilesMetadata":
+			var params pluginGetFilesMetadataParams
+			_ = json.Unmarshal(req.Params, &params)
+			metadata := make([]pluginDownloadMetadata, len(params.Mods))
+			for i, mod := range params.Mods {
+				if mod.Download.URL == "" {
+					metadata[i].Manual = &pluginManualDownload{Name: mod.Name, FileName: mod.FileName, URL: "https://example.com"}
+					continue
+				}
+				metadata[i] = pluginDownloadMetadata{URL: mod.Download.URL, Headers: map[string]string{"Authorization": "Bearer secret"}}
+			}
+			result = metadata
+		case "search", "resolveProject":
+			project := pluginProject{ID: "1", Slug: "private-mod", Name: "Private Mod", Downloads: 5}
+			if req.Method == "search" {
+				result = []pluginProject{project}
+			} else {
+				result = project
+			}
+		case "listVersions":
+			result = []pluginVersion{{ID: "v2", Version: "2.0", FileName: "private-2.0.jar"}, {ID: "v1", Version: "1.0", FileName: "private-1.0.jar"}}
+		case "newMod":
+			var params pluginNewModParams
+			_ = json.Unmarshal(req.Params, &params)
+			result = pluginMod{
+				Name:     params.Project.Name,
+				FileName: params.Version.FileName,
+				Side:     core.UniversalSide,
+				Download: pluginModDownload{HashFormat: "sha256", Hash: "abc", Mode: "metadata:" + name},
+				Update:   core.ModUpdate{name: core.ModSourceData{"version": params.Version.Version}},
+			}
+		default:
+			rpcErr = &pluginError{Code: -32601, Message: "method not found"}
+		}
+
+		if rpcErr != nil {
+			_ = encoder.Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "error": rpcErr})
+		} else {
+			_ = encoder.Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+		}
+	}
+}
+
+func startStubPlugin(t *testing.T, name string) *Plugin {
+	t.Helper()
+	t.Setenv(stubPluginEnv, name)
+	p, err := StartPlugin(os.Args[0], &recordingLogger{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close() })
+	return p
+}
+
+func TestStartPlugin(t *testing.T) {
+	p := startStubPlugin(t, "private")
+	assert.Equal(t, "private", p.Name)
+	assert.True(t, p.HasCapability(PluginCapabilityUpdate))
+	assert.True(t, p.HasCapability(PluginCapabilityDownload))
+	assert.True(t, p.HasCapability(PluginCapabilitySource))
+
+	err := p.call("unknown", nil, nil)
+	assert.ErrorContains(t, err, "method not found")
+}
+
+func TestRegisterPlugin(t *testing.T) {
+	reg := core.NewRegistry()
+	RegisterAll(reg)
+
+	p := startStubPlugin(t, "private")
+	require.NoError(t, RegisterPlugin(reg, p))
+
+	_, ok := reg.GetUpdater("private")
+	assert.True(t, ok)
+	_, ok = reg.GetMetaDownloader("private")
+	assert.True(t, ok)
+	_, ok = reg.GetSource("private")
+	assert.True(t, ok)
+
+	t.Run("built-in providers cannot be replaced", func(t *testing.T) {
+		assert.Error(t, RegisterPlugin(reg, startStubPlugin(t, "modrinth")))
+	})
+
+	t.Run("meta downloaders cannot be replaced", func(t *testing.T) {
+		reg.AddMetaDownloader("downloader-only", pluginDownloader{p})
+		assert.Error(t, RegisterPlugin(reg, startStubPlugin(t, "downloader-only")))
+	})
+}
+
+func TestStartPlugin_Timeout(t *testing.T) {
+	timeout := PluginInitializeTimeout
+	PluginInitializeTimeout = 200 * time.Millisecond
+	t.Cleanup(func() { PluginInitializeTimeout = timeout })
+
+	t.Setenv(stubPluginEnv, "hang")
+	start := time.Now()
+	_, err := StartPlugin(os.Args[0], &recordingLogger{})
+	assert.ErrorContains(t, err, "did not respond to initialize")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestPluginUpdater(t *testing.T) {
+	p := startStubPlugin(t, "private")
+	updater := pluginUpdater{p}
+	pack := core.Pack{Versions: map[string]string{"minecraft": "1.20.1"}}
+
+	mods := []*core.Mod{
+		{Name: "Old", FileName: "private-1.0.jar", Update: core.ModUpdate{"private": core.ModSourceData{"version": "1.0"}}},
+		{Name: "Current", FileName: "private-2.0.jar", Update: core.ModUpdate{"private": core.ModSourceData{"version": "2.0"}}},
+		{Name: "Broken", Update: core.ModUpdate{"private": core.ModSourceData{}}},
+	}
+
+	checks, err := updater.CheckUpdate(mods, pack)
+	require.NoError(t, err)
+	require.Len(t, checks, 3)
+	assert.True(t, checks[0].UpdateAvailable)
+	assert.Equal(t, "private-1.0.jar -> private-2.0.jar", checks[0].UpdateString)
+	assert.False(t, checks[1].UpdateAvailable)
+	assert.EqualError(t, checks[2].Error, "missing version")
+	assert.Equal(t, []string{"private: checking\n"}, p.logger.(*recordingLogger).warnings)
+
+	require.NoError(t, updater.DoUpdate(mods[:1], []any{checks[0].CachedState}))
+	assert.Equal(t, "private-2.0.jar", mods[0].FileName)
+	assert.Equal(t, "metadata:private", mods[0].Download.Mode)
+	assert.Equal(t, "2.0", mods[0].Update["private"]["version"])
+}
+
+func TestPluginDownloader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("jar contents"))
+	}))
+	t.Cleanup(server.Close)
+
+	p := startStubPlugin(t, "private")
+	mods := []*core.Mod{
+		{Name: "Hosted", FileName: "hosted.jar", Download: core.ModDownload{URL: server.URL + "/hosted.jar"}},
+		{Name: "Manual", FileName: "manual.jar"},
+	}
+
+	data, err := pluginDownloader{p}.GetFilesMetadata(mods)
+	require.NoError(t, err)
+	require.Len(t, data, 2)
+
+	manual, _ := data[0].GetManualDownload()
+	assert.False(t, manual)
+	file, err := data[0].DownloadFile()
+	require.NoError(t, err)
+	defer file.Close()
+	buf := make([]byte, 32)
+	n, _ := file.Read(buf)
+	assert.Equal(t, "jar contents", string(buf[:n]))
+
+	manual, info := data[1].GetManualDownload()
+	assert.True(t, manual)
+	assert.Equal(t, "manual.jar", info.FileName)
+}
+
+func TestPluginSource(t *testing.T) {
+	p := startStubPlugin(t, "private")
+	src := pluginSource{p}
+	req := core.SourceRequest{Pack: core.Pack{Versions: map[string]string{"minecraft": "1.20.1"}}}
+
+	results, err := src.Search("private", req)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "private", results[0].Source)
+	assert.Equal(t, uint64(5), results[0].Downloads)
+
+	mod, version, err := core.NewModFromSource(src, "private-mod", req)
+	require.NoError(t, err)
+	assert.Equal(t, "2.0", version.Version)
+	assert.Equal(t, "private-2.0.jar", mod.FileName)
+	assert.Equal(t, "private-mod", mod.Slug)
+	assert.Equal(t, "mods", mod.ModType)
+	assert.Equal(t, "2.0", mod.Update["private"]["version"])
+}
+
+func TestLoadPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin discovery test relies on symlinks and executable bits")
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.Symlink(os.Args[0], filepath.Join(dir, "private-plugin")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a plugin"), 0644))
+
+	paths, err := FindPlugins([]string{dir, filepath.Join(dir, "missing")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "private-plugin")}, paths)
+
+	t.Setenv(stubPluginEnv, "private")
+	reg := core.NewRegistry()
+	reg.SetLogger(core.NoopLogger{})
+	plugins, err := LoadPlugins(reg, []string{dir})
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	t.Cleanup(func() { _ = plugins[0].Close() })
+
+	_, ok := reg.GetUpdater("private")
+	assert.True(t, ok)
+}
@@ -2,7 +2,7 @@ package sources
 
 import "github.com/leocov-dev/packwiz-nxt/core"
 
-// RegisterAll registers every provider (CurseForge, GitHub, Modrinth) on reg. Library
+// RegisterAll registers every provider (CurseForge, Gitea, GitHub, GitHub Actions, Hangar, Maven, Modrinth, URL) on reg. Library
 // consumers building an isolated *core.Registry, instead of relying on
 // core.DefaultRegistry (which each provider's init() populates automatically), should
 // call this once on their own registry:
@@ -11,6 +11,11 @@ import "github.com/leocov-dev/packwiz-nxt/core"
 //	sources.RegisterAll(reg)
 func RegisterAll(reg *core.Registry) {
 	RegisterCurseforge(reg)
+	RegisterGitea(reg)
 	RegisterGithub(reg)
+	RegisterGithubActions(reg)
+	RegisterHangar(reg)
+	RegisterMaven(reg)
 	RegisterModrinth(reg)
+	RegisterUrl(reg)
 }
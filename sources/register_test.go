@@ -12,12 +12,19 @@ func TestRegisterAll(t *testing.T) {
 	reg := core.NewRegistry()
 	RegisterAll(reg)
 
-	for _, name := range []string{"curseforge", "github", "modrinth"} {
+	for _, name := range []string{"curseforge", "gitea", "github", "github-actions", "hangar", "maven", "modrinth"} {
 		updater, ok := reg.GetUpdater(name)
 		if assert.True(t, ok, "expected updater %q to be registered", name) {
 			assert.Equal(t, name, updater.GetName())
 		}
+		source, ok := reg.GetSource(name)
+		if assert.True(t, ok, "expected source %q to be registered", name) {
+			assert.Equal(t, name, source.GetName())
+		}
 	}
+
+	_, ok := reg.GetUpdater("url")
+	assert.True(t, ok, "expected the url updater to be registered")
 }
 
 func TestRegisterCurseforge(t *testing.T) {
@@ -28,6 +35,9 @@ func TestRegisterCurseforge(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "curseforge", updater.GetName())
 
+	_, ok = reg.GetSource("curseforge")
+	assert.True(t, ok)
+
 	_, ok = reg.GetUpdater("github")
 	assert.False(t, ok)
 }
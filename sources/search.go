@@ -0,0 +1,190 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	modrinthApi "codeberg.org/jmansfield/go-modrinth/modrinth"
+	"github.com/sahilm/fuzzy"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+const searchResultsPerProvider = 10
+
+// SearchAllProviders searches every Source registered on reg (core.DefaultRegistry if nil)
+// for query with SearchSources.
+func SearchAllProviders(reg *core.Registry, query string, req core.SourceRequest) ([]core.SourceProject, error) {
+	if reg == nil {
+		reg = core.DefaultRegistry
+	}
+	return SearchSources(reg.Sources(), query, req)
+}
+
+// SearchSources searches each of srcs for query, and returns the combined results ranked
+// by RankSearchResults. Sources that are unavailable (core.ErrSourceUnavailable), such as
+// CurseForge without an API key, are skipped unless none could be searched. If other
+// Sources fail, the results from the rest are still returned alongside the error.
+func SearchSources(srcs []core.Source, query string, req core.SourceRequest) ([]core.SourceProject, error) {
+	var results []core.SourceProject
+	var errs, unavailable []error
+	searched := 0
+
+	for _, src := range srcs {
+		found, err := src.Search(query, req)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", src.GetName(), err)
+			if errors.Is(err, core.ErrSourceUnavailable) {
+				unavailable = append(unavailable, err)
+			} else {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		searched++
+		results = append(results, found...)
+	}
+
+	if searched == 0 && len(errs) == 0 {
+		// Nothing could be searched at all; say why rather than reporting no results
+		return nil, errors.Join(unavailable...)
+	}
+
+	return RankSearchResults(query, results), errors.Join(errs...)
+}
+
+func searchModrinth(query string, mcVersions []string) ([]core.SourceProject, error) {
+	facets := make([]string, 0, len(mcVersions))
+	for _, v := range mcVersions {
+		facets = append(facets, "versions:"+v)
+	}
+
+	res, err := GetModrinthClient().Projects.Search(&modrinthApi.SearchOptions{
+		Limit:  searchResultsPerProvider,
+		Index:  "relevance",
+		Facets: [][]string{facets},
+		Query:  query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]core.SourceProject, 0, len(res.Hits))
+	for _, hit := range res.Hits {
+		if hit.ProjectID == nil {
+			continue
+		}
+		result := core.SourceProject{
+			Source: "modrinth",
+			ID:     *hit.ProjectID,
+		}
+		if hit.Slug != nil {
+			result.Slug = *hit.Slug
+		}
+		if hit.Title != nil {
+			result.Name = *hit.Title
+		}
+		if hit.Description != nil {
+			result.Summary = *hit.Description
+		}
+		if hit.Downloads != nil {
+			result.Downloads = uint64(*hit.Downloads)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func searchCurseforge(query string, mcVersions []string, classID uint32, categoryID uint32, loaderType ModloaderType) ([]core.SourceProject, error) {
+	// If there are more than one acceptable version, we shouldn't filter by game version at all (as we can't filter by multiple)
+	filterGameVersion := ""
+	if len(mcVersions) == 1 {
+		filterGameVersion = GetCurseforgeVersion(mcVersions[0])
+	}
+
+	mods, err := GetCurseforgeClient().GetSearch(query, "", classID, categoryID, filterGameVersion, loaderType)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]core.SourceProject, 0, len(mods))
+	for _, mod := range mods {
+		results = append(results, cfToSourceProject(cfSourceProject{Info: mod}))
+	}
+	return results, nil
+}
+
+type searchResultNames []core.SourceProject
+
+func (r searchResultNames) String(i int) string {
+	return r[i].Name
+}
+
+func (r searchResultNames) Len() int {
+	return len(r)
+}
+
+// normaliseSearchName lowercases s and strips separators, so "Just Enough Items",
+// "just-enough-items" and "justenoughitems" compare equal
+func normaliseSearchName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_', '.', '\'':
+			return -1
+		}
+		return r
+	}, strings.ToLower(s))
+}
+
+// RankSearchResults orders results from several providers into a single list: exact
+// name/slug matches first, then results ordered by fuzzy match score against the name,
+// then results that don't fuzzy match at all. Ties are broken by download count.
+func RankSearchResults(query string, results []core.SourceProject) []core.SourceProject {
+	const (
+		rankExact = iota
+		rankFuzzy
+		rankOther
+	)
+
+	type ranked struct {
+		result core.SourceProject
+		rank   int
+		score  int
+	}
+
+	rankedResults := make([]ranked, len(results))
+	for i, r := range results {
+		rankedResults[i] = ranked{result: r, rank: rankOther}
+	}
+
+	for _, m := range fuzzy.FindFrom(query, searchResultNames(results)) {
+		rankedResults[m.Index].rank = rankFuzzy
+		rankedResults[m.Index].score = m.Score
+	}
+
+	normalisedQuery := normaliseSearchName(query)
+	for i, r := range results {
+		if normaliseSearchName(r.Name) == normalisedQuery || normaliseSearchName(r.Slug) == normalisedQuery {
+			rankedResults[i].rank = rankExact
+		}
+	}
+
+	sort.SliceStable(rankedResults, func(i, j int) bool {
+		a, b := rankedResults[i], rankedResults[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if a.rank == rankFuzzy && a.score != b.score {
+			return a.score > b.score
+		}
+		return a.result.Downloads > b.result.Downloads
+	})
+
+	sorted := make([]core.SourceProject, len(rankedResults))
+	for i, r := range rankedResults {
+		sorted[i] = r.result
+	}
+	return sorted
+}
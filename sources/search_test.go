@@ -0,0 +1,110 @@
+package sources
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func searchResultNamesOf(results []core.SourceProject) []string {
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = r.Name
+	}
+	return names
+}
+
+func TestRankSearchResults(t *testing.T) {
+	t.Run("exact matches come first regardless of downloads", func(t *testing.T) {
+		results := []core.SourceProject{
+			{Name: "Sodium Extra", Slug: "sodium-extra", Downloads: 1000},
+			{Name: "Sodium", Slug: "sodium", Downloads: 10},
+		}
+		ranked := RankSearchResults("sodium", results)
+		assert.Equal(t, []string{"Sodium", "Sodium Extra"}, searchResultNamesOf(ranked))
+	})
+
+	t.Run("slug match counts as exact", func(t *testing.T) {
+		results := []core.SourceProject{
+			{Name: "Just Enough Resources", Slug: "jer", Downloads: 1000},
+			{Name: "Just Enough Items", Slug: "jei", Downloads: 10},
+		}
+		ranked := RankSearchResults("jei", results)
+		assert.Equal(t, "Just Enough Items", ranked[0].Name)
+	})
+
+	t.Run("fuzzy matches rank above non-matches", func(t *testing.T) {
+		results := []core.SourceProject{
+			{Name: "Unrelated", Downloads: 5000},
+			{Name: "Iris Shaders", Downloads: 10},
+		}
+		ranked := RankSearchResults("iris", results)
+		assert.Equal(t, []string{"Iris Shaders", "Unrelated"}, searchResultNamesOf(ranked))
+	})
+
+	t.Run("downloads break ties", func(t *testing.T) {
+		results := []core.SourceProject{
+			{Name: "Alpha", Downloads: 1},
+			{Name: "Beta", Downloads: 100},
+		}
+		ranked := RankSearchResults("zzz", results)
+		assert.Equal(t, []string{"Beta", "Alpha"}, searchResultNamesOf(ranked))
+	})
+}
+
+func TestSearchAllProviders(t *testing.T) {
+	pack := core.Pack{Versions: map[string]string{"minecraft": "1.20.1", "fabric": "0.15.0"}}
+	req := core.SourceRequest{Pack: pack}
+	reg := core.NewRegistry()
+	RegisterAll(reg)
+
+	t.Run("merges Modrinth and CurseForge results", func(t *testing.T) {
+		withMrClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/search", r.URL.Path)
+			_, _ = w.Write([]byte(`{"hits":[{"project_id":"AANobbMI","slug":"sodium","title":"Sodium","description":"fast","downloads":100}]}`))
+		}))
+		withCfClient(t, newTestHTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.True(t, strings.HasSuffix(r.URL.Path, "/v1/mods/search"))
+			assert.Equal(t, "sodium", r.URL.Query().Get("searchFilter"))
+			_, _ = w.Write([]byte(`{"data":[{"id":394468,"slug":"sodium-extra","name":"Sodium Extra","summary":"more","downloadCount":5000}]}`))
+		})))
+
+		results, err := SearchAllProviders(reg, "sodium", req)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, "modrinth", results[0].Source)
+		assert.Equal(t, "AANobbMI", results[0].ID)
+		assert.Equal(t, "curseforge", results[1].Source)
+		assert.Equal(t, "394468", results[1].ID)
+		assert.Equal(t, uint64(5000), results[1].Downloads)
+	})
+
+	t.Run("returns results from working providers when one fails", func(t *testing.T) {
+		withMrClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		withCfClient(t, newTestHTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"data":[{"id":1,"slug":"sodium","name":"Sodium"}]}`))
+		})))
+
+		results, err := SearchAllProviders(reg, "sodium", req)
+		assert.Error(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "curseforge", results[0].Source)
+	})
+
+	t.Run("skips CurseForge without an API key", func(t *testing.T) {
+		withMrClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"hits":[]}`))
+		}))
+
+		results, err := SearchAllProviders(reg, "sodium", req)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+
+	"github.com/spf13/viper"
 )
 
 // redirectTransport rewrites the scheme+host of every outgoing request to
@@ -43,3 +45,12 @@ func newTestHTTPClient(t *testing.T, handler http.Handler) *http.Client {
 		Transport: redirectTransport{target: target, base: http.DefaultTransport},
 	}
 }
+
+// withTestCache points the download cache, which providers hash files into, at a
+// temporary directory for the duration of the test
+func withTestCache(t *testing.T) {
+	t.Helper()
+	original := viper.GetString("cache.directory")
+	viper.Set("cache.directory", t.TempDir())
+	t.Cleanup(func() { viper.Set("cache.directory", original) })
+}
@@ -0,0 +1,288 @@
+package sources
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/unascribed/FlexVer/go/flexver"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+	"github.com/leocov-dev/packwiz-nxt/fileio"
+)
+
+// urlVersionPlaceholder is replaced with the version in URL templates
+const urlVersionPlaceholder = "{version}"
+
+// UrlTemplate configures the template mode of the url updater: the download URL is
+// Template with {version} replaced by the highest version (by FlexVer) found at
+// VersionsURL. VersionsURL is either a JSON document, in which case VersionPath is the
+// dot-separated path to a version string or array of them (arrays along the path are
+// searched element by element, e.g. "versions.id"), or a page such as a directory
+// listing, in which case the first capture group of every match of VersionRegex is a
+// version. VersionRegex defaults to matching the file name of Template.
+type UrlTemplate struct {
+	Template     string
+	VersionsURL  string
+	VersionPath  string
+	VersionRegex string
+}
+
+// urlValidators are the response headers used to detect changes to a stable URL
+type urlValidators struct {
+	ETag          string
+	LastModified  string
+	ContentLength int64
+}
+
+// urlFetchValidators sends a HEAD request for rawURL, returning its validators. Servers
+// that don't support HEAD requests return no validators, so the file is re-hashed instead.
+func urlFetchValidators(rawURL string) (urlValidators, error) {
+	resp, err := core.HeadWithUA(rawURL)
+	if err != nil {
+		return urlValidators{}, fmt.Errorf("failed to check %s: %w", rawURL, err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented {
+		return urlValidators{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return urlValidators{}, fmt.Errorf("failed to check %s: invalid status code %v", rawURL, resp.StatusCode)
+	}
+
+	validators := urlValidators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	if length, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err == nil {
+		validators.ContentLength = length
+	}
+	return validators, nil
+}
+
+// urlHashFile downloads rawURL into the download cache, returning its sha256 hash
+func urlHashFile(rawURL string) (string, error) {
+	resp, err := core.GetWithUA(rawURL, "application/octet-stream")
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: invalid status code %v", rawURL, resp.StatusCode)
+	}
+	return fileio.StoreInCache(resp.Body)
+}
+
+// urlFileName returns the unescaped last path segment of rawURL
+func urlFileName(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", fmt.Errorf("unsupported URL scheme: %s", u.Scheme)
+	}
+	fileName := path.Base(u.Path)
+	if fileName == "/" || fileName == "." {
+		return "", fmt.Errorf("URL does not point to a file: %s", rawURL)
+	}
+	return fileName, nil
+}
+
+// Url returns the download URL for version
+func (t UrlTemplate) Url(version string) string {
+	return strings.ReplaceAll(t.Template, urlVersionPlaceholder, version)
+}
+
+func (t UrlTemplate) versionRegex() (*regexp.Regexp, error) {
+	if t.VersionRegex != "" {
+		expr, err := regexp.Compile(t.VersionRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version regex %q: %w", t.VersionRegex, err)
+		}
+		if expr.NumSubexp() < 1 {
+			return nil, fmt.Errorf("version regex %q must have a capture group", t.VersionRegex)
+		}
+		return expr, nil
+	}
+
+	fileName := path.Base(t.Template)
+	before, after, ok := strings.Cut(fileName, urlVersionPlaceholder)
+	if !ok {
+		return nil, errors.New("a version regex is required when the template's file name doesn't contain " + urlVersionPlaceholder)
+	}
+	return regexp.MustCompile(regexp.QuoteMeta(before) + `([^"'/<>\s]+?)` + regexp.QuoteMeta(after)), nil
+}
+
+// FindLatestVersion returns the highest version listed at t.VersionsURL
+func (t UrlTemplate) FindLatestVersion() (string, error) {
+	versions, err := t.listVersions()
+	if err != nil {
+		return "", err
+	}
+	return urlHighestVersion(versions), nil
+}
+
+// listVersions returns the versions listed at t.VersionsURL, erroring if there are none
+func (t UrlTemplate) listVersions() ([]string, error) {
+	if !strings.Contains(t.Template, urlVersionPlaceholder) {
+		return nil, fmt.Errorf("URL template %q doesn't contain %s", t.Template, urlVersionPlaceholder)
+	}
+	if t.VersionsURL == "" {
+		return nil, errors.New("a version discovery URL is required")
+	}
+
+	resp, err := core.GetWithUA(t.VersionsURL, "application/json, text/html;q=0.9, */*;q=0.8")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", t.VersionsURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: invalid status code %v", t.VersionsURL, resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", t.VersionsURL, err)
+	}
+
+	var found []string
+	if t.VersionPath != "" {
+		var doc any
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", t.VersionsURL, err)
+		}
+		found = urlJsonStrings(doc, strings.Split(t.VersionPath, "."))
+	} else {
+		expr, err := t.versionRegex()
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range expr.FindAllSubmatch(body, -1) {
+			found = append(found, string(match[1]))
+		}
+	}
+
+	var versions []string
+	for _, v := range found {
+		if v != "" {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no versions found at %s", t.VersionsURL)
+	}
+	return versions, nil
+}
+
+// urlHighestVersion returns the highest of versions by FlexVer, or "" if there are none
+func urlHighestVersion(versions []string) string {
+	latest := ""
+	for _, v := range versions {
+		if latest == "" || flexver.Less(latest, v) {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// urlJsonStrings returns the strings (or numbers) at keys in doc, searching arrays
+// element by element
+func urlJsonStrings(doc any, keys []string) []string {
+	switch v := doc.(type) {
+	case []any:
+		var out []string
+		for _, elem := range v {
+			out = append(out, urlJsonStrings(elem, keys)...)
+		}
+		return out
+	case map[string]any:
+		if len(keys) == 0 {
+			return nil
+		}
+		return urlJsonStrings(v[keys[0]], keys[1:])
+	case string:
+		if len(keys) == 0 {
+			return []string{v}
+		}
+	case float64:
+		if len(keys) == 0 {
+			return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+		}
+	}
+	return nil
+}
+
+// UrlNewMod creates a Mod for the file at rawURL that can be updated by the url updater.
+// If template.Template is set, the latest version is installed from the template instead
+// (and rawURL is ignored). Otherwise rawURL is treated as a stable "latest" URL, updated
+// whenever its ETag, Last-Modified or Content-Length headers change.
+func UrlNewMod(name string, rawURL string, template UrlTemplate, modType string) (*core.Mod, error) {
+	data := urlUpdateData{
+		Template:     template.Template,
+		VersionsURL:  template.VersionsURL,
+		VersionPath:  template.VersionPath,
+		VersionRegex: template.VersionRegex,
+	}
+
+	if template.Template != "" {
+		version, err := template.FindLatestVersion()
+		if err != nil {
+			return nil, err
+		}
+		data.Version = version
+		rawURL = template.Url(version)
+	} else {
+		validators, err := urlFetchValidators(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		data.setValidators(validators)
+	}
+
+	fileName, err := urlFileName(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = strings.TrimSuffix(fileName, path.Ext(fileName))
+	}
+
+	hash, err := urlHashFile(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	updateMap := make(core.ModUpdate)
+	updateMap["url"], err = data.ToMap()
+	if err != nil {
+		return nil, err
+	}
+
+	mod := core.NewMod(
+		core.SlugifyName(name),
+		name,
+		fileName,
+		core.UniversalSide,
+		modType,
+		"",
+		false,
+		false,
+		updateMap,
+		core.ModDownload{
+			URL:        rawURL,
+			HashFormat: "sha256",
+			Hash:       hash,
+		},
+		nil,
+	)
+	mod.Version = data.Version
+	return mod, nil
+}
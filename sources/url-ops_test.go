@@ -0,0 +1,106 @@
+package sources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestUrlServer serves mod-1.0.jar/mod-1.1.jar with a directory listing and JSON
+// version list, and a stable latest.jar whose contents and ETag are *latest
+func newTestUrlServer(t *testing.T, latest *string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/":
+			_, _ = w.Write([]byte(`<a href="mod-1.0.jar">mod-1.0.jar</a> <a href="mod-1.1.jar">mod-1.1.jar</a> <a href="mod-1.1-sources.zip">x</a>`))
+		case "/versions.json":
+			_, _ = w.Write([]byte(`{"versions":[{"id":"1.0"},{"id":"1.1"}]}`))
+		case "/files/mod-1.0.jar", "/files/mod-1.1.jar":
+			_, _ = w.Write([]byte(r.URL.Path))
+		case "/latest.jar":
+			w.Header().Set("ETag", `"`+*latest+`"`)
+			_, _ = w.Write([]byte(*latest))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestUrlTemplate_FindLatestVersion(t *testing.T) {
+	latest := "a"
+	server := newTestUrlServer(t, &latest)
+
+	t.Run("directory listing", func(t *testing.T) {
+		template := UrlTemplate{Template: server.URL + "/files/mod-{version}.jar", VersionsURL: server.URL + "/files/"}
+		version, err := template.FindLatestVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "1.1", version)
+		assert.Equal(t, server.URL+"/files/mod-1.1.jar", template.Url(version))
+	})
+
+	t.Run("version regex", func(t *testing.T) {
+		template := UrlTemplate{Template: server.URL + "/files/mod-{version}.jar", VersionsURL: server.URL + "/files/", VersionRegex: `mod-(1\.0)\.jar`}
+		version, err := template.FindLatestVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "1.0", version)
+
+		template.VersionRegex = `mod-1\.0\.jar`
+		_, err = template.FindLatestVersion()
+		assert.Error(t, err, "no capture group")
+	})
+
+	t.Run("JSON path", func(t *testing.T) {
+		template := UrlTemplate{Template: server.URL + "/files/mod-{version}.jar", VersionsURL: server.URL + "/versions.json", VersionPath: "versions.id"}
+		version, err := template.FindLatestVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "1.1", version)
+
+		template.VersionPath = "missing"
+		_, err = template.FindLatestVersion()
+		assert.Error(t, err)
+	})
+
+	t.Run("template without placeholder", func(t *testing.T) {
+		_, err := UrlTemplate{Template: server.URL + "/files/mod.jar", VersionsURL: server.URL + "/files/"}.FindLatestVersion()
+		assert.Error(t, err)
+	})
+}
+
+func TestUrlNewMod(t *testing.T) {
+	withTestCache(t)
+	latest := "build-1"
+	server := newTestUrlServer(t, &latest)
+
+	t.Run("stable URL", func(t *testing.T) {
+		mod, err := UrlNewMod("", server.URL+"/latest.jar", UrlTemplate{}, "mods")
+		require.NoError(t, err)
+		assert.Equal(t, "latest", mod.Name)
+		assert.Equal(t, "latest.jar", mod.FileName)
+		assert.Equal(t, server.URL+"/latest.jar", mod.Download.URL)
+		assert.Equal(t, `"build-1"`, mod.Update["url"]["etag"])
+		assert.Equal(t, int64(len("build-1")), mod.Update["url"]["content-length"])
+		assert.Empty(t, mod.Version)
+	})
+
+	t.Run("template", func(t *testing.T) {
+		template := UrlTemplate{Template: server.URL + "/files/mod-{version}.jar", VersionsURL: server.URL + "/files/"}
+		mod, err := UrlNewMod("My Mod", "", template, "mods")
+		require.NoError(t, err)
+		assert.Equal(t, "my-mod", mod.Slug)
+		assert.Equal(t, "mod-1.1.jar", mod.FileName)
+		assert.Equal(t, "1.1", mod.Update["url"]["version"])
+		assert.Equal(t, "1.1", mod.Version)
+		assert.NotContains(t, mod.Update["url"], "etag")
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		_, err := UrlNewMod("", "ftp://example.com/mod.jar", UrlTemplate{}, "mods")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,219 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/unascribed/FlexVer/go/flexver"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func init() {
+	RegisterUrl(core.DefaultRegistry)
+}
+
+// RegisterUrl registers the url Updater on reg. Library consumers building an isolated
+// *core.Registry (instead of relying on core.DefaultRegistry) should call this - or
+// sources.RegisterAll - explicitly.
+func RegisterUrl(reg *core.Registry) {
+	reg.AddUpdater(urlUpdater{})
+}
+
+// urlUpdateData is the update data of the url updater. With a Template, it uses the
+// template mode described by UrlTemplate; otherwise the mod's download URL is a stable
+// "latest" URL, re-checked with the recorded validators.
+type urlUpdateData struct {
+	ETag          string `mapstructure:"etag,omitempty"`
+	LastModified  string `mapstructure:"last-modified,omitempty"`
+	ContentLength int64  `mapstructure:"content-length,omitempty"`
+
+	Template     string `mapstructure:"template,omitempty"`
+	VersionsURL  string `mapstructure:"versions-url,omitempty"`
+	VersionPath  string `mapstructure:"version-path,omitempty"`
+	VersionRegex string `mapstructure:"version-regex,omitempty"`
+	Version      string `mapstructure:"version,omitempty"`
+}
+
+func (u urlUpdateData) ToMap() (map[string]interface{}, error) {
+	newMap := make(map[string]interface{})
+	err := mapstructure.Decode(u, &newMap)
+	return newMap, err
+}
+
+func (u urlUpdateData) template() UrlTemplate {
+	return UrlTemplate{
+		Template:     u.Template,
+		VersionsURL:  u.VersionsURL,
+		VersionPath:  u.VersionPath,
+		VersionRegex: u.VersionRegex,
+	}
+}
+
+func (u *urlUpdateData) setValidators(v urlValidators) {
+	u.ETag = v.ETag
+	u.LastModified = v.LastModified
+	u.ContentLength = v.ContentLength
+}
+
+// changed returns true if v differs from the recorded validators, or if there are none
+// to compare
+func (u urlUpdateData) changed(v urlValidators) bool {
+	if u.ETag == "" && u.LastModified == "" && u.ContentLength == 0 {
+		return true
+	}
+	return u.ETag != v.ETag || u.LastModified != v.LastModified || u.ContentLength != v.ContentLength
+}
+
+type urlUpdater struct{}
+
+func (u urlUpdater) GetName() string {
+	return "url"
+}
+
+func (u urlUpdater) ParseUpdate(updateUnparsed map[string]interface{}) (interface{}, error) {
+	var updateData urlUpdateData
+	err := mapstructure.Decode(updateUnparsed, &updateData)
+	return updateData, err
+}
+
+type urlCachedStateStore struct {
+	URL        string
+	Hash       string
+	Version    string
+	Validators urlValidators
+}
+
+func (u urlUpdater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateCheck, error) {
+	results := make([]core.UpdateCheck, len(mods))
+	// Only used to skip Minecraft versions in version numbers, so packs without one are still updated
+	mcVersions, _ := pack.GetSupportedMCVersions()
+
+	for i, mod := range mods {
+		var data urlUpdateData
+		err := mod.DecodeNamedModSourceData("url", &data)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: errors.New("failed to parse update metadata")}
+			continue
+		}
+
+		if data.Template != "" {
+			results[i] = checkUrlTemplateUpdate(mod, data, mcVersions)
+		} else {
+			results[i] = checkUrlLatestUpdate(mod, data)
+			// A stable URL has no versions to compare against the constraint
+			results[i].ConstraintIgnored = mod.VersionConstraint != "" && results[i].Error == nil
+		}
+	}
+
+	return results, nil
+}
+
+func checkUrlTemplateUpdate(mod *core.Mod, data urlUpdateData, mcVersions []string) core.UpdateCheck {
+	template := data.template()
+	versions, err := template.listVersions()
+	if err != nil {
+		return core.UpdateCheck{Error: err}
+	}
+	accepted, err := core.FilterAcceptedVersions(mod, mcVersions, versions, func(v string) string { return v })
+	if err != nil {
+		return core.UpdateCheck{Error: err}
+	}
+	version := urlHighestVersion(accepted)
+	heldBack := ""
+	if latest := urlHighestVersion(versions); latest != version && flexver.Compare(latest, data.Version) > 0 {
+		heldBack = latest
+	}
+
+	// The installed version may be newer than the latest allowed, e.g. if it was added
+	// before the constraint; that isn't an update
+	if version == "" || flexver.Compare(version, data.Version) <= 0 {
+		return core.UpdateCheck{UpdateAvailable: false, HeldBack: heldBack}
+	}
+
+	newURL := template.Url(version)
+	fileName, err := urlFileName(newURL)
+	if err != nil {
+		return core.UpdateCheck{Error: err}
+	}
+	hash, err := urlHashFile(newURL)
+	if err != nil {
+		return core.UpdateCheck{Error: err}
+	}
+
+	return core.UpdateCheck{
+		UpdateAvailable: true,
+		UpdateString:    mod.DescribeUpdate(version, fileName),
+		HeldBack:        heldBack,
+		CachedState:     urlCachedStateStore{URL: newURL, Hash: hash, Version: version},
+	}
+}
+
+// checkUrlLatestUpdate re-hashes the download URL if its validators changed. The hash
+// decides whether there is an update, as validators can change without the file changing
+// (e.g. a re-upload); in that case the new validators are stored on the mod, so the next
+// check doesn't download it again.
+func checkUrlLatestUpdate(mod *core.Mod, data urlUpdateData) core.UpdateCheck {
+	validators, err := urlFetchValidators(mod.Download.URL)
+	if err != nil {
+		return core.UpdateCheck{Error: err}
+	}
+	if !data.changed(validators) {
+		return core.UpdateCheck{UpdateAvailable: false}
+	}
+
+	hash, err := urlHashFile(mod.Download.URL)
+	if err != nil {
+		return core.UpdateCheck{Error: err}
+	}
+	if mod.Download.HashFormat == "sha256" && hash == mod.Download.Hash {
+		data.setValidators(validators)
+		mod.Update["url"], err = data.ToMap()
+		if err != nil {
+			return core.UpdateCheck{Error: err}
+		}
+		return core.UpdateCheck{UpdateAvailable: false}
+	}
+
+	return core.UpdateCheck{
+		UpdateAvailable: true,
+		UpdateString:    fmt.Sprintf("%s (changed at %s)", mod.FileName, mod.Download.URL),
+		CachedState:     urlCachedStateStore{URL: mod.Download.URL, Hash: hash, Validators: validators},
+	}
+}
+
+func (u urlUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
+	for i, mod := range mods {
+		modState := cachedState[i].(urlCachedStateStore)
+
+		fileName, err := urlFileName(modState.URL)
+		if err != nil {
+			return err
+		}
+
+		mod.FileName = fileName
+		mod.Download = core.ModDownload{
+			URL:        modState.URL,
+			HashFormat: "sha256",
+			Hash:       modState.Hash,
+		}
+
+		var data urlUpdateData
+		if err := mod.DecodeNamedModSourceData("url", &data); err != nil {
+			return err
+		}
+		if data.Template != "" {
+			data.Version = modState.Version
+			mod.Version = modState.Version
+		} else {
+			data.setValidators(modState.Validators)
+		}
+		mod.Update["url"], err = data.ToMap()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
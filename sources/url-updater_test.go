@@ -0,0 +1,93 @@
+package sources
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leocov-dev/packwiz-nxt/core"
+)
+
+func TestUrlUpdater(t *testing.T) {
+	withTestCache(t)
+	latest := "build-1"
+	server := newTestUrlServer(t, &latest)
+	var downloads atomic.Int32
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/latest.jar" {
+			downloads.Add(1)
+		}
+		handler.ServeHTTP(w, r)
+	})
+
+	stable, err := UrlNewMod("", server.URL+"/latest.jar", UrlTemplate{}, "mods")
+	require.NoError(t, err)
+	versioned, err := UrlNewMod("", "", UrlTemplate{Template: server.URL + "/files/mod-{version}.jar", VersionsURL: server.URL + "/files/"}, "mods")
+	require.NoError(t, err)
+	versioned.Update["url"]["version"] = "1.0"
+	versioned.Version = "1.0"
+	versioned.FileName = "mod-1.0.jar"
+
+	results, err := urlUpdater{}.CheckUpdate([]*core.Mod{stable, versioned}, core.Pack{})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.False(t, results[0].UpdateAvailable)
+	assert.NoError(t, results[0].Error)
+	require.True(t, results[1].UpdateAvailable)
+	assert.Equal(t, "1.0 -> 1.1", results[1].UpdateString)
+
+	require.NoError(t, urlUpdater{}.DoUpdate([]*core.Mod{versioned}, []interface{}{results[1].CachedState}))
+	assert.Equal(t, "1.1", versioned.Update["url"]["version"])
+	assert.Equal(t, "1.1", versioned.Version)
+	assert.Equal(t, server.URL+"/files/mod-1.1.jar", versioned.Download.URL)
+
+	t.Run("version constraints", func(t *testing.T) {
+		constrained, err := UrlNewMod("", "", UrlTemplate{Template: server.URL + "/files/mod-{version}.jar", VersionsURL: server.URL + "/files/"}, "mods")
+		require.NoError(t, err)
+		constrained.Update["url"]["version"] = "1.0"
+		constrained.VersionConstraint = "<1.1"
+		stable.VersionConstraint = "<2"
+
+		results, err := urlUpdater{}.CheckUpdate([]*core.Mod{constrained, stable}, core.Pack{})
+		require.NoError(t, err)
+		assert.False(t, results[0].UpdateAvailable)
+		assert.Equal(t, "1.1", results[0].HeldBack)
+		assert.False(t, results[0].ConstraintIgnored)
+		assert.True(t, results[1].ConstraintIgnored, "stable URLs have no versions to constrain")
+		stable.VersionConstraint = ""
+	})
+
+	t.Run("stable URL changes", func(t *testing.T) {
+		latest = "build-2"
+		oldHash := stable.Download.Hash
+
+		results, err := urlUpdater{}.CheckUpdate([]*core.Mod{stable}, core.Pack{})
+		require.NoError(t, err)
+		require.True(t, results[0].UpdateAvailable)
+
+		require.NoError(t, urlUpdater{}.DoUpdate([]*core.Mod{stable}, []interface{}{results[0].CachedState}))
+		assert.NotEqual(t, oldHash, stable.Download.Hash)
+		assert.Equal(t, `"build-2"`, stable.Update["url"]["etag"])
+		assert.Equal(t, "latest.jar", stable.FileName)
+	})
+
+	t.Run("changed validators with the same contents", func(t *testing.T) {
+		stable.Update["url"]["etag"] = `"stale"`
+		downloads.Store(0)
+		results, err := urlUpdater{}.CheckUpdate([]*core.Mod{stable}, core.Pack{})
+		require.NoError(t, err)
+		assert.False(t, results[0].UpdateAvailable)
+		assert.Equal(t, int32(1), downloads.Load())
+		assert.Equal(t, `"build-2"`, stable.Update["url"]["etag"])
+
+		// The new validators were recorded, so the next check doesn't re-hash
+		results, err = urlUpdater{}.CheckUpdate([]*core.Mod{stable}, core.Pack{})
+		require.NoError(t, err)
+		assert.False(t, results[0].UpdateAvailable)
+		assert.Equal(t, int32(1), downloads.Load())
+	})
+}